@@ -0,0 +1,19 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package main
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/changaolee/skeleton/internal/pump"
+)
+
+func main() {
+	rand.Seed(time.Now().UTC().UnixNano())
+
+	pump.NewApp("skt-pump").Run()
+}
@@ -24,28 +24,152 @@ USE `skeleton`;
 /*!40111 SET @OLD_SQL_NOTES = @@SQL_NOTES, SQL_NOTES = 0 */;
 
 --
--- Table structure for table `user`
+-- Table structure for table `audit`
 --
 
-DROP TABLE IF EXISTS `user`;
+DROP TABLE IF EXISTS `audit`;
 /*!40101 SET @saved_cs_client = @@character_set_client */;
 /*!40101 SET character_set_client = utf8 */;
-CREATE TABLE `user`
+CREATE TABLE `audit`
+(
+    `id`         bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `timestamp`  bigint(20)          NOT NULL,
+    `username`   varchar(255)        NOT NULL,
+    `effect`     varchar(16)         NOT NULL,
+    `conclusion` varchar(1024)       NOT NULL DEFAULT '',
+    `request`    longtext                     DEFAULT NULL,
+    `policies`   longtext                     DEFAULT NULL,
+    `deciders`   longtext                     DEFAULT NULL,
+    `expireAt`   timestamp           NULL     DEFAULT NULL,
+    `createdAt`  timestamp           NOT NULL DEFAULT current_timestamp(),
+    PRIMARY KEY (`id`),
+    KEY `index_username_timestamp` (`username`, `timestamp`),
+    KEY `index_effect` (`effect`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `audit`
+--
+
+LOCK TABLES `audit` WRITE;
+/*!40000 ALTER TABLE `audit`
+    DISABLE KEYS */;
+/*!40000 ALTER TABLE `audit`
+    ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `policy`
+--
+
+DROP TABLE IF EXISTS `policy`;
+/*!40101 SET @saved_cs_client = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `policy`
+(
+    `id`           bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `instanceID`   varchar(32)                  DEFAULT NULL,
+    `name`         varchar(45)         NOT NULL,
+    `username`     varchar(255)        NOT NULL,
+    `policyShadow` longtext                     DEFAULT NULL,
+    `extendShadow` longtext                     DEFAULT NULL,
+    `createdAt`    timestamp           NOT NULL DEFAULT current_timestamp(),
+    `updatedAt`    timestamp           NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `index_username_name` (`username`, `name`),
+    UNIQUE KEY `index_instanceID` (`instanceID`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `policy`
+--
+
+LOCK TABLES `policy` WRITE;
+/*!40000 ALTER TABLE `policy`
+    DISABLE KEYS */;
+/*!40000 ALTER TABLE `policy`
+    ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `secret`
+--
+
+DROP TABLE IF EXISTS `secret`;
+/*!40101 SET @saved_cs_client = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `secret`
 (
     `id`           bigint(20) unsigned NOT NULL AUTO_INCREMENT,
     `instanceID`   varchar(32)                  DEFAULT NULL,
     `name`         varchar(45)         NOT NULL,
-    `status`       int(1)                       DEFAULT 1 COMMENT '1:可用，0:不可用',
-    `nickname`     varchar(30)         NOT NULL,
-    `password`     varchar(255)        NOT NULL,
-    `email`        varchar(256)        NOT NULL,
-    `phone`        varchar(20)                  DEFAULT NULL,
-    `isAdmin`      tinyint(1) unsigned NOT NULL DEFAULT 0 COMMENT '1:管理员，0:非管理员',
+    `username`     varchar(255)        NOT NULL,
+    `secretID`     varchar(36)         NOT NULL,
+    `secretKey`    varchar(255)        NOT NULL,
+    `expires`      bigint(64) unsigned NOT NULL DEFAULT 0 COMMENT '过期时间(Unix 时间戳)，0 表示永不过期',
+    `description`  varchar(255)        NOT NULL DEFAULT '',
     `extendShadow` longtext                     DEFAULT NULL,
-    `loginAt`      timestamp           NULL     DEFAULT NULL COMMENT '最近登录时间',
     `createdAt`    timestamp           NOT NULL DEFAULT current_timestamp(),
     `updatedAt`    timestamp           NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
     PRIMARY KEY (`id`),
+    UNIQUE KEY `index_username_name` (`username`, `name`),
+    UNIQUE KEY `index_secretID` (`secretID`),
+    UNIQUE KEY `index_instanceID` (`instanceID`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `secret`
+--
+
+LOCK TABLES `secret` WRITE;
+/*!40000 ALTER TABLE `secret`
+    DISABLE KEYS */;
+/*!40000 ALTER TABLE `secret`
+    ENABLE KEYS */;
+UNLOCK TABLES;
+
+--
+-- Table structure for table `user`
+--
+
+DROP TABLE IF EXISTS `user`;
+/*!40101 SET @saved_cs_client = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `user`
+(
+    `id`                bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `instanceID`        varchar(32)                  DEFAULT NULL,
+    `name`              varchar(45)         NOT NULL,
+    `status`            int(1)                       DEFAULT 1 COMMENT '1:可用，0:不可用，2:邮箱未验证',
+    `nickname`          varchar(30)         NOT NULL,
+    `password`          varchar(255)        NOT NULL,
+    `email`             varchar(256)        NOT NULL,
+    `phone`             varchar(20)                  DEFAULT NULL,
+    `isAdmin`           tinyint(1) unsigned NOT NULL DEFAULT 0 COMMENT '1:管理员，0:非管理员',
+    `extendShadow`      longtext                     DEFAULT NULL,
+    `loginAt`           timestamp           NULL     DEFAULT NULL COMMENT '最近登录时间',
+    `loginIP`           varchar(64)                  DEFAULT NULL COMMENT '最近登录来源 IP',
+    `failedLoginCount`  int(10) unsigned    NOT NULL DEFAULT 0 COMMENT '连续登录失败次数',
+    `lockedUntil`       timestamp           NULL     DEFAULT NULL COMMENT '账户锁定截止时间',
+    `mfaEnabled`        tinyint(1) unsigned NOT NULL DEFAULT 0 COMMENT '1:已开启两步验证，0:未开启',
+    `totpSecret`        varchar(64)                  DEFAULT NULL COMMENT 'TOTP 密钥',
+    `totpLastStep`      bigint(20)          NOT NULL DEFAULT 0 COMMENT '最近使用的 TOTP 时间步，防止验证码重放',
+    `recoveryCodes`     text                         DEFAULT NULL COMMENT '加密后的恢复码',
+    `passwordChangedAt` timestamp           NULL     DEFAULT NULL COMMENT '最近修改密码时间',
+    `passwordHistory`   text                         DEFAULT NULL COMMENT '加密后的历史密码，按时间倒序排列',
+    `emailVerifiedAt`   timestamp           NULL     DEFAULT NULL COMMENT '邮箱验证时间',
+    `createdAt`         timestamp           NOT NULL DEFAULT current_timestamp(),
+    `updatedAt`         timestamp           NOT NULL DEFAULT current_timestamp() ON UPDATE current_timestamp(),
+    PRIMARY KEY (`id`),
     UNIQUE KEY `index_name` (`name`),
     UNIQUE KEY `index_instanceID` (`instanceID`)
 ) ENGINE = InnoDB
@@ -64,6 +188,39 @@ LOCK TABLES `user` WRITE;
 /*!40000 ALTER TABLE `user`
     ENABLE KEYS */;
 UNLOCK TABLES;
+
+--
+-- Table structure for table `user_identity`
+--
+
+DROP TABLE IF EXISTS `user_identity`;
+/*!40101 SET @saved_cs_client = @@character_set_client */;
+/*!40101 SET character_set_client = utf8 */;
+CREATE TABLE `user_identity`
+(
+    `id`        bigint(20) unsigned NOT NULL AUTO_INCREMENT,
+    `username`  varchar(45)         NOT NULL,
+    `provider`  varchar(64)         NOT NULL COMMENT '外部身份提供方名称',
+    `subject`   varchar(255)        NOT NULL COMMENT '用户在外部身份提供方中的唯一标识',
+    `createdAt` timestamp           NOT NULL DEFAULT current_timestamp(),
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `index_provider_subject` (`provider`, `subject`),
+    KEY `index_username` (`username`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_general_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
+--
+-- Dumping data for table `user_identity`
+--
+
+LOCK TABLES `user_identity` WRITE;
+/*!40000 ALTER TABLE `user_identity`
+    DISABLE KEYS */;
+/*!40000 ALTER TABLE `user_identity`
+    ENABLE KEYS */;
+UNLOCK TABLES;
 /*!40103 SET TIME_ZONE = @OLD_TIME_ZONE */;
 
 /*!40101 SET SQL_MODE = @OLD_SQL_MODE */;
@@ -0,0 +1,58 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package apiserver
+
+import (
+	"crypto/rand"
+
+	"github.com/changaolee/skeleton/internal/apiserver/biz"
+	"github.com/changaolee/skeleton/internal/apiserver/mail"
+	"github.com/changaolee/skeleton/internal/apiserver/options"
+	"github.com/changaolee/skeleton/internal/apiserver/verification"
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+// newMailer 基于邮件选项创建发送邮件的 Mailer.
+func newMailer(opts *options.MailOptions) (mail.Mailer, error) {
+	switch opts.Driver {
+	case "smtp":
+		return mail.NewSMTPMailer(opts), nil
+	case "file":
+		return mail.NewFileMailer(opts.From, opts.Dir)
+	default:
+		return mail.NewLogMailer(opts.From), nil
+	}
+}
+
+// newAccountPolicy 基于账户选项和邮件选项创建邮箱验证和找回密码的策略.
+func newAccountPolicy(opts *options.AccountOptions, mailOpts *options.MailOptions) (biz.AccountPolicy, error) {
+	templates, err := mail.NewTemplates(mailOpts.TemplateDir)
+	if err != nil {
+		return biz.AccountPolicy{}, err
+	}
+
+	key := []byte(opts.TokenKey)
+	if len(key) == 0 {
+		log.Warnf("--account.token-key is not set, use a random key: " +
+			"email verification and password reset tokens are invalidated on restart and " +
+			"cannot be used across replicas")
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return biz.AccountPolicy{}, err
+		}
+	}
+
+	return biz.AccountPolicy{
+		Signer:               verification.NewSigner(key),
+		Templates:            templates,
+		EmailVerificationTTL: opts.EmailVerificationTTL,
+		PasswordResetTTL:     opts.PasswordResetTTL,
+		ResendInterval:       opts.ResendInterval,
+		RequireVerifiedEmail: opts.RequireVerifiedEmail,
+		VerifyEmailURL:       opts.VerifyEmailURL,
+		ResetPasswordURL:     opts.ResetPasswordURL,
+	}, nil
+}
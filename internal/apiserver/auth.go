@@ -6,21 +6,33 @@
 package apiserver
 
 import (
-	"context"
 	"encoding/base64"
+	"encoding/json"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	jwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/changaolee/skeleton/internal/pkg/model/user"
 	"github.com/gin-gonic/gin"
-	"github.com/spf13/viper"
+	gojwt "github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
 
+	"github.com/changaolee/skeleton/internal/apiserver/biz"
+	"github.com/changaolee/skeleton/internal/apiserver/options"
+	"github.com/changaolee/skeleton/internal/apiserver/password"
 	"github.com/changaolee/skeleton/internal/apiserver/store"
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/internal/pkg/jwks"
 	"github.com/changaolee/skeleton/internal/pkg/middleware"
 	"github.com/changaolee/skeleton/internal/pkg/middleware/auth"
+	genoptions "github.com/changaolee/skeleton/internal/pkg/options"
+	"github.com/changaolee/skeleton/internal/pkg/revocation"
+	"github.com/changaolee/skeleton/pkg/errors"
 	"github.com/changaolee/skeleton/pkg/log"
+	"github.com/changaolee/skeleton/pkg/util/iputil"
 )
 
 const (
@@ -33,45 +45,93 @@ const (
 
 type loginInfo struct {
 	Username string `form:"username" json:"username" binding:"required,username"`
-	Password string `form:"password" json:"password" binding:"required,password"`
+	Password string `form:"password" json:"password" binding:"required"`
 }
 
-func newAutoAuth() middleware.AuthStrategy {
+type changeExpiredPasswordInfo struct {
+	Username    string `json:"username"    binding:"required,username"`
+	Password    string `json:"password"    binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required"`
+}
+
+func newAutoAuth(jwtAuth *reloadableJWTAuth) middleware.AuthStrategy {
 	return auth.NewAutoStrategy(
 		newBasicAuth().(auth.BasicStrategy),
-		newJWTAuth().(auth.JWTStrategy),
+		jwtAuth.load().strategy,
 	)
 }
 
 func newBasicAuth() middleware.AuthStrategy {
-	return auth.NewBasicStrategy(func(username string, password string) bool {
-		// 从数据库中拉取用户信息
-		user, err := store.Store().Users().Get(context.TODO(), username)
+	return auth.NewBasicStrategy(func(c *gin.Context, username string, password string) error {
+		users := biz.New(store.Store()).Users()
+		u, err := users.Authenticate(c, username, password, iputil.RemoteIP(c.Request))
 		if err != nil {
-			return false
+			return err
 		}
-
-		// 验证用户密码
-		if err := user.Compare(password); err != nil {
-			return false
+		// Basic 认证无法提供验证码，需要两步验证的用户只能通过 /login 获取 token
+		if users.RequiresMFA(u) {
+			return errors.WithCode(code.ErrMFARequired, "user %s must login with mfa code", username)
 		}
 
-		user.LoginAt = time.Now()
-		_ = store.Store().Users().Update(context.TODO(), user)
-
-		return true
+		return nil
 	})
 }
 
-func newJWTAuth() middleware.AuthStrategy {
+// newLoginPolicy 基于登录选项创建登录失败锁定账户的策略.
+func newLoginPolicy(opts *options.LoginOptions) biz.LoginPolicy {
+	return biz.LoginPolicy{
+		MaxFailedAttempts: opts.MaxFailedAttempts,
+		LockoutDuration:   opts.LockoutDuration,
+	}
+}
+
+// newMFAPolicy 基于两步验证选项创建两步验证策略.
+func newMFAPolicy(opts *options.MFAOptions) biz.MFAPolicy {
+	return biz.MFAPolicy{
+		Issuer:           opts.Issuer,
+		RequireForAdmins: opts.RequireForAdmins,
+		ChallengeTTL:     opts.ChallengeTTL,
+		MaxAttempts:      opts.MaxAttempts,
+		RecoveryCodes:    opts.RecoveryCodes,
+	}
+}
+
+// newPasswordPolicy 基于密码策略选项创建密码策略.
+func newPasswordPolicy(opts *options.PasswordOptions) (password.Policy, error) {
+	policy := password.Policy{
+		MinLength:          opts.MinLength,
+		MaxLength:          opts.MaxLength,
+		RequireUppercase:   opts.RequireUppercase,
+		RequireLowercase:   opts.RequireLowercase,
+		RequireDigit:       opts.RequireDigit,
+		RequireSpecial:     opts.RequireSpecial,
+		HistorySize:        opts.HistorySize,
+		MaxAge:             opts.MaxAge,
+		BcryptCost:         opts.BcryptCost,
+		UpgradeCostOnLogin: opts.UpgradeCostOnLogin,
+	}
+
+	if opts.BannedFile != "" {
+		banned, err := password.LoadBanned(opts.BannedFile)
+		if err != nil {
+			return password.Policy{}, err
+		}
+		policy.Banned = banned
+	}
+
+	return policy, nil
+}
+
+func newJWTAuth(opts *genoptions.JwtOptions, keyfunc gojwt.Keyfunc) middleware.AuthStrategy {
 	ginjwt, _ := jwt.New(&jwt.GinJWTMiddleware{
-		Realm:            viper.GetString("jwt.realm"), // JWT 标识
+		Realm:            opts.Realm, // JWT 标识
 		SigningAlgorithm: "HS256",
-		Key:              []byte(viper.GetString("jwt.key")),   // 用于签名的密钥
-		Timeout:          viper.GetDuration("jwt.timeout"),     // token 有效时间
-		MaxRefresh:       viper.GetDuration("jwt.max-refresh"), // token 最长更新间隔
-		Authenticator:    authenticator(),                      // 用户身份验证，
-		LoginResponse:    loginResponse(),                      // 登录响应
+		Key:              []byte(opts.Key), // 用于签名的密钥
+		KeyFunc:          keyfunc,          // 根据 token 的签名算法和 kid 选择验证密钥
+		Timeout:          opts.Timeout,     // token 有效时间
+		MaxRefresh:       opts.MaxRefresh,  // token 最长更新间隔
+		Authenticator:    authenticator(),  // 用户身份验证，
+		LoginResponse:    loginResponse(),  // 登录响应
 		LogoutResponse: func(c *gin.Context, code int) { // 退出登录响应
 			c.JSON(http.StatusOK, nil)
 		},
@@ -84,6 +144,10 @@ func newJWTAuth() middleware.AuthStrategy {
 		IdentityKey:  middleware.UsernameKey, // 身份标识字段
 		Authorizator: authorizator(),         // 用户权限检查
 		Unauthorized: func(c *gin.Context, code int, message string) { // 未授权响应
+			if err := c.Errors.Last(); err != nil {
+				core.WriteResponse(c, err.Err, nil)
+				return
+			}
 			c.JSON(code, gin.H{
 				"message": message,
 			})
@@ -96,6 +160,257 @@ func newJWTAuth() middleware.AuthStrategy {
 	return auth.NewJWTStrategy(*ginjwt)
 }
 
+// tokenLifetime 返回 token 签发后最长可以使用（包括刷新）的时间.
+func tokenLifetime(opts *genoptions.JwtOptions) time.Duration {
+	if opts.MaxRefresh > opts.Timeout {
+		return opts.MaxRefresh
+	}
+	return opts.Timeout
+}
+
+// reloadableJWTAuth 提供登录、登出和刷新 token 接口，配置热加载修改 jwt.timeout、jwt.keys
+// 或 jwt.active-kid 后，新签发的 token 使用新的有效时间和签发密钥.
+type reloadableJWTAuth struct {
+	state atomic.Value // jwtAuthState
+}
+
+// jwtAuthState 保存当前的认证策略和密钥.
+type jwtAuthState struct {
+	strategy auth.JWTStrategy
+	// keys 为 jwt.keys 中的非对称密钥，为 nil 时使用 key 以 HS256 签发 token
+	keys *jwks.KeySet
+	key  []byte
+}
+
+func newReloadableJWTAuth(opts *genoptions.JwtOptions) (*reloadableJWTAuth, error) {
+	j := &reloadableJWTAuth{}
+	if err := j.SetOptions(opts); err != nil {
+		return nil, err
+	}
+
+	return j, nil
+}
+
+// SetOptions 使用新的 JWT 选项重建认证策略并重新读取密钥，对之后到达的请求生效.
+func (j *reloadableJWTAuth) SetOptions(opts *genoptions.JwtOptions) error {
+	keys, err := opts.KeySet()
+	if err != nil {
+		return err
+	}
+
+	j.state.Store(jwtAuthState{
+		strategy: newJWTAuth(opts, j.keyfunc).(auth.JWTStrategy),
+		keys:     keys,
+		key:      []byte(opts.Key),
+	})
+
+	return nil
+}
+
+func (j *reloadableJWTAuth) load() jwtAuthState {
+	return j.state.Load().(jwtAuthState)
+}
+
+// keyfunc 返回验证 token 签名使用的密钥：HS256 token 使用 jwt.key 验证，
+// 其他 token 根据 kid 使用 jwt.keys 中的公钥验证. 迁移到非对称密钥后，
+// 在旧 token 全部过期前保留 jwt.key，之后将其移除.
+func (j *reloadableJWTAuth) keyfunc(token *gojwt.Token) (interface{}, error) {
+	state := j.load()
+
+	if token.Method == gojwt.SigningMethodHS256 {
+		if len(state.key) == 0 {
+			return nil, jwt.ErrInvalidSigningAlgorithm
+		}
+
+		return state.key, nil
+	}
+
+	if state.keys == nil {
+		return nil, jwt.ErrInvalidSigningAlgorithm
+	}
+
+	return state.keys.Keyfunc(token)
+}
+
+// sign 签发 token，配置了 jwt.keys 时使用其中的签发密钥，否则使用 jwt.key.
+func (s jwtAuthState) sign(claims gojwt.MapClaims) (string, error) {
+	if s.keys != nil {
+		return s.keys.Sign(claims)
+	}
+
+	return gojwt.NewWithClaims(gojwt.SigningMethodHS256, claims).SignedString(s.key)
+}
+
+// issueToken 设置 token 的有效时间，签发 token 并返回给客户端.
+func (s jwtAuthState) issueToken(c *gin.Context, claims gojwt.MapClaims,
+	response func(c *gin.Context, code int, token string, expire time.Time),
+) {
+	now := s.strategy.TimeFunc()
+	expire := now.Add(s.strategy.Timeout)
+	claims["exp"] = expire.Unix()
+	claims["orig_iat"] = now.Unix()
+	claims[revocation.IssuedAtMsClaim] = now.UnixMilli()
+
+	token, err := s.sign(claims)
+	if err != nil {
+		log.C(c).Errorf("Sign token failed: %s", err.Error())
+		s.strategy.Unauthorized(c, http.StatusUnauthorized, jwt.ErrFailedTokenCreation.Error())
+
+		return
+	}
+
+	if s.strategy.SendCookie {
+		if s.strategy.CookieSameSite != 0 {
+			c.SetSameSite(s.strategy.CookieSameSite)
+		}
+		c.SetCookie(s.strategy.CookieName, token, int(s.strategy.CookieMaxAge.Seconds()), "/",
+			s.strategy.CookieDomain, s.strategy.SecureCookie, s.strategy.CookieHTTPOnly)
+	}
+
+	response(c, http.StatusOK, token, expire)
+}
+
+// LoginHandler 处理用户登录请求.
+func (j *reloadableJWTAuth) LoginHandler(c *gin.Context) {
+	state := j.load()
+
+	data, err := state.strategy.Authenticator(c)
+	if err != nil {
+		state.strategy.Unauthorized(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	// 需要两步验证的用户通过密码校验后只获得登录挑战，提供验证码后才签发 token
+	if u, ok := data.(*user.User); ok && biz.New(store.Store()).Users().RequiresMFA(u) {
+		mfaChallenge(c, u)
+		return
+	}
+
+	state.login(c, data)
+}
+
+// ChangeExpiredPasswordHandler 处理密码过期用户的登录请求，校验旧密码并修改为新密码后继续登录.
+func (j *reloadableJWTAuth) ChangeExpiredPasswordHandler(c *gin.Context) {
+	var r changeExpiredPasswordInfo
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+		return
+	}
+
+	users := biz.New(store.Store()).Users()
+	u, err := users.ChangeExpiredPassword(c, r.Username, r.Password, r.NewPassword, iputil.RemoteIP(c.Request))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	if users.RequiresMFA(u) {
+		mfaChallenge(c, u)
+		return
+	}
+
+	j.load().login(c, u)
+}
+
+// login 为通过认证的用户签发 token，data 为认证返回的用户.
+func (s jwtAuthState) login(c *gin.Context, data interface{}) {
+	s.issueToken(c, s.claims(data), s.strategy.LoginResponse)
+}
+
+// claims 返回为认证返回的用户签发 token 使用的声明.
+func (s jwtAuthState) claims(data interface{}) gojwt.MapClaims {
+	claims := gojwt.MapClaims{}
+	for k, v := range s.strategy.PayloadFunc(data) {
+		claims[k] = v
+	}
+
+	return claims
+}
+
+// LogoutHandler 处理用户登出请求，吊销请求携带的 token.
+func (j *reloadableJWTAuth) LogoutHandler(c *gin.Context) {
+	strategy := j.load().strategy
+
+	// 已经过期但仍在刷新时间内的 token 也需要吊销，避免被用来刷新
+	if claims, err := strategy.CheckIfTokenExpire(c); err == nil {
+		if jti, _ := claims["jti"].(string); jti != "" {
+			expiresAt := claimTime(claims, "exp")
+			if refreshable := claimTime(claims, "orig_iat").Add(strategy.MaxRefresh); refreshable.After(expiresAt) {
+				expiresAt = refreshable
+			}
+
+			if err := revocation.Get().Revoke(c, jti, expiresAt); err != nil {
+				core.WriteResponse(c, errors.WithCode(code.ErrDatabase, "revoke token failed: %s", err.Error()), nil)
+				return
+			}
+		}
+	}
+
+	strategy.LogoutHandler(c)
+}
+
+// RefreshHandler 处理刷新 token 请求，已被吊销的 token 不能刷新.
+func (j *reloadableJWTAuth) RefreshHandler(c *gin.Context) {
+	state := j.load()
+
+	claims, err := state.strategy.CheckIfTokenExpire(c)
+	if err != nil {
+		state.strategy.Unauthorized(c, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if err := checkRevoked(c, claims); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	newClaims := gojwt.MapClaims{}
+	for k, v := range claims {
+		newClaims[k] = v
+	}
+
+	state.issueToken(c, newClaims, state.strategy.RefreshResponse)
+}
+
+// JWKSHandler 返回验证 token 使用的公钥，供其他服务在不共享密钥的情况下验证 token.
+func (j *reloadableJWTAuth) JWKSHandler(c *gin.Context) {
+	set := jwks.JSONWebKeySet{Keys: []jwks.JSONWebKey{}}
+	if keys := j.load().keys; keys != nil {
+		set = keys.JWKS()
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	core.WriteResponse(c, nil, set)
+}
+
+// checkRevoked 检查 token 是否已被吊销，吊销列表不可用时无法确认 token 未被吊销，拒绝请求.
+func checkRevoked(c *gin.Context, claims map[string]interface{}) error {
+	jti, _ := claims["jti"].(string)
+	username, _ := claims[jwt.IdentityKey].(string)
+
+	revoked, err := revocation.Get().IsRevoked(c, jti, username, revocation.IssuedAt(claims))
+	if err != nil {
+		return errors.WithCode(code.ErrDatabase, "check revocation of token %s failed: %s", jti, err.Error())
+	}
+	if revoked {
+		return errors.WithCode(code.ErrTokenRevoked, "token %s of user %s has been revoked", jti, username)
+	}
+
+	return nil
+}
+
+// claimTime 将 token 中以 Unix 时间戳表示的字段转换为时间.
+func claimTime(claims map[string]interface{}, key string) time.Time {
+	switch v := claims[key].(type) {
+	case float64:
+		return time.Unix(int64(v), 0)
+	case json.Number:
+		n, _ := v.Int64()
+		return time.Unix(n, 0)
+	default:
+		return time.Time{}
+	}
+}
+
 // authenticator 验证用户身份.
 func authenticator() func(c *gin.Context) (interface{}, error) {
 	return func(c *gin.Context) (interface{}, error) {
@@ -112,22 +427,16 @@ func authenticator() func(c *gin.Context) (interface{}, error) {
 			return "", jwt.ErrFailedAuthentication
 		}
 
-		// 基于登录用户名查找用户
-		user, err := store.Store().Users().Get(c, login.Username)
+		// 校验用户密码，连续登录失败的用户会被锁定
+		user, err := biz.New(store.Store()).Users().Authenticate(c, login.Username, login.Password,
+			iputil.RemoteIP(c.Request))
 		if err != nil {
-			log.Errorf("Get user information failed: %s", err.Error())
-
-			return "", jwt.ErrFailedAuthentication
-		}
+			// 由 Unauthorized 返回业务错误码
+			_ = c.Error(err)
 
-		// 验证用户密码
-		if err := user.Compare(login.Password); err != nil {
 			return "", jwt.ErrFailedAuthentication
 		}
 
-		user.LoginAt = time.Now()
-		_ = store.Store().Users().Update(c, user)
-
 		return user, nil
 	}
 }
@@ -193,6 +502,7 @@ func payloadFunc() func(data interface{}) jwt.MapClaims {
 		claims := jwt.MapClaims{
 			"iss": APIServerIssuer,
 			"aud": APIServerAudience,
+			"jti": uuid.New().String(),
 		}
 		if u, ok := data.(*user.User); ok {
 			claims[jwt.IdentityKey] = u.Name
@@ -204,10 +514,41 @@ func payloadFunc() func(data interface{}) jwt.MapClaims {
 
 func authorizator() func(data interface{}, c *gin.Context) bool {
 	return func(data interface{}, c *gin.Context) bool {
-		if v, ok := data.(string); ok {
-			log.C(c).Infof("User `%s` is authenticated.", v)
-			return true
+		v, ok := data.(string)
+		if !ok {
+			return false
 		}
-		return false
+
+		if err := checkRevoked(c, jwt.ExtractClaims(c)); err != nil {
+			// 由 Unauthorized 返回业务错误码
+			_ = c.Error(err)
+
+			return false
+		}
+
+		log.C(c).Infof("User `%s` is authenticated.", v)
+		return true
+	}
+}
+
+// adminOnly 是一个 Gin 中间件，要求当前登录用户为管理员，需要在认证中间件之后使用.
+func adminOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		operator, err := store.Store().Users().Get(c, c.GetString(middleware.UsernameKey))
+		if err != nil {
+			core.WriteResponse(c, err, nil)
+			c.Abort()
+
+			return
+		}
+
+		if operator.IsAdmin != 1 {
+			core.WriteResponse(c, errors.WithCode(code.ErrPermissionDenied, "administrator permission required"), nil)
+			c.Abort()
+
+			return
+		}
+
+		c.Next()
 	}
 }
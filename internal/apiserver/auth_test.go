@@ -0,0 +1,163 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package apiserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/changaolee/skeleton/internal/apiserver/biz"
+	"github.com/changaolee/skeleton/internal/apiserver/password"
+	"github.com/changaolee/skeleton/internal/apiserver/store"
+	"github.com/changaolee/skeleton/internal/apiserver/store/fake"
+	"github.com/changaolee/skeleton/internal/pkg/model/user"
+	genoptions "github.com/changaolee/skeleton/internal/pkg/options"
+	"github.com/changaolee/skeleton/internal/pkg/revocation"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+)
+
+// newTestJWTAuth 使用内存中的用户和吊销列表创建 reloadableJWTAuth，并创建用户 alice.
+func newTestJWTAuth(t *testing.T) *reloadableJWTAuth {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	oldStore, oldRevoker := store.Store(), revocation.Get()
+	t.Cleanup(func() {
+		store.SetStore(oldStore)
+		revocation.Set(oldRevoker)
+		biz.SetPasswordPolicy(password.DefaultPolicy())
+	})
+
+	s := fake.NewStore()
+	store.SetStore(s)
+	revocation.Set(revocation.NewMemoryRevoker())
+	policy := password.DefaultPolicy()
+	policy.BcryptCost = bcrypt.MinCost
+	biz.SetPasswordPolicy(policy)
+
+	err := biz.New(s).Users().Create(context.Background(), &user.User{
+		ObjectMeta: metav1.ObjectMeta{Name: "alice"},
+		Status:     user.StatusActive,
+		Nickname:   "alice",
+		Password:   "Secret#123",
+		Email:      "alice@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j, err := newReloadableJWTAuth(&genoptions.JwtOptions{
+		Realm:      "test",
+		Key:        "0123456789abcdef0123456789abcdef",
+		Timeout:    time.Hour,
+		MaxRefresh: time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return j
+}
+
+// postToken 发送 JSON 请求并返回响应中的 token.
+func postToken(t *testing.T, g *gin.Engine, path, body string) string {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	g.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("POST %s = %d %s", path, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Token string `json:"token"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Token == "" {
+		t.Fatalf("POST %s response %s has no token", path, w.Body.String())
+	}
+	return resp.Token
+}
+
+// getWithToken 使用 token 访问需要认证的接口，返回状态码.
+func getWithToken(g *gin.Engine, token string) int {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	g.ServeHTTP(w, req)
+
+	return w.Code
+}
+
+func TestChangeExpiredPasswordHandler_TokenUsable(t *testing.T) {
+	j := newTestJWTAuth(t)
+
+	g := gin.New()
+	g.POST("/login", j.LoginHandler)
+	g.POST("/login/password", j.ChangeExpiredPasswordHandler)
+	g.GET("/me", j.load().strategy.AuthFunc(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// 在修改密码之前签发的 token
+	state := j.load()
+	state.strategy.TimeFunc = func() time.Time { return time.Now().Add(-time.Minute) }
+	j.state.Store(state)
+	oldToken := postToken(t, g, "/login", `{"username":"alice","password":"Secret#123"}`)
+	state.strategy.TimeFunc = time.Now
+	j.state.Store(state)
+
+	token := postToken(t, g, "/login/password",
+		`{"username":"alice","password":"Secret#123","newPassword":"NewSecret#456"}`)
+
+	if code := getWithToken(g, token); code != http.StatusOK {
+		t.Errorf("GET with token returned by /login/password = %d, want 200", code)
+	}
+	if code := getWithToken(g, oldToken); code != http.StatusUnauthorized {
+		t.Errorf("GET with token issued before password change = %d, want 401", code)
+	}
+}
+
+// failingRevoker 模拟不可用的吊销列表.
+type failingRevoker struct {
+	revocation.Revoker
+}
+
+func (failingRevoker) IsRevoked(context.Context, string, string, time.Time) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func TestCheckRevoked_RevokerUnavailable(t *testing.T) {
+	j := newTestJWTAuth(t)
+
+	g := gin.New()
+	g.POST("/login", j.LoginHandler)
+	g.POST("/refresh", j.RefreshHandler)
+	g.GET("/me", j.load().strategy.AuthFunc(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	token := postToken(t, g, "/login", `{"username":"alice","password":"Secret#123"}`)
+	revocation.Set(failingRevoker{})
+
+	if code := getWithToken(g, token); code == http.StatusOK {
+		t.Errorf("GET when revocation list is unavailable = %d, want rejected", code)
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/refresh", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	g.ServeHTTP(w, req)
+	if w.Code == http.StatusOK {
+		t.Errorf("POST /refresh when revocation list is unavailable = %d, want rejected", w.Code)
+	}
+}
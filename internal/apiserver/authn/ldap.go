@@ -0,0 +1,206 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package authn
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/changaolee/skeleton/internal/apiserver/options"
+)
+
+// ldapVerifier 先使用 bind-dn 查找用户条目，再使用用户条目的 DN 和密码 bind 校验密码.
+type ldapVerifier struct {
+	url          string
+	startTLS     bool
+	tlsConfig    *tls.Config
+	timeout      time.Duration
+	bindDN       string
+	bindPassword string
+	baseDN       string
+	userFilter   string
+	nicknameAttr string
+	emailAttr    string
+	phoneAttr    string
+	groupAttr    string
+	adminGroups  []*ldap.DN
+}
+
+// NewLDAPVerifier 创建一个使用 LDAP 校验密码的 PasswordVerifier.
+func NewLDAPVerifier(opts *options.LDAPOptions) (PasswordVerifier, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		ServerName:         u.Hostname(),
+		InsecureSkipVerify: opts.InsecureSkipVerify, //nolint:gosec // 由配置决定，只用于测试
+		MinVersion:         tls.VersionTLS12,
+	}
+	if opts.CAFile != "" {
+		pem, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in %s", opts.CAFile)
+		}
+	}
+
+	v := &ldapVerifier{
+		url:          opts.URL,
+		startTLS:     opts.StartTLS,
+		tlsConfig:    tlsConfig,
+		timeout:      opts.Timeout,
+		bindDN:       opts.BindDN,
+		bindPassword: opts.BindPassword,
+		baseDN:       opts.BaseDN,
+		userFilter:   opts.UserFilter,
+		nicknameAttr: opts.NicknameAttribute,
+		emailAttr:    opts.EmailAttribute,
+		phoneAttr:    opts.PhoneAttribute,
+		groupAttr:    opts.GroupAttribute,
+	}
+	for _, group := range opts.AdminGroups {
+		dn, err := ldap.ParseDN(group)
+		if err != nil {
+			return nil, fmt.Errorf("invalid admin group %q: %w", group, err)
+		}
+		v.adminGroups = append(v.adminGroups, dn)
+	}
+
+	return v, nil
+}
+
+func (v *ldapVerifier) Name() string {
+	return "ldap"
+}
+
+func (v *ldapVerifier) Verify(ctx context.Context, username, password string) (*Account, error) {
+	// LDAP 服务器会将空密码的 bind 视为匿名 bind 并返回成功
+	if password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := v.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	entry, err := v.search(conn, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCredentials, err.Error())
+		}
+		return nil, fmt.Errorf("bind as %s: %w", entry.DN, err)
+	}
+
+	account := &Account{
+		Username: username,
+		Nickname: v.attribute(entry, v.nicknameAttr),
+		Email:    v.attribute(entry, v.emailAttr),
+		Phone:    v.attribute(entry, v.phoneAttr),
+	}
+	if len(v.adminGroups) > 0 {
+		isAdmin := v.isAdmin(entry)
+		account.IsAdmin = &isAdmin
+	}
+
+	return account, nil
+}
+
+// dial 连接 LDAP 服务器，需要时升级为 TLS 连接，并使用 bind-dn 登录.
+func (v *ldapVerifier) dial() (*ldap.Conn, error) {
+	conn, err := ldap.DialURL(v.url,
+		ldap.DialWithDialer(&net.Dialer{Timeout: v.timeout}),
+		ldap.DialWithTLSConfig(v.tlsConfig),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("connect to %s: %w", v.url, err)
+	}
+	conn.SetTimeout(v.timeout)
+
+	if v.startTLS {
+		if err := conn.StartTLS(v.tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("start tls: %w", err)
+		}
+	}
+
+	if v.bindDN != "" {
+		if err := conn.Bind(v.bindDN, v.bindPassword); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("bind as %s: %w", v.bindDN, err)
+		}
+	}
+
+	return conn, nil
+}
+
+// search 查找用户条目，用户不存在时返回 ErrUserNotFound.
+func (v *ldapVerifier) search(conn *ldap.Conn, username string) (*ldap.Entry, error) {
+	var attributes []string
+	for _, attr := range []string{v.nicknameAttr, v.emailAttr, v.phoneAttr, v.groupAttr} {
+		if attr != "" {
+			attributes = append(attributes, attr)
+		}
+	}
+
+	req := ldap.NewSearchRequest(v.baseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2,
+		int(v.timeout.Seconds()), false, fmt.Sprintf(v.userFilter, ldap.EscapeFilter(username)), attributes, nil)
+	result, err := conn.Search(req)
+	if err != nil && !ldap.IsErrorWithCode(err, ldap.LDAPResultSizeLimitExceeded) {
+		return nil, fmt.Errorf("search user %s: %w", username, err)
+	}
+
+	switch {
+	case result == nil || len(result.Entries) == 0:
+		return nil, ErrUserNotFound
+	case len(result.Entries) > 1:
+		return nil, fmt.Errorf("search user %s: more than one entry found", username)
+	default:
+		return result.Entries[0], nil
+	}
+}
+
+func (v *ldapVerifier) attribute(entry *ldap.Entry, name string) string {
+	if name == "" {
+		return ""
+	}
+	return entry.GetEqualFoldAttributeValue(name)
+}
+
+// isAdmin 判断用户是否属于管理员组.
+func (v *ldapVerifier) isAdmin(entry *ldap.Entry) bool {
+	for _, group := range entry.GetEqualFoldAttributeValues(v.groupAttr) {
+		dn, err := ldap.ParseDN(group)
+		if err != nil {
+			continue
+		}
+		for _, admin := range v.adminGroups {
+			if admin.EqualFold(dn) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
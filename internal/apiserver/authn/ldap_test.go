@@ -0,0 +1,319 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package authn
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+
+	"github.com/changaolee/skeleton/internal/apiserver/options"
+)
+
+const (
+	serviceDN       = "cn=skt,dc=example,dc=com"
+	servicePassword = "service-secret"
+	adminGroup      = "cn=skt-admins,ou=groups,dc=example,dc=com"
+)
+
+type fakeEntry struct {
+	dn         string
+	password   string
+	attributes map[string][]string
+}
+
+// fakeLDAPServer 是一个只支持 simple bind 和 search 的 LDAP 服务器，search 只支持 (uid=xxx) 形式的过滤条件.
+type fakeLDAPServer struct {
+	listener net.Listener
+	entries  []fakeEntry
+}
+
+func newFakeLDAPServer(t *testing.T, tlsConfig *tls.Config) *fakeLDAPServer {
+	t.Helper()
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", tlsConfig)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeLDAPServer{
+		listener: listener,
+		entries: []fakeEntry{
+			{dn: serviceDN, password: servicePassword},
+			{dn: "uid=alice,ou=people,dc=example,dc=com", password: "alice-secret", attributes: map[string][]string{
+				"uid":      {"alice"},
+				"cn":       {"Alice"},
+				"mail":     {"alice@example.com"},
+				"memberOf": {"cn=dev,ou=groups,dc=example,dc=com", "CN=SKT-Admins,OU=Groups,DC=example,DC=com"},
+			}},
+			{dn: "uid=bob,ou=people,dc=example,dc=com", password: "bob-secret", attributes: map[string][]string{
+				"uid":      {"bob"},
+				"cn":       {"Bob"},
+				"memberOf": {"cn=dev,ou=groups,dc=example,dc=com"},
+			}},
+		},
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+func (s *fakeLDAPServer) URL() string {
+	return "ldaps://" + s.listener.Addr().String()
+}
+
+func (s *fakeLDAPServer) serve(conn net.Conn) {
+	defer conn.Close()
+
+	var boundDN string
+	for {
+		req, err := ber.ReadPacket(conn)
+		if err != nil || len(req.Children) < 2 {
+			return
+		}
+		id := req.Children[0].Value
+		op := req.Children[1]
+
+		switch op.Tag {
+		case ldap.ApplicationBindRequest:
+			dn, _ := op.Children[1].Value.(string)
+			password := op.Children[2].Data.String()
+
+			code := uint16(ldap.LDAPResultInvalidCredentials)
+			if e := s.find(func(e fakeEntry) bool { return e.dn == dn }); e != nil && e.password == password {
+				code, boundDN = ldap.LDAPResultSuccess, dn
+			}
+			s.write(conn, id, ldap.ApplicationBindResponse, code)
+		case ldap.ApplicationSearchRequest:
+			if boundDN != serviceDN {
+				s.write(conn, id, ldap.ApplicationSearchResultDone, ldap.LDAPResultInsufficientAccessRights)
+				continue
+			}
+
+			filter, _ := ldap.DecompileFilter(op.Children[6])
+			uid := strings.TrimSuffix(strings.TrimPrefix(filter, "(uid="), ")")
+			if e := s.find(func(e fakeEntry) bool {
+				return len(e.attributes["uid"]) > 0 && e.attributes["uid"][0] == uid
+			}); e != nil {
+				s.writeEntry(conn, id, e)
+			}
+			s.write(conn, id, ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess)
+		default:
+			return
+		}
+	}
+}
+
+func (s *fakeLDAPServer) find(match func(fakeEntry) bool) *fakeEntry {
+	for i := range s.entries {
+		if match(s.entries[i]) {
+			return &s.entries[i]
+		}
+	}
+	return nil
+}
+
+func message(id interface{}, op *ber.Packet) []byte {
+	p := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	p.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, id, "Message ID"))
+	p.AppendChild(op)
+
+	return p.Bytes()
+}
+
+func (s *fakeLDAPServer) write(conn net.Conn, id interface{}, tag ber.Tag, code uint16) {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Response")
+	op.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Diagnostic Message"))
+
+	_, _ = conn.Write(message(id, op))
+}
+
+func (s *fakeLDAPServer) writeEntry(conn net.Conn, id interface{}, e *fakeEntry) {
+	op := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Entry")
+	op.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.dn, "DN"))
+	attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for name, values := range e.attributes {
+		attr := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attr.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+		vals := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, v := range values {
+			vals.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, v, "Value"))
+		}
+		attr.AppendChild(vals)
+		attributes.AppendChild(attr)
+	}
+	op.AppendChild(attributes)
+
+	_, _ = conn.Write(message(id, op))
+}
+
+// newTestCertificate 生成 127.0.0.1 的自签名证书，返回服务端 TLS 配置和 CA 文件路径.
+func newTestCertificate(t *testing.T) (*tls.Config, string) {
+	t.Helper()
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "fake ldap"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() error = %v", err)
+	}
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("write ca file: %v", err)
+	}
+
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}, caFile
+}
+
+func newTestLDAPOptions(url, caFile string) *options.LDAPOptions {
+	opts := options.NewLDAPOptions()
+	opts.Enabled = true
+	opts.URL = url
+	opts.CAFile = caFile
+	opts.BindDN = serviceDN
+	opts.BindPassword = servicePassword
+	opts.BaseDN = "ou=people,dc=example,dc=com"
+	opts.AdminGroups = []string{adminGroup}
+
+	return opts
+}
+
+func TestLDAPVerifier(t *testing.T) {
+	ctx := context.Background()
+	tlsConfig, caFile := newTestCertificate(t)
+	server := newFakeLDAPServer(t, tlsConfig)
+
+	v, err := NewLDAPVerifier(newTestLDAPOptions(server.URL(), caFile))
+	if err != nil {
+		t.Fatalf("NewLDAPVerifier() error = %v", err)
+	}
+
+	account, err := v.Verify(ctx, "alice", "alice-secret")
+	if err != nil {
+		t.Fatalf("Verify(alice) error = %v", err)
+	}
+	if account.Username != "alice" || account.Nickname != "Alice" || account.Email != "alice@example.com" ||
+		account.IsAdmin == nil || !*account.IsAdmin {
+		t.Errorf("Verify(alice) = %+v, want admin alice", account)
+	}
+
+	account, err = v.Verify(ctx, "bob", "bob-secret")
+	if err != nil {
+		t.Fatalf("Verify(bob) error = %v", err)
+	}
+	if account.IsAdmin == nil || *account.IsAdmin {
+		t.Errorf("Verify(bob) IsAdmin = %v, want false", account.IsAdmin)
+	}
+
+	tests := []struct {
+		name     string
+		username string
+		password string
+		want     error
+	}{
+		{name: "wrong password", username: "alice", password: "bob-secret", want: ErrInvalidCredentials},
+		{name: "empty password", username: "alice", password: "", want: ErrInvalidCredentials},
+		{name: "unknown user", username: "carol", password: "carol-secret", want: ErrUserNotFound},
+		{name: "filter injection", username: "*", password: "alice-secret", want: ErrUserNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := v.Verify(ctx, tt.username, tt.password); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// 不信任服务端证书时无法连接
+	v, _ = NewLDAPVerifier(newTestLDAPOptions(server.URL(), ""))
+	if _, err := v.Verify(ctx, "alice", "alice-secret"); err == nil || errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Verify() with untrusted certificate error = %v, want connection error", err)
+	}
+}
+
+type fakeVerifier map[string]string
+
+func (v fakeVerifier) Name() string {
+	return "fake"
+}
+
+func (v fakeVerifier) Verify(ctx context.Context, username, password string) (*Account, error) {
+	p, ok := v[username]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	if p != password {
+		return nil, ErrInvalidCredentials
+	}
+	return &Account{Username: username}, nil
+}
+
+func TestFallbackVerifier(t *testing.T) {
+	ctx := context.Background()
+	tlsConfig, caFile := newTestCertificate(t)
+	server := newFakeLDAPServer(t, tlsConfig)
+	local := fakeVerifier{"alice": "local-secret", "root": "root-secret"}
+
+	primary, _ := NewLDAPVerifier(newTestLDAPOptions(server.URL(), caFile))
+	v := NewFallbackVerifier(primary, local)
+
+	if _, err := v.Verify(ctx, "alice", "alice-secret"); err != nil {
+		t.Errorf("Verify(alice) error = %v", err)
+	}
+	// LDAP 中存在的用户不使用本地密码校验
+	if _, err := v.Verify(ctx, "alice", "local-secret"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Verify(alice, local password) error = %v, want %v", err, ErrInvalidCredentials)
+	}
+	// LDAP 中不存在的用户使用本地账户校验
+	if _, err := v.Verify(ctx, "root", "root-secret"); err != nil {
+		t.Errorf("Verify(root) error = %v", err)
+	}
+
+	// LDAP 不可用时使用本地账户校验
+	unavailable, _ := NewLDAPVerifier(newTestLDAPOptions("ldaps://127.0.0.1:1", caFile))
+	v = NewFallbackVerifier(unavailable, local)
+	if _, err := v.Verify(ctx, "alice", "local-secret"); err != nil {
+		t.Errorf("Verify(alice) with LDAP unavailable error = %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package authn
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/changaolee/skeleton/internal/apiserver/store"
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/pkg/errors"
+)
+
+// localVerifier 使用 user 表中保存的密码校验用户.
+type localVerifier struct {
+	s store.IStore
+}
+
+// NewLocalVerifier 创建一个使用本地账户校验密码的 PasswordVerifier.
+func NewLocalVerifier(s store.IStore) PasswordVerifier {
+	return &localVerifier{s: s}
+}
+
+func (v *localVerifier) Name() string {
+	return "local"
+}
+
+func (v *localVerifier) Verify(ctx context.Context, username, password string) (*Account, error) {
+	u, err := v.s.Users().Get(ctx, username)
+	if err != nil {
+		if errors.IsCode(err, code.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if err := u.Compare(password); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCredentials, err.Error())
+	}
+
+	return &Account{
+		Username: u.Name,
+		Nickname: u.Nickname,
+		Email:    u.Email,
+		Phone:    u.Phone,
+		Local:    true,
+	}, nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package authn
+
+import (
+	"context"
+	"errors"
+
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+var (
+	// ErrInvalidCredentials 表示密码错误.
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	// ErrUserNotFound 表示用户不存在.
+	ErrUserNotFound = errors.New("user not found")
+)
+
+// Account 是通过密码校验的账户信息.
+type Account struct {
+	Username string
+	Nickname string
+	Email    string
+	Phone    string
+	// IsAdmin 为 nil 表示由 skt 管理用户的管理员权限，否则登录时将用户的管理员权限同步为该值
+	IsAdmin *bool
+	// Local 为 true 表示使用 user 表中保存的密码校验，密码策略中的有效期和加密强度只对本地密码生效
+	Local bool
+}
+
+// PasswordVerifier 定义了用户密码的校验方式.
+type PasswordVerifier interface {
+	// Name 返回校验方式的名称.
+	Name() string
+	// Verify 校验用户密码，用户不存在时返回 ErrUserNotFound，密码错误时返回 ErrInvalidCredentials.
+	Verify(ctx context.Context, username, password string) (*Account, error)
+}
+
+var ins PasswordVerifier
+
+// Get 获取 PasswordVerifier 实例，未设置时返回 nil，表示使用本地账户校验.
+func Get() PasswordVerifier {
+	return ins
+}
+
+// Set 设置 PasswordVerifier 实例.
+func Set(v PasswordVerifier) {
+	ins = v
+}
+
+// fallbackVerifier 在 primary 中找不到用户或 primary 不可用时，使用 fallback 校验密码.
+type fallbackVerifier struct {
+	primary  PasswordVerifier
+	fallback PasswordVerifier
+}
+
+// NewFallbackVerifier 创建一个 PasswordVerifier，优先使用 primary 校验密码，
+// primary 中找不到用户或 primary 不可用时使用 fallback 校验. primary 返回密码错误时不会使用 fallback.
+func NewFallbackVerifier(primary, fallback PasswordVerifier) PasswordVerifier {
+	return &fallbackVerifier{primary: primary, fallback: fallback}
+}
+
+func (v *fallbackVerifier) Name() string {
+	return v.primary.Name() + "+" + v.fallback.Name()
+}
+
+func (v *fallbackVerifier) Verify(ctx context.Context, username, password string) (*Account, error) {
+	account, err := v.primary.Verify(ctx, username, password)
+	if err == nil || errors.Is(err, ErrInvalidCredentials) {
+		return account, err
+	}
+
+	if !errors.Is(err, ErrUserNotFound) {
+		log.C(ctx).Warnf("Verify password of user %s with %s failed, fall back to %s: %s",
+			username, v.primary.Name(), v.fallback.Name(), err.Error())
+	}
+
+	return v.fallback.Verify(ctx, username, password)
+}
@@ -0,0 +1,264 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package biz
+
+import (
+	"context"
+	"crypto/rand"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/changaolee/skeleton/internal/apiserver/mail"
+	"github.com/changaolee/skeleton/internal/apiserver/verification"
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/model/user"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+// AccountPolicy 定义了邮箱验证和找回密码的策略.
+type AccountPolicy struct {
+	// Signer 用于签发和校验邮件中的令牌
+	Signer *verification.Signer
+	// Templates 为邮件模板
+	Templates *mail.Templates
+	// EmailVerificationTTL 和 PasswordResetTTL 为令牌的有效期
+	EmailVerificationTTL time.Duration
+	PasswordResetTTL     time.Duration
+	// ResendInterval 为向同一用户发送同类邮件的最小间隔
+	ResendInterval time.Duration
+	// RequireVerifiedEmail 为 true 时新用户在验证邮箱前无法登录
+	RequireVerifiedEmail bool
+	// VerifyEmailURL 和 ResetPasswordURL 为邮件中链接的地址，令牌作为 token 查询参数附加在地址后
+	VerifyEmailURL   string
+	ResetPasswordURL string
+}
+
+// accountPolicy 保存当前生效的账户策略，类型为 AccountPolicy.
+var accountPolicy atomic.Value
+
+func init() {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	templates, _ := mail.NewTemplates("")
+
+	accountPolicy.Store(AccountPolicy{
+		Signer:               verification.NewSigner(key),
+		Templates:            templates,
+		EmailVerificationTTL: 24 * time.Hour,
+		PasswordResetTTL:     30 * time.Minute,
+		ResendInterval:       time.Minute,
+	})
+}
+
+// SetAccountPolicy 替换账户策略，对之后的请求生效.
+func SetAccountPolicy(policy AccountPolicy) {
+	accountPolicy.Store(policy)
+}
+
+func loadAccountPolicy() AccountPolicy {
+	policy, _ := accountPolicy.Load().(AccountPolicy)
+	return policy
+}
+
+// RequestEmailVerification 向用户发送邮箱验证邮件. 用户不存在或邮箱已验证时不发送邮件，也不返回错误，避免泄露用户是否存在.
+func (b *userBiz) RequestEmailVerification(ctx context.Context, username string) error {
+	u, err := b.s.Users().Get(ctx, username)
+	if err != nil {
+		if errors.IsCode(err, code.ErrUserNotFound) {
+			log.C(ctx).Infof("Skip email verification of nonexistent user %s", username)
+			return nil
+		}
+		return err
+	}
+
+	return b.sendEmailVerification(ctx, u)
+}
+
+// sendEmailVerification 向邮箱尚未验证的用户发送邮箱验证邮件.
+func (b *userBiz) sendEmailVerification(ctx context.Context, u *user.User) error {
+	if u.EmailVerifiedAt != nil {
+		return nil
+	}
+
+	policy := loadAccountPolicy()
+
+	return b.sendAccountMail(ctx, u, verification.PurposeEmailVerification, verification.Fingerprint(u.Email),
+		policy.EmailVerificationTTL, mail.TemplateEmailVerification, policy.VerifyEmailURL)
+}
+
+// VerifyEmail 使用邮箱验证邮件中的令牌验证用户邮箱，令牌签发后用户修改过邮箱时令牌失效.
+func (b *userBiz) VerifyEmail(ctx context.Context, token string) error {
+	claims, u, err := b.parseAccountToken(ctx, token, verification.PurposeEmailVerification)
+	if err != nil {
+		return err
+	}
+	if claims.Binding != verification.Fingerprint(u.Email) {
+		return errors.WithCode(code.ErrVerificationTokenInvalid, "email of user %s has changed", u.Name)
+	}
+	if err := b.consumeAccountToken(ctx, claims); err != nil {
+		return err
+	}
+
+	markEmailVerified(u, time.Now())
+
+	return b.s.Users().UpdateEmailVerified(ctx, u)
+}
+
+// RequestPasswordReset 向用户发送重置密码邮件. 用户不存在时不发送邮件，也不返回错误，避免泄露用户是否存在.
+func (b *userBiz) RequestPasswordReset(ctx context.Context, username string) error {
+	u, err := b.s.Users().Get(ctx, username)
+	if err != nil {
+		if errors.IsCode(err, code.ErrUserNotFound) {
+			log.C(ctx).Infof("Skip password reset of nonexistent user %s", username)
+			return nil
+		}
+		return err
+	}
+
+	policy := loadAccountPolicy()
+
+	return b.sendAccountMail(ctx, u, verification.PurposePasswordReset, verification.Fingerprint(u.Password),
+		policy.PasswordResetTTL, mail.TemplatePasswordReset, policy.ResetPasswordURL)
+}
+
+// ResetPassword 使用重置密码邮件中的令牌将用户密码修改为新密码，并解除账户锁定. 令牌签发后用户修改过密码时令牌失效.
+// 用户能够收到邮件说明邮箱有效，邮箱尚未验证时一并标记为已验证.
+func (b *userBiz) ResetPassword(ctx context.Context, token, newPassword string) error {
+	claims, u, err := b.parseAccountToken(ctx, token, verification.PurposePasswordReset)
+	if err != nil {
+		return err
+	}
+	if claims.Binding != verification.Fingerprint(u.Password) {
+		return errors.WithCode(code.ErrVerificationTokenInvalid, "password of user %s has changed", u.Name)
+	}
+
+	// 新密码不符合密码策略时令牌仍然有效
+	hash, history, err := newPasswordHash(u, newPassword)
+	if err != nil {
+		return err
+	}
+	if err := b.consumeAccountToken(ctx, claims); err != nil {
+		return err
+	}
+
+	if err := b.savePassword(ctx, u, hash, history); err != nil {
+		return err
+	}
+	if err := b.s.Users().Unlock(ctx, u.Name); err != nil {
+		return err
+	}
+	markEmailVerified(u, time.Now())
+
+	return b.s.Users().UpdateEmailVerified(ctx, u)
+}
+
+// sendAccountMail 签发用途为 purpose 的令牌，并使用模板 tmpl 向用户发送包含令牌的邮件.
+// 在 ResendInterval 内重复请求时不发送邮件.
+func (b *userBiz) sendAccountMail(ctx context.Context, u *user.User, purpose verification.Purpose, binding string,
+	ttl time.Duration, tmpl string, baseURL string,
+) error {
+	policy := loadAccountPolicy()
+
+	if policy.ResendInterval > 0 {
+		first, err := b.o.Mark(ctx, "throttle:"+string(purpose)+":"+u.Name, policy.ResendInterval)
+		if err != nil {
+			return errors.WithCode(code.ErrDatabase, "throttle %s mail: %s", purpose, err.Error())
+		}
+		if !first {
+			log.C(ctx).Infof("Skip %s mail to user %s, sent within %s", purpose, u.Name, policy.ResendInterval)
+			return nil
+		}
+	}
+
+	token, claims, err := policy.Signer.Sign(purpose, u.Name, binding, ttl)
+	if err != nil {
+		return errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	msg, err := policy.Templates.Render(tmpl, u.Email, map[string]interface{}{
+		"Username":  u.Name,
+		"Nickname":  u.Nickname,
+		"Token":     token,
+		"Link":      tokenLink(baseURL, token),
+		"ExpiresAt": time.Unix(claims.ExpiresAt, 0).Format(time.RFC3339),
+	})
+	if err != nil {
+		return errors.WithCode(code.ErrUnknown, "render %s mail: %s", purpose, err.Error())
+	}
+
+	if err := b.e.Send(ctx, msg); err != nil {
+		return errors.WithCode(code.ErrUnknown, "send %s mail: %s", purpose, err.Error())
+	}
+	log.C(ctx).Infow("Account mail sent", "username", u.Name, "purpose", purpose)
+
+	return nil
+}
+
+// parseAccountToken 校验令牌，返回令牌中的信息和令牌所属的用户.
+func (b *userBiz) parseAccountToken(ctx context.Context, token string, purpose verification.Purpose,
+) (*verification.Claims, *user.User, error) {
+	claims, err := loadAccountPolicy().Signer.Parse(token, purpose, time.Now())
+	if err != nil {
+		return nil, nil, errors.WithCode(code.ErrVerificationTokenInvalid, err.Error())
+	}
+
+	u, err := b.s.Users().Get(ctx, claims.Username)
+	if err != nil {
+		if errors.IsCode(err, code.ErrUserNotFound) {
+			return nil, nil, errors.WithCode(code.ErrVerificationTokenInvalid, "user %s not found", claims.Username)
+		}
+		return nil, nil, err
+	}
+
+	return claims, u, nil
+}
+
+// consumeAccountToken 将令牌标记为已使用，令牌已被使用过时返回错误.
+func (b *userBiz) consumeAccountToken(ctx context.Context, claims *verification.Claims) error {
+	ttl := time.Until(time.Unix(claims.ExpiresAt, 0))
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+
+	first, err := b.o.Mark(ctx, "used:"+claims.ID, ttl)
+	if err != nil {
+		return errors.WithCode(code.ErrDatabase, "consume %s token: %s", claims.Purpose, err.Error())
+	}
+	if !first {
+		return errors.WithCode(code.ErrVerificationTokenInvalid, "%s token has already been used", claims.Purpose)
+	}
+
+	return nil
+}
+
+// markEmailVerified 将用户邮箱标记为已验证，等待验证邮箱的用户同时变为可用.
+func markEmailVerified(u *user.User, now time.Time) {
+	if u.EmailVerifiedAt == nil {
+		u.EmailVerifiedAt = &now
+	}
+	if u.Status == user.StatusUnverified {
+		u.Status = user.StatusActive
+	}
+}
+
+// tokenLink 将令牌作为 token 查询参数附加到 baseURL 后，baseURL 为空时返回令牌本身.
+func tokenLink(baseURL, token string) string {
+	if baseURL == "" {
+		return token
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return token
+	}
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
@@ -0,0 +1,155 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package biz
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/changaolee/skeleton/internal/apiserver/password"
+	"github.com/changaolee/skeleton/internal/apiserver/store/fake"
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/model/user"
+	"github.com/changaolee/skeleton/pkg/errors"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+)
+
+var linkPattern = regexp.MustCompile(`https://skt\.example\.com/\S+`)
+
+// useTestPolicies 使用降低 bcrypt cost 并开启邮箱验证的策略，测试结束后恢复原策略.
+func useTestPolicies(t *testing.T) {
+	t.Helper()
+
+	oldPassword, oldAccount := loadPasswordPolicy(), loadAccountPolicy()
+	t.Cleanup(func() {
+		SetPasswordPolicy(oldPassword)
+		SetAccountPolicy(oldAccount)
+	})
+
+	passwordPolicy := password.DefaultPolicy()
+	passwordPolicy.BcryptCost = bcrypt.MinCost
+	SetPasswordPolicy(passwordPolicy)
+
+	accountPolicy := oldAccount
+	accountPolicy.RequireVerifiedEmail = true
+	accountPolicy.ResendInterval = 0
+	accountPolicy.VerifyEmailURL = "https://skt.example.com/verify-email"
+	accountPolicy.ResetPasswordURL = "https://skt.example.com/reset-password"
+	SetAccountPolicy(accountPolicy)
+}
+
+// tokenFromMail 返回邮件链接中的令牌.
+func tokenFromMail(t *testing.T, mailer *fakeMailer) string {
+	t.Helper()
+
+	msg := mailer.last()
+	if msg == nil {
+		t.Fatal("no mail sent")
+	}
+	link, err := url.Parse(linkPattern.FindString(msg.Body))
+	if err != nil {
+		t.Fatalf("parse link in mail: %v", err)
+	}
+	token := link.Query().Get("token")
+	if token == "" {
+		t.Fatalf("no token in mail body %q", msg.Body)
+	}
+	return token
+}
+
+func TestSignupVerifyEmailLogin(t *testing.T) {
+	useTestPolicies(t)
+	ctx := context.Background()
+	s := fake.NewStore()
+	mailer := &fakeMailer{}
+	b := newTestUsers(s, mailer)
+
+	u := &user.User{
+		ObjectMeta: metav1.ObjectMeta{Name: "alice"},
+		Status:     user.StatusActive,
+		Nickname:   "alice",
+		Password:   "Secret#123",
+		Email:      "alice@example.com",
+	}
+	if err := b.Create(ctx, u); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if u.Status != user.StatusUnverified {
+		t.Fatalf("Create() status = %d, want %d", u.Status, user.StatusUnverified)
+	}
+
+	if _, err := b.Authenticate(ctx, "alice", "Secret#123", "127.0.0.1"); !errors.IsCode(err, code.ErrEmailNotVerified) {
+		t.Fatalf("Authenticate() before verification error = %v, want ErrEmailNotVerified", err)
+	}
+
+	// 重新发送的邮件同样可以验证邮箱
+	if err := b.RequestEmailVerification(ctx, "alice"); err != nil {
+		t.Fatalf("RequestEmailVerification() error = %v", err)
+	}
+	token := tokenFromMail(t, mailer)
+	if err := b.VerifyEmail(ctx, token); err != nil {
+		t.Fatalf("VerifyEmail() error = %v", err)
+	}
+	if err := b.VerifyEmail(ctx, token); !errors.IsCode(err, code.ErrVerificationTokenInvalid) {
+		t.Errorf("VerifyEmail() with used token error = %v, want ErrVerificationTokenInvalid", err)
+	}
+
+	got, err := b.Authenticate(ctx, "alice", "Secret#123", "127.0.0.1")
+	if err != nil {
+		t.Fatalf("Authenticate() after verification error = %v", err)
+	}
+	if got.Status != user.StatusActive || got.EmailVerifiedAt == nil {
+		t.Errorf("Authenticate() user status = %d, emailVerifiedAt = %v, want verified active user",
+			got.Status, got.EmailVerifiedAt)
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	useTestPolicies(t)
+	ctx := context.Background()
+	s := fake.NewStore()
+	mailer := &fakeMailer{}
+	b := newTestUsers(s, mailer)
+
+	u := &user.User{
+		ObjectMeta: metav1.ObjectMeta{Name: "bob"},
+		Status:     user.StatusActive,
+		Nickname:   "bob",
+		Password:   "Secret#123",
+		Email:      "bob@example.com",
+	}
+	if err := b.Create(ctx, u); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if err := b.RequestPasswordReset(ctx, "bob"); err != nil {
+		t.Fatalf("RequestPasswordReset() error = %v", err)
+	}
+	token := tokenFromMail(t, mailer)
+
+	// 新密码不符合密码策略时令牌仍然可以使用
+	if err := b.ResetPassword(ctx, token, "weak"); !errors.IsCode(err, code.ErrValidation) {
+		t.Fatalf("ResetPassword() with weak password error = %v, want ErrValidation", err)
+	}
+	if err := b.ResetPassword(ctx, token, "NewSecret#456"); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	if err := b.ResetPassword(ctx, token, "Other#789abc"); !errors.IsCode(err, code.ErrVerificationTokenInvalid) {
+		t.Errorf("ResetPassword() with used token error = %v, want ErrVerificationTokenInvalid", err)
+	}
+
+	// 能收到重置密码邮件说明邮箱有效
+	if _, err := b.Authenticate(ctx, "bob", "NewSecret#456", "127.0.0.1"); err != nil {
+		t.Errorf("Authenticate() with new password error = %v", err)
+	}
+	if _, err := b.Authenticate(ctx, "bob", "Secret#123", "127.0.0.1"); !errors.IsCode(err, code.ErrPasswordIncorrect) {
+		t.Errorf("Authenticate() with old password error = %v, want ErrPasswordIncorrect", err)
+	}
+}
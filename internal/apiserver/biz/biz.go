@@ -5,24 +5,53 @@
 
 package biz
 
-import "github.com/changaolee/skeleton/internal/apiserver/store"
+import (
+	"github.com/changaolee/skeleton/internal/apiserver/authn"
+	"github.com/changaolee/skeleton/internal/apiserver/mail"
+	"github.com/changaolee/skeleton/internal/apiserver/mfa"
+	"github.com/changaolee/skeleton/internal/apiserver/publisher"
+	"github.com/changaolee/skeleton/internal/apiserver/store"
+	"github.com/changaolee/skeleton/internal/apiserver/verification"
+	"github.com/changaolee/skeleton/internal/pkg/revocation"
+)
 
 // IBiz 定义了 Biz 层接口.
 type IBiz interface {
 	Users() UserBiz
+	Secrets() SecretBiz
+	Policies() PolicyBiz
 }
 
 type biz struct {
 	s store.IStore
+	p publisher.Publisher
+	r revocation.Revoker
+	v authn.PasswordVerifier
+	m mfa.Challenges
+	e mail.Mailer
+	o verification.Once
 }
 
 var _ IBiz = (*biz)(nil)
 
 // New 创建一个.
 func New(s store.IStore) *biz {
-	return &biz{s: s}
+	v := authn.Get()
+	if v == nil {
+		v = authn.NewLocalVerifier(s)
+	}
+
+	return &biz{s: s, p: publisher.Get(), r: revocation.Get(), v: v, m: mfa.Get(), e: mail.Get(), o: verification.Get()}
 }
 
 func (b *biz) Users() UserBiz {
 	return newUsers(b)
 }
+
+func (b *biz) Secrets() SecretBiz {
+	return newSecrets(b)
+}
+
+func (b *biz) Policies() PolicyBiz {
+	return newPolicies(b)
+}
@@ -0,0 +1,56 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package biz
+
+import (
+	"context"
+	"sync"
+
+	"github.com/changaolee/skeleton/internal/apiserver/authn"
+	"github.com/changaolee/skeleton/internal/apiserver/mail"
+	"github.com/changaolee/skeleton/internal/apiserver/mfa"
+	"github.com/changaolee/skeleton/internal/apiserver/publisher"
+	"github.com/changaolee/skeleton/internal/apiserver/store"
+	"github.com/changaolee/skeleton/internal/apiserver/verification"
+	"github.com/changaolee/skeleton/internal/pkg/revocation"
+)
+
+// fakeMailer 记录发送的邮件.
+type fakeMailer struct {
+	mu       sync.Mutex
+	messages []*mail.Message
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg *mail.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+func (m *fakeMailer) last() *mail.Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if len(m.messages) == 0 {
+		return nil
+	}
+	return m.messages[len(m.messages)-1]
+}
+
+// newTestUsers 创建使用 s 和本地密码校验的 userBiz.
+func newTestUsers(s store.IStore, mailer mail.Mailer) *userBiz {
+	return &userBiz{
+		s: s,
+		p: publisher.Get(),
+		r: revocation.Get(),
+		v: authn.NewLocalVerifier(s),
+		m: mfa.Get(),
+		e: mailer,
+		o: verification.Get(),
+	}
+}
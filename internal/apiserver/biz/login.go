@@ -0,0 +1,280 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package biz
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/changaolee/skeleton/internal/apiserver/authn"
+	"github.com/changaolee/skeleton/internal/apiserver/idp"
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/model/user"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+)
+
+// LoginPolicy 定义了登录失败后锁定账户的策略.
+type LoginPolicy struct {
+	// MaxFailedAttempts 为锁定账户前允许的连续登录失败次数，为 0 时不锁定账户
+	MaxFailedAttempts int
+	// LockoutDuration 为账户被锁定的时长
+	LockoutDuration time.Duration
+}
+
+// loginPolicy 保存当前生效的登录策略，类型为 LoginPolicy.
+var loginPolicy atomic.Value
+
+func init() {
+	loginPolicy.Store(LoginPolicy{})
+}
+
+// SetLoginPolicy 替换登录策略，对之后的登录请求生效.
+func SetLoginPolicy(policy LoginPolicy) {
+	loginPolicy.Store(policy)
+}
+
+// Authenticate 使用 PasswordVerifier 校验用户密码并记录登录结果，ip 为登录请求的来源 IP.
+// 连续登录失败的次数达到 LoginPolicy 的限制后，账户在锁定期间内无法登录.
+// 本地密码超过密码策略的有效期后，需要通过 ChangeExpiredPassword 修改密码才能登录.
+// 通过外部系统校验的用户不存在时自动创建.
+func (b *userBiz) Authenticate(ctx context.Context, username, password, ip string) (*user.User, error) {
+	u, expired, err := b.verifyPassword(ctx, username, password)
+	if err != nil {
+		return nil, err
+	}
+	if expired {
+		return nil, errors.WithCode(code.ErrPasswordExpired, "password of user %s has expired", username)
+	}
+
+	// 需要两步验证的用户在验证码校验通过后才记录登录成功
+	if !b.RequiresMFA(u) {
+		b.recordLoginSuccess(ctx, u, ip, time.Now())
+	}
+
+	return u, nil
+}
+
+// ChangeExpiredPassword 在登录时校验旧密码，并将密码修改为符合密码策略的新密码，用于密码过期后登录.
+func (b *userBiz) ChangeExpiredPassword(ctx context.Context, username, oldPassword, newPassword, ip string) (*user.User, error) {
+	u, _, err := b.verifyPassword(ctx, username, oldPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := b.changePassword(ctx, u, newPassword); err != nil {
+		return nil, err
+	}
+
+	if !b.RequiresMFA(u) {
+		b.recordLoginSuccess(ctx, u, ip, time.Now())
+	}
+
+	return u, nil
+}
+
+// verifyPassword 使用 PasswordVerifier 校验用户密码，密码错误时记录登录失败. expired 表示本地密码已过期.
+func (b *userBiz) verifyPassword(ctx context.Context, username, password string) (*user.User, bool, error) {
+	u, err := b.s.Users().Get(ctx, username)
+	if err != nil {
+		if !errors.IsCode(err, code.ErrUserNotFound) {
+			return nil, false, err
+		}
+		u = nil
+	}
+
+	now := time.Now()
+	if u != nil && u.IsLocked(now) {
+		return nil, false, errors.WithCode(code.ErrUserLocked, "user %s is locked until %s",
+			username, u.LockedUntil.Format(time.RFC3339))
+	}
+
+	account, err := b.v.Verify(ctx, username, password)
+	if err != nil {
+		if !errors.Is(err, authn.ErrInvalidCredentials) && !errors.Is(err, authn.ErrUserNotFound) {
+			return nil, false, errors.WithCode(code.ErrUnknown, "verify password of user %s: %s", username, err.Error())
+		}
+
+		if u != nil {
+			b.recordLoginFailure(ctx, username, now)
+		}
+		// 不区分用户不存在和密码错误，避免泄露用户是否存在
+		return nil, false, errors.WithCode(code.ErrPasswordIncorrect, err.Error())
+	}
+
+	if u == nil {
+		u, err = b.provision(ctx, &user.User{
+			ObjectMeta: metav1.ObjectMeta{Name: account.Username},
+			Nickname:   account.Nickname,
+			Email:      account.Email,
+			Phone:      account.Phone,
+			IsAdmin:    boolToInt(account.IsAdmin != nil && *account.IsAdmin),
+		}, b.v.Name(), nil)
+		if err != nil {
+			return nil, false, err
+		}
+	} else if account.IsAdmin != nil && u.IsAdmin != boolToInt(*account.IsAdmin) {
+		// 管理员权限由外部系统管理时，每次登录同步一次
+		u.IsAdmin = boolToInt(*account.IsAdmin)
+		if err := b.s.Users().UpdateIsAdmin(ctx, u); err != nil {
+			return nil, false, err
+		}
+	}
+
+	if err := checkEmailVerified(u); err != nil {
+		return nil, false, err
+	}
+
+	if !account.Local {
+		return u, false, nil
+	}
+
+	policy := loadPasswordPolicy()
+	if policy.NeedsRehash(u.Password) {
+		b.upgradePasswordHash(ctx, u, password, policy)
+	}
+
+	return u, policy.Expired(u.PasswordSetAt(), now), nil
+}
+
+// FederatedLogin 使用外部身份提供方认证后的身份登录. 通过身份提供方名称和 sub 查找关联的用户，
+// 没有关联的用户时使用身份提供方返回的用户名创建用户并关联. 用户名可以被修改且不保证唯一，
+// 同名的已有用户不会自动关联，避免外部用户通过修改用户名接管本地账户.
+// 与 Authenticate 一样，锁定和邮箱未验证的用户无法登录，需要两步验证的用户由调用方创建登录挑战.
+func (b *userBiz) FederatedLogin(ctx context.Context, identity *idp.Identity, ip string) (*user.User, error) {
+	if identity.Subject == "" {
+		return nil, errors.WithCode(code.ErrFederatedLoginFailed, "no subject in identity from %s", identity.Provider)
+	}
+
+	u, err := b.s.Users().GetByIdentity(ctx, identity.Provider, identity.Subject)
+	if errors.IsCode(err, code.ErrUserNotFound) {
+		u, err = b.provision(ctx, &user.User{
+			ObjectMeta: metav1.ObjectMeta{Name: identity.Username},
+			Nickname:   identity.Nickname,
+			Email:      identity.Email,
+			Phone:      identity.Phone,
+		}, "idp/"+identity.Provider, &user.Identity{Provider: identity.Provider, Subject: identity.Subject})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if u.IsLocked(now) {
+		return nil, errors.WithCode(code.ErrUserLocked, "user %s is locked until %s",
+			u.Name, u.LockedUntil.Format(time.RFC3339))
+	}
+	if err := checkEmailVerified(u); err != nil {
+		return nil, err
+	}
+
+	// 与密码登录一样，需要两步验证的用户在验证码校验通过后才记录登录成功
+	if !b.RequiresMFA(u) {
+		b.recordLoginSuccess(ctx, u, ip, now)
+	}
+
+	return u, nil
+}
+
+// checkEmailVerified 在开启邮箱验证时拒绝邮箱尚未验证的用户登录.
+func checkEmailVerified(u *user.User) error {
+	if u.Status == user.StatusUnverified && loadAccountPolicy().RequireVerifiedEmail {
+		return errors.WithCode(code.ErrEmailNotVerified, "email of user %s has not been verified", u.Name)
+	}
+
+	return nil
+}
+
+// recordLoginFailure 记录一次登录失败，连续失败次数达到 LoginPolicy 的限制时锁定账户.
+func (b *userBiz) recordLoginFailure(ctx context.Context, username string, now time.Time) {
+	policy, _ := loginPolicy.Load().(LoginPolicy)
+	if policy.MaxFailedAttempts <= 0 {
+		return
+	}
+
+	lockedUntil := now.Add(policy.LockoutDuration)
+	if err := b.s.Users().RecordLoginFailure(ctx, username, policy.MaxFailedAttempts, lockedUntil); err != nil {
+		log.C(ctx).Errorf("Record login failure of user %s failed: %s", username, err.Error())
+	}
+}
+
+// recordLoginSuccess 记录一次登录成功，并清零连续失败次数.
+func (b *userBiz) recordLoginSuccess(ctx context.Context, u *user.User, ip string, now time.Time) {
+	if err := b.s.Users().RecordLoginSuccess(ctx, u.Name, ip, now); err != nil {
+		log.C(ctx).Errorf("Record login of user %s failed: %s", u.Name, err.Error())
+	}
+	u.LoginAt = now
+	u.LoginIP = ip
+	u.FailedLoginCount = 0
+	u.LockedUntil = nil
+}
+
+// provision 创建通过外部系统认证的用户，source 为认证来源. 用户使用随机密码，只能通过外部系统登录.
+// identity 不为 nil 时在同一事务中关联外部身份.
+func (b *userBiz) provision(ctx context.Context, u *user.User, source string, identity *user.Identity) (*user.User, error) {
+	password, err := idp.RandomString()
+	if err != nil {
+		return nil, errors.WithCode(code.ErrUnknown, err.Error())
+	}
+
+	if u.Nickname == "" {
+		u.Nickname = u.Name
+	}
+	// 随机密码不需要符合密码策略
+	hash, err := loadPasswordPolicy().Hash(password)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrEncrypt, err.Error())
+	}
+
+	now := time.Now()
+	u.Status = user.StatusActive
+	u.Password = hash
+	u.PasswordChangedAt = &now
+	u.LoginAt = now
+
+	if identity == nil {
+		err = b.s.Users().Create(ctx, u)
+	} else {
+		err = b.s.Users().CreateWithIdentity(ctx, u, identity)
+	}
+	if err != nil {
+		if !errors.IsCode(err, code.ErrUserAlreadyExist) {
+			return nil, err
+		}
+		// 同一用户并发首次登录时，用户可能已被其他请求创建
+		if identity == nil {
+			return b.s.Users().Get(ctx, u.Name)
+		}
+		linked, err := b.s.Users().GetByIdentity(ctx, identity.Provider, identity.Subject)
+		if errors.IsCode(err, code.ErrUserNotFound) {
+			return nil, errors.WithCode(code.ErrFederatedLoginFailed,
+				"user %s already exists and is not linked to identity provider %s", u.Name, identity.Provider)
+		}
+		return linked, err
+	}
+
+	log.C(ctx).Infow("User provisioned", "username", u.Name, "source", source)
+
+	return u, nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// Unlock 解除用户账户的锁定.
+func (b *userBiz) Unlock(ctx context.Context, username string) error {
+	if _, err := b.s.Users().Get(ctx, username); err != nil {
+		return err
+	}
+
+	return b.s.Users().Unlock(ctx, username)
+}
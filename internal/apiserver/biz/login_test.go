@@ -0,0 +1,103 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package biz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/changaolee/skeleton/internal/apiserver/idp"
+	"github.com/changaolee/skeleton/internal/apiserver/store/fake"
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/model/user"
+	"github.com/changaolee/skeleton/pkg/errors"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+)
+
+func TestFederatedLogin(t *testing.T) {
+	useTestPolicies(t)
+	ctx := context.Background()
+	s := fake.NewStore()
+	b := newTestUsers(s, &fakeMailer{})
+
+	admin := &user.User{
+		ObjectMeta: metav1.ObjectMeta{Name: "admin"},
+		Status:     user.StatusActive,
+		Nickname:   "admin",
+		Password:   "Secret#123",
+		Email:      "admin@example.com",
+		IsAdmin:    1,
+	}
+	if err := s.Users().Create(ctx, admin); err != nil {
+		t.Fatal(err)
+	}
+
+	// 用户名与本地用户相同的外部身份不会关联到本地用户
+	_, err := b.FederatedLogin(ctx, &idp.Identity{Provider: "corp", Subject: "s-1", Username: "admin"}, "127.0.0.1")
+	if !errors.IsCode(err, code.ErrFederatedLoginFailed) {
+		t.Fatalf("FederatedLogin() as existing local user error = %v, want ErrFederatedLoginFailed", err)
+	}
+
+	u, err := b.FederatedLogin(ctx, &idp.Identity{Provider: "corp", Subject: "s-2", Username: "carol"}, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("FederatedLogin() error = %v", err)
+	}
+	if u.Name != "carol" || u.IsAdmin != 0 {
+		t.Fatalf("FederatedLogin() = %s (isAdmin %d), want provisioned user carol", u.Name, u.IsAdmin)
+	}
+
+	// 修改用户名后仍然通过 sub 找到同一用户
+	u, err = b.FederatedLogin(ctx, &idp.Identity{Provider: "corp", Subject: "s-2", Username: "admin"}, "127.0.0.1")
+	if err != nil || u.Name != "carol" {
+		t.Fatalf("FederatedLogin() after renaming = (%v, %v), want carol", u, err)
+	}
+
+	// 同一 sub 在其他身份提供方中是不同的身份
+	_, err = b.FederatedLogin(ctx, &idp.Identity{Provider: "other", Subject: "s-2", Username: "carol"}, "127.0.0.1")
+	if !errors.IsCode(err, code.ErrFederatedLoginFailed) {
+		t.Errorf("FederatedLogin() from other provider error = %v, want ErrFederatedLoginFailed", err)
+	}
+}
+
+func TestFederatedLoginGates(t *testing.T) {
+	useTestPolicies(t)
+	ctx := context.Background()
+	s := fake.NewStore()
+	b := newTestUsers(s, &fakeMailer{})
+
+	link := func(name string, status, mfaEnabled int) {
+		t.Helper()
+		u := &user.User{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     status,
+			Nickname:   name,
+			Email:      name + "@example.com",
+			MFAEnabled: mfaEnabled,
+		}
+		if err := s.Users().CreateWithIdentity(ctx, u, &user.Identity{Provider: "corp", Subject: name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	link("dave", user.StatusUnverified, 0)
+	link("erin", user.StatusActive, 1)
+
+	_, err := b.FederatedLogin(ctx, &idp.Identity{Provider: "corp", Subject: "dave", Username: "dave"}, "127.0.0.1")
+	if !errors.IsCode(err, code.ErrEmailNotVerified) {
+		t.Errorf("FederatedLogin() with unverified email error = %v, want ErrEmailNotVerified", err)
+	}
+
+	// 需要两步验证的用户在验证码校验通过前不记录登录成功
+	u, err := b.FederatedLogin(ctx, &idp.Identity{Provider: "corp", Subject: "erin", Username: "erin"}, "127.0.0.1")
+	if err != nil {
+		t.Fatalf("FederatedLogin() error = %v", err)
+	}
+	if !b.RequiresMFA(u) {
+		t.Error("RequiresMFA() = false for federated user with mfa enabled")
+	}
+	if stored, _ := s.Users().Get(ctx, "erin"); stored.LoginIP != "" {
+		t.Errorf("FederatedLogin() recorded login of user requiring mfa from %q", stored.LoginIP)
+	}
+}
@@ -0,0 +1,301 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package biz
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/changaolee/skeleton/internal/apiserver/mfa"
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/model/user"
+	"github.com/changaolee/skeleton/internal/pkg/totp"
+	"github.com/changaolee/skeleton/pkg/auth"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+// MFAPolicy 定义了两步验证策略.
+type MFAPolicy struct {
+	// Issuer 为身份验证器应用中显示的发行方
+	Issuer string
+	// RequireForAdmins 为 true 时管理员必须通过两步验证登录，未开启的管理员在登录时开启
+	RequireForAdmins bool
+	// ChallengeTTL 为通过密码校验后提供验证码的时限
+	ChallengeTTL time.Duration
+	// MaxAttempts 为一次登录最多可以尝试的验证码个数
+	MaxAttempts int
+	// RecoveryCodes 为开启两步验证时生成的恢复码个数
+	RecoveryCodes int
+}
+
+// mfaPolicy 保存当前生效的两步验证策略，类型为 MFAPolicy.
+var mfaPolicy atomic.Value
+
+func init() {
+	mfaPolicy.Store(MFAPolicy{Issuer: "skt", ChallengeTTL: 5 * time.Minute, MaxAttempts: 5, RecoveryCodes: 10})
+}
+
+// SetMFAPolicy 替换两步验证策略，对之后的请求生效.
+func SetMFAPolicy(policy MFAPolicy) {
+	mfaPolicy.Store(policy)
+}
+
+func loadMFAPolicy() MFAPolicy {
+	policy, _ := mfaPolicy.Load().(MFAPolicy)
+	return policy
+}
+
+// RequiresMFA 判断用户登录时是否需要两步验证.
+func (b *userBiz) RequiresMFA(u *user.User) bool {
+	return u.MFAEnabled == 1 || (u.IsAdmin == 1 && loadMFAPolicy().RequireForAdmins)
+}
+
+// CreateMFAChallenge 为通过密码校验的用户创建登录挑战，返回挑战 ID 和过期时间.
+func (b *userBiz) CreateMFAChallenge(ctx context.Context, username string) (string, time.Time, error) {
+	ttl := loadMFAPolicy().ChallengeTTL
+	expire := time.Now().Add(ttl)
+
+	id, err := b.m.Create(ctx, username, ttl)
+	if err != nil {
+		return "", time.Time{}, errors.WithCode(code.ErrDatabase, "create mfa challenge: %s", err.Error())
+	}
+
+	return id, expire, nil
+}
+
+// EnrollMFA 为用户生成新的 TOTP 密钥，用户使用该密钥生成的验证码确认后开启两步验证.
+func (b *userBiz) EnrollMFA(ctx context.Context, username string) (*user.MFAEnrollment, error) {
+	u, err := b.s.Users().Get(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if u.MFAEnabled == 1 {
+		return nil, errors.WithCode(code.ErrMFAAlreadyEnabled, "mfa of user %s is already enabled", username)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.WithCode(code.ErrUnknown, err.Error())
+	}
+	u.TOTPSecret = secret
+	u.RecoveryCodes = nil
+	if err := b.s.Users().UpdateMFA(ctx, u); err != nil {
+		return nil, err
+	}
+
+	return &user.MFAEnrollment{
+		Secret: secret,
+		URI:    totp.URI(loadMFAPolicy().Issuer, username, secret),
+	}, nil
+}
+
+// EnrollMFAWithChallenge 为被要求在登录时开启两步验证的用户生成 TOTP 密钥.
+func (b *userBiz) EnrollMFAWithChallenge(ctx context.Context, challenge string) (*user.MFAEnrollment, error) {
+	username, err := b.attemptChallenge(ctx, challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.EnrollMFA(ctx, username)
+}
+
+// ConfirmMFA 校验使用新 TOTP 密钥生成的验证码，通过后开启两步验证并返回恢复码.
+func (b *userBiz) ConfirmMFA(ctx context.Context, username, passcode string) (*user.MFARecoveryCodes, error) {
+	u, err := b.s.Users().Get(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.confirmMFA(ctx, u, passcode)
+}
+
+func (b *userBiz) confirmMFA(ctx context.Context, u *user.User, passcode string) (*user.MFARecoveryCodes, error) {
+	if u.MFAEnabled == 1 {
+		return nil, errors.WithCode(code.ErrMFAAlreadyEnabled, "mfa of user %s is already enabled", u.Name)
+	}
+	if u.TOTPSecret == "" {
+		return nil, errors.WithCode(code.ErrMFANotEnrolled, "mfa enrollment of user %s has not been started", u.Name)
+	}
+	if err := b.verifyTOTP(ctx, u, passcode); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes(loadMFAPolicy().RecoveryCodes)
+	if err != nil {
+		return nil, err
+	}
+	u.MFAEnabled = 1
+	u.RecoveryCodes = hashes
+	if err := b.s.Users().UpdateMFA(ctx, u); err != nil {
+		return nil, err
+	}
+
+	return &user.MFARecoveryCodes{RecoveryCodes: codes}, nil
+}
+
+// VerifyMFA 校验已开启两步验证的用户提供的验证码或恢复码，恢复码使用后失效.
+func (b *userBiz) VerifyMFA(ctx context.Context, username, passcode string) error {
+	u, err := b.s.Users().Get(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	return b.verifyMFA(ctx, u, passcode)
+}
+
+func (b *userBiz) verifyMFA(ctx context.Context, u *user.User, passcode string) error {
+	if u.MFAEnabled != 1 {
+		return errors.WithCode(code.ErrMFANotEnrolled, "mfa of user %s is not enabled", u.Name)
+	}
+
+	if len(passcode) == totp.Digits {
+		return b.verifyTOTP(ctx, u, passcode)
+	}
+
+	normalized := normalizeRecoveryCode(passcode)
+	for _, hash := range u.RecoveryCodes {
+		if auth.Compare(hash, normalized) != nil {
+			continue
+		}
+
+		// u 可能已经过期，由 store 原子地删除恢复码，恢复码被并发使用时只有一个请求成功
+		consumed, err := b.s.Users().ConsumeRecoveryCode(ctx, u.Name, hash)
+		if err != nil {
+			return err
+		}
+		if !consumed {
+			return errors.WithCode(code.ErrMFACodeInvalid, "recovery code for user %s has already been used", u.Name)
+		}
+		log.C(ctx).Infof("User %s used a recovery code", u.Name)
+
+		return nil
+	}
+
+	return errors.WithCode(code.ErrMFACodeInvalid, "invalid mfa code for user %s", u.Name)
+}
+
+// verifyTOTP 校验 TOTP 验证码，每个验证码只能使用一次.
+func (b *userBiz) verifyTOTP(ctx context.Context, u *user.User, passcode string) error {
+	step, ok := totp.Validate(u.TOTPSecret, passcode, time.Now())
+	if !ok {
+		return errors.WithCode(code.ErrMFACodeInvalid, "invalid mfa code for user %s", u.Name)
+	}
+
+	recorded, err := b.s.Users().RecordTOTPStep(ctx, u.Name, step)
+	if err != nil {
+		return err
+	}
+	if !recorded {
+		return errors.WithCode(code.ErrMFACodeInvalid, "mfa code for user %s has already been used", u.Name)
+	}
+
+	return nil
+}
+
+// LoginWithMFA 校验登录挑战和验证码，通过后完成登录. 被要求在登录时开启两步验证的用户，
+// 使用验证码确认开启，并返回恢复码. 验证码错误计入连续登录失败次数.
+func (b *userBiz) LoginWithMFA(ctx context.Context, challenge, passcode, ip string) (*user.User, *user.MFARecoveryCodes, error) {
+	username, err := b.attemptChallenge(ctx, challenge)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	u, err := b.s.Users().Get(ctx, username)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if u.IsLocked(now) {
+		return nil, nil, errors.WithCode(code.ErrUserLocked, "user %s is locked until %s",
+			username, u.LockedUntil.Format(time.RFC3339))
+	}
+
+	var recoveryCodes *user.MFARecoveryCodes
+	if u.MFAEnabled == 1 {
+		err = b.verifyMFA(ctx, u, passcode)
+	} else {
+		recoveryCodes, err = b.confirmMFA(ctx, u, passcode)
+	}
+	if err != nil {
+		if errors.IsCode(err, code.ErrMFACodeInvalid) {
+			b.recordLoginFailure(ctx, username, now)
+		}
+		return nil, nil, err
+	}
+
+	consumed, err := b.m.Consume(ctx, challenge)
+	if err != nil {
+		return nil, nil, errors.WithCode(code.ErrDatabase, "consume mfa challenge: %s", err.Error())
+	}
+	if !consumed {
+		return nil, nil, errors.WithCode(code.ErrMFAChallengeInvalid, "mfa challenge has already been used")
+	}
+
+	b.recordLoginSuccess(ctx, u, ip, now)
+
+	return u, recoveryCodes, nil
+}
+
+// DisableMFA 关闭用户的两步验证，删除 TOTP 密钥和恢复码.
+func (b *userBiz) DisableMFA(ctx context.Context, username string) error {
+	u, err := b.s.Users().Get(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	u.MFAEnabled = 0
+	u.TOTPSecret = ""
+	u.RecoveryCodes = nil
+
+	return b.s.Users().UpdateMFA(ctx, u)
+}
+
+func (b *userBiz) attemptChallenge(ctx context.Context, challenge string) (string, error) {
+	username, err := b.m.Attempt(ctx, challenge, loadMFAPolicy().MaxAttempts)
+	if err != nil {
+		if errors.Is(err, mfa.ErrChallengeNotFound) {
+			return "", errors.WithCode(code.ErrMFAChallengeInvalid, err.Error())
+		}
+		return "", errors.WithCode(code.ErrDatabase, "attempt mfa challenge: %s", err.Error())
+	}
+
+	return username, nil
+}
+
+// generateRecoveryCodes 生成 n 个恢复码，返回展示给用户的恢复码和加密后的恢复码.
+func generateRecoveryCodes(n int) ([]string, []string, error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+
+	codes := make([]string, 0, n)
+	hashes := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		b := make([]byte, 7)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, errors.WithCode(code.ErrUnknown, err.Error())
+		}
+		raw := strings.ToLower(encoding.EncodeToString(b))[:10]
+
+		hash, err := auth.Encrypt(raw)
+		if err != nil {
+			return nil, nil, errors.WithCode(code.ErrEncrypt, err.Error())
+		}
+		codes = append(codes, raw[:5]+"-"+raw[5:])
+		hashes = append(hashes, hash)
+	}
+
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode 忽略恢复码中的大小写、空格和连字符.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,54 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package biz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/changaolee/skeleton/internal/apiserver/store/fake"
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/model/user"
+	"github.com/changaolee/skeleton/pkg/errors"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+)
+
+func TestVerifyMFA_RecoveryCodeUsedOnce(t *testing.T) {
+	useTestPolicies(t)
+	ctx := context.Background()
+	s := fake.NewStore()
+	b := newTestUsers(s, &fakeMailer{})
+
+	codes, hashes, err := generateRecoveryCodes(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Users().Create(ctx, &user.User{
+		ObjectMeta:    metav1.ObjectMeta{Name: "alice"},
+		Status:        user.StatusActive,
+		Email:         "alice@example.com",
+		MFAEnabled:    1,
+		TOTPSecret:    "JBSWY3DPEHPK3PXP",
+		RecoveryCodes: hashes,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 两个并发的请求都在恢复码被使用之前读取了用户
+	first, _ := s.Users().Get(ctx, "alice")
+	second, _ := s.Users().Get(ctx, "alice")
+
+	if err := b.verifyMFA(ctx, first, codes[0]); err != nil {
+		t.Fatalf("verifyMFA() error = %v", err)
+	}
+	if err := b.verifyMFA(ctx, second, codes[0]); !errors.IsCode(err, code.ErrMFACodeInvalid) {
+		t.Fatalf("verifyMFA() with used recovery code error = %v, want ErrMFACodeInvalid", err)
+	}
+	if err := b.verifyMFA(ctx, second, codes[1]); err != nil {
+		t.Fatalf("verifyMFA() with another recovery code error = %v", err)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package biz
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/changaolee/skeleton/internal/apiserver/password"
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/model/user"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+	"github.com/changaolee/skeleton/pkg/validation/field"
+)
+
+// passwordPolicy 保存当前生效的密码策略，类型为 password.Policy.
+var passwordPolicy atomic.Value
+
+func init() {
+	passwordPolicy.Store(password.DefaultPolicy())
+}
+
+// SetPasswordPolicy 替换密码策略，对之后的请求生效.
+func SetPasswordPolicy(policy password.Policy) {
+	passwordPolicy.Store(policy)
+}
+
+func loadPasswordPolicy() password.Policy {
+	policy, _ := passwordPolicy.Load().(password.Policy)
+	return policy
+}
+
+// hashNewPassword 校验新用户的密码是否符合密码策略，通过后加密保存到 u 中.
+func hashNewPassword(u *user.User, now time.Time) error {
+	policy := loadPasswordPolicy()
+	if errs := policy.Validate(field.NewPath("password"), u.Password); len(errs) != 0 {
+		return errors.WithCode(code.ErrValidation, errs.ToAggregate().Error())
+	}
+
+	hash, err := policy.Hash(u.Password)
+	if err != nil {
+		return errors.WithCode(code.ErrEncrypt, err.Error())
+	}
+	u.Password = hash
+	u.PasswordChangedAt = &now
+	u.PasswordHistory = nil
+
+	return nil
+}
+
+// changePassword 校验新密码后保存新密码，并吊销用户已签发的 token.
+func (b *userBiz) changePassword(ctx context.Context, u *user.User, newPassword string) error {
+	hash, history, err := newPasswordHash(u, newPassword)
+	if err != nil {
+		return err
+	}
+
+	return b.savePassword(ctx, u, hash, history)
+}
+
+// newPasswordHash 校验新密码是否符合密码策略且没有重复使用最近的密码，返回新密码的密文和加入原密码后的历史密码.
+func newPasswordHash(u *user.User, newPassword string) (string, []string, error) {
+	policy := loadPasswordPolicy()
+	fldPath := field.NewPath("newPassword")
+
+	recent := append([]string{u.Password}, u.PasswordHistory...)
+	errs := policy.Validate(fldPath, newPassword)
+	errs = append(errs, policy.ValidateReuse(fldPath, newPassword, recent)...)
+	if len(errs) != 0 {
+		return "", nil, errors.WithCode(code.ErrValidation, errs.ToAggregate().Error())
+	}
+
+	hash, err := policy.Hash(newPassword)
+	if err != nil {
+		return "", nil, errors.WithCode(code.ErrEncrypt, err.Error())
+	}
+
+	// 历史密码与新密码一起共 HistorySize 个
+	var history []string
+	if policy.HistorySize > 1 {
+		history = recent
+		if len(history) > policy.HistorySize-1 {
+			history = history[:policy.HistorySize-1]
+		}
+	}
+
+	return hash, history, nil
+}
+
+// savePassword 保存新密码的密文和历史密码，并吊销用户已签发的 token.
+func (b *userBiz) savePassword(ctx context.Context, u *user.User, hash string, history []string) error {
+	now := time.Now()
+	u.Password = hash
+	u.PasswordChangedAt = &now
+	u.PasswordHistory = history
+	if err := b.s.Users().UpdatePassword(ctx, u); err != nil {
+		return err
+	}
+
+	b.revokeTokens(ctx, u.Name)
+	return nil
+}
+
+// upgradePasswordHash 使用当前密码策略的 bcrypt cost 重新加密用户密码，失败时只记录日志.
+func (b *userBiz) upgradePasswordHash(ctx context.Context, u *user.User, plaintext string, policy password.Policy) {
+	hash, err := policy.Hash(plaintext)
+	if err != nil {
+		log.C(ctx).Errorf("Rehash password of user %s failed: %s", u.Name, err.Error())
+		return
+	}
+
+	u.Password = hash
+	if err := b.s.Users().UpdatePassword(ctx, u); err != nil {
+		log.C(ctx).Errorf("Save rehashed password of user %s failed: %s", u.Name, err.Error())
+		return
+	}
+	log.C(ctx).Infof("Upgraded password hash of user %s to bcrypt cost %d", u.Name, policy.BcryptCost)
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package biz
+
+import (
+	"context"
+
+	"github.com/changaolee/skeleton/internal/apiserver/publisher"
+	"github.com/changaolee/skeleton/internal/apiserver/store"
+	mp "github.com/changaolee/skeleton/internal/pkg/model/policy"
+	"github.com/changaolee/skeleton/internal/pkg/notification"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+)
+
+type PolicyBiz interface {
+	Create(ctx context.Context, policy *mp.Policy) error
+	Update(ctx context.Context, policy *mp.Policy) error
+	Delete(ctx context.Context, username, name string) error
+	Get(ctx context.Context, username, name string) (*mp.Policy, error)
+	List(ctx context.Context, username string, opts metav1.ListOptions) (*mp.PolicyList, error)
+}
+
+type policyBiz struct {
+	s store.IStore
+	p publisher.Publisher
+}
+
+var _ PolicyBiz = (*policyBiz)(nil)
+
+func newPolicies(b *biz) *policyBiz {
+	return &policyBiz{s: b.s, p: b.p}
+}
+
+func (b *policyBiz) Create(ctx context.Context, policy *mp.Policy) error {
+	if err := b.s.Policies().Create(ctx, policy); err != nil {
+		return err
+	}
+
+	b.notify(notification.ActionCreate, policy.Username, policy.Name)
+	return nil
+}
+
+func (b *policyBiz) Update(ctx context.Context, policy *mp.Policy) error {
+	if err := b.s.Policies().Update(ctx, policy); err != nil {
+		return err
+	}
+
+	b.notify(notification.ActionUpdate, policy.Username, policy.Name)
+	return nil
+}
+
+func (b *policyBiz) Delete(ctx context.Context, username, name string) error {
+	if err := b.s.Policies().Delete(ctx, username, name); err != nil {
+		return err
+	}
+
+	b.notify(notification.ActionDelete, username, name)
+	return nil
+}
+
+func (b *policyBiz) Get(ctx context.Context, username, name string) (*mp.Policy, error) {
+	return b.s.Policies().Get(ctx, username, name)
+}
+
+func (b *policyBiz) List(ctx context.Context, username string, opts metav1.ListOptions) (*mp.PolicyList, error) {
+	return b.s.Policies().List(ctx, username, opts)
+}
+
+// notify 在 policy 变更提交后通知 skt-authz-server 重新加载缓存.
+func (b *policyBiz) notify(action, username, name string) {
+	b.p.Publish(notification.NoticePolicyChanged, notification.Payload{
+		Action:   action,
+		Username: username,
+		Names:    []string{name},
+	})
+}
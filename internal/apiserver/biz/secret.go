@@ -0,0 +1,80 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package biz
+
+import (
+	"context"
+
+	"github.com/changaolee/skeleton/internal/apiserver/publisher"
+	"github.com/changaolee/skeleton/internal/apiserver/store"
+	ms "github.com/changaolee/skeleton/internal/pkg/model/secret"
+	"github.com/changaolee/skeleton/internal/pkg/notification"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+)
+
+type SecretBiz interface {
+	// Create 创建 secret，用户的 secret 数量达到 maxCount 时返回 ErrReachMaxCount.
+	Create(ctx context.Context, secret *ms.Secret, maxCount int) error
+	Update(ctx context.Context, secret *ms.Secret) error
+	Delete(ctx context.Context, username, name string) error
+	Get(ctx context.Context, username, name string) (*ms.Secret, error)
+	List(ctx context.Context, username string, opts metav1.ListOptions) (*ms.SecretList, error)
+}
+
+type secretBiz struct {
+	s store.IStore
+	p publisher.Publisher
+}
+
+var _ SecretBiz = (*secretBiz)(nil)
+
+func newSecrets(b *biz) *secretBiz {
+	return &secretBiz{s: b.s, p: b.p}
+}
+
+func (b *secretBiz) Create(ctx context.Context, secret *ms.Secret, maxCount int) error {
+	if err := b.s.Secrets().CreateWithQuota(ctx, secret, maxCount); err != nil {
+		return err
+	}
+
+	b.notify(notification.ActionCreate, secret.Username, secret.Name)
+	return nil
+}
+
+func (b *secretBiz) Update(ctx context.Context, secret *ms.Secret) error {
+	if err := b.s.Secrets().Update(ctx, secret); err != nil {
+		return err
+	}
+
+	b.notify(notification.ActionUpdate, secret.Username, secret.Name)
+	return nil
+}
+
+func (b *secretBiz) Delete(ctx context.Context, username, name string) error {
+	if err := b.s.Secrets().Delete(ctx, username, name); err != nil {
+		return err
+	}
+
+	b.notify(notification.ActionDelete, username, name)
+	return nil
+}
+
+func (b *secretBiz) Get(ctx context.Context, username, name string) (*ms.Secret, error) {
+	return b.s.Secrets().Get(ctx, username, name)
+}
+
+func (b *secretBiz) List(ctx context.Context, username string, opts metav1.ListOptions) (*ms.SecretList, error) {
+	return b.s.Secrets().List(ctx, username, opts)
+}
+
+// notify 在 secret 变更提交后通知 skt-authz-server 重新加载缓存.
+func (b *secretBiz) notify(action, username, name string) {
+	b.p.Publish(notification.NoticeSecretChanged, notification.Payload{
+		Action:   action,
+		Username: username,
+		Names:    []string{name},
+	})
+}
@@ -7,30 +7,163 @@ package biz
 
 import (
 	"context"
+	"time"
 
+	"github.com/changaolee/skeleton/internal/apiserver/authn"
+	"github.com/changaolee/skeleton/internal/apiserver/idp"
+	"github.com/changaolee/skeleton/internal/apiserver/mail"
+	"github.com/changaolee/skeleton/internal/apiserver/mfa"
+	"github.com/changaolee/skeleton/internal/apiserver/publisher"
 	"github.com/changaolee/skeleton/internal/apiserver/store"
+	"github.com/changaolee/skeleton/internal/apiserver/verification"
+	"github.com/changaolee/skeleton/internal/pkg/code"
 	"github.com/changaolee/skeleton/internal/pkg/model/user"
+	"github.com/changaolee/skeleton/internal/pkg/notification"
+	"github.com/changaolee/skeleton/internal/pkg/revocation"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
 )
 
 type UserBiz interface {
 	Create(ctx context.Context, user *user.User) error
+	Update(ctx context.Context, user *user.User) error
+	Delete(ctx context.Context, username string) error
+	DeleteCollection(ctx context.Context, usernames []string) error
 	Get(ctx context.Context, username string) (*user.User, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*user.UserList, error)
+	ChangePassword(ctx context.Context, username, oldPassword, newPassword string) error
+	Authenticate(ctx context.Context, username, password, ip string) (*user.User, error)
+	ChangeExpiredPassword(ctx context.Context, username, oldPassword, newPassword, ip string) (*user.User, error)
+	FederatedLogin(ctx context.Context, identity *idp.Identity, ip string) (*user.User, error)
+	Unlock(ctx context.Context, username string) error
+	RequiresMFA(u *user.User) bool
+	CreateMFAChallenge(ctx context.Context, username string) (string, time.Time, error)
+	EnrollMFA(ctx context.Context, username string) (*user.MFAEnrollment, error)
+	EnrollMFAWithChallenge(ctx context.Context, challenge string) (*user.MFAEnrollment, error)
+	ConfirmMFA(ctx context.Context, username, code string) (*user.MFARecoveryCodes, error)
+	VerifyMFA(ctx context.Context, username, code string) error
+	LoginWithMFA(ctx context.Context, challenge, code, ip string) (*user.User, *user.MFARecoveryCodes, error)
+	DisableMFA(ctx context.Context, username string) error
+	RevokeTokens(ctx context.Context, username string) error
+	RequestEmailVerification(ctx context.Context, username string) error
+	VerifyEmail(ctx context.Context, token string) error
+	RequestPasswordReset(ctx context.Context, username string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
 }
 
 type userBiz struct {
 	s store.IStore
+	p publisher.Publisher
+	r revocation.Revoker
+	v authn.PasswordVerifier
+	m mfa.Challenges
+	e mail.Mailer
+	o verification.Once
 }
 
 var _ UserBiz = (*userBiz)(nil)
 
 func newUsers(b *biz) *userBiz {
-	return &userBiz{s: b.s}
+	return &userBiz{s: b.s, p: b.p, r: b.r, v: b.v, m: b.m, e: b.e, o: b.o}
 }
 
-func (b *userBiz) Create(ctx context.Context, user *user.User) error {
-	return b.s.Users().Create(ctx, user)
+// Create 校验用户密码是否符合密码策略，加密密码后创建用户，并向用户发送邮箱验证邮件.
+// 开启邮箱验证时，用户在验证邮箱前无法登录.
+func (b *userBiz) Create(ctx context.Context, u *user.User) error {
+	if err := hashNewPassword(u, time.Now()); err != nil {
+		return err
+	}
+	if u.Status == user.StatusActive && loadAccountPolicy().RequireVerifiedEmail {
+		u.Status = user.StatusUnverified
+	}
+
+	if err := b.s.Users().Create(ctx, u); err != nil {
+		return err
+	}
+
+	if err := b.sendEmailVerification(ctx, u); err != nil {
+		log.C(ctx).Errorf("Send email verification to user %s failed: %s", u.Name, err.Error())
+	}
+
+	return nil
+}
+
+func (b *userBiz) Update(ctx context.Context, user *user.User) error {
+	return b.s.Users().Update(ctx, user)
+}
+
+// Delete 删除用户，用户拥有的 secret 和 policy 会在同一事务中删除，提交后发布变更通知并吊销用户的 token.
+func (b *userBiz) Delete(ctx context.Context, username string) error {
+	if err := b.s.Users().Delete(ctx, username); err != nil {
+		return err
+	}
+
+	b.notifyResourcesDeleted(username)
+	b.revokeTokens(ctx, username)
+	return nil
+}
+
+// DeleteCollection 批量删除用户，提交后为每个用户发布变更通知并吊销用户的 token.
+func (b *userBiz) DeleteCollection(ctx context.Context, usernames []string) error {
+	if err := b.s.Users().DeleteCollection(ctx, usernames); err != nil {
+		return err
+	}
+
+	for _, username := range usernames {
+		b.notifyResourcesDeleted(username)
+		b.revokeTokens(ctx, username)
+	}
+	return nil
+}
+
+// notifyResourcesDeleted 通知 skt-authz-server 用户的 secret 和 policy 已被删除.
+func (b *userBiz) notifyResourcesDeleted(username string) {
+	payload := notification.Payload{
+		Action:   notification.ActionDelete,
+		Username: username,
+	}
+	b.p.Publish(notification.NoticeSecretChanged, payload)
+	b.p.Publish(notification.NoticePolicyChanged, payload)
 }
 
 func (b *userBiz) Get(ctx context.Context, username string) (*user.User, error) {
 	return b.s.Users().Get(ctx, username)
 }
+
+func (b *userBiz) List(ctx context.Context, opts metav1.ListOptions) (*user.UserList, error) {
+	return b.s.Users().List(ctx, opts)
+}
+
+// ChangePassword 校验旧密码后，将用户密码修改为符合密码策略的新密码，并吊销用户已签发的 token.
+func (b *userBiz) ChangePassword(ctx context.Context, username, oldPassword, newPassword string) error {
+	u, err := b.s.Users().Get(ctx, username)
+	if err != nil {
+		return err
+	}
+
+	if err := u.Compare(oldPassword); err != nil {
+		return errors.WithCode(code.ErrPasswordIncorrect, err.Error())
+	}
+
+	return b.changePassword(ctx, u, newPassword)
+}
+
+// RevokeTokens 吊销用户已签发的所有 token.
+func (b *userBiz) RevokeTokens(ctx context.Context, username string) error {
+	if _, err := b.s.Users().Get(ctx, username); err != nil {
+		return err
+	}
+
+	if err := b.r.RevokeUser(ctx, username); err != nil {
+		return errors.WithCode(code.ErrDatabase, "revoke tokens of user %s failed: %s", username, err.Error())
+	}
+	return nil
+}
+
+// revokeTokens 在用户数据变更成功后吊销用户的 token，失败时只记录日志.
+func (b *userBiz) revokeTokens(ctx context.Context, username string) {
+	if err := b.r.RevokeUser(ctx, username); err != nil {
+		log.C(ctx).Errorf("Revoke tokens of user %s failed: %s", username, err.Error())
+	}
+}
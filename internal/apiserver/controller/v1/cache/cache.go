@@ -0,0 +1,93 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package cache
+
+import (
+	"context"
+	"time"
+
+	"github.com/changaolee/skeleton/internal/apiserver/store"
+	"github.com/changaolee/skeleton/pkg/log"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+	pb "github.com/changaolee/skeleton/pkg/proto/apiserver/v1"
+)
+
+// CacheController 实现了 pb.CacheServer 接口，为 skt-authz-server 提供 secrets 和 policies.
+type CacheController struct {
+	s store.IStore
+}
+
+var _ pb.CacheServer = (*CacheController)(nil)
+
+// NewCacheController 创建一个 cache controller.
+func NewCacheController(s store.IStore) *CacheController {
+	return &CacheController{s: s}
+}
+
+// ListSecrets 返回全部用户的 secret 列表.
+func (cc *CacheController) ListSecrets(ctx context.Context, r *pb.ListSecretsRequest) (*pb.ListSecretsResponse, error) {
+	log.C(ctx).Infow("ListSecrets function called.")
+
+	opts := metav1.ListOptions{
+		Offset: r.Offset,
+		Limit:  r.Limit,
+	}
+
+	secrets, err := cc.s.Secrets().List(ctx, "", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*pb.SecretInfo, 0, len(secrets.Items))
+	for _, secret := range secrets.Items {
+		items = append(items, &pb.SecretInfo{
+			Name:        secret.Name,
+			SecretId:    secret.SecretID,
+			Username:    secret.Username,
+			SecretKey:   secret.SecretKey,
+			Expires:     secret.Expires,
+			Description: secret.Description,
+			CreatedAt:   secret.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:   secret.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return &pb.ListSecretsResponse{
+		TotalCount: secrets.TotalCount,
+		Items:      items,
+	}, nil
+}
+
+// ListPolicies 返回全部用户的 policy 列表.
+func (cc *CacheController) ListPolicies(ctx context.Context, r *pb.ListPoliciesRequest) (*pb.ListPoliciesResponse, error) {
+	log.C(ctx).Infow("ListPolicies function called.")
+
+	opts := metav1.ListOptions{
+		Offset: r.Offset,
+		Limit:  r.Limit,
+	}
+
+	policies, err := cc.s.Policies().List(ctx, "", opts)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*pb.PolicyInfo, 0, len(policies.Items))
+	for _, policy := range policies.Items {
+		items = append(items, &pb.PolicyInfo{
+			Name:         policy.Name,
+			Username:     policy.Username,
+			PolicyStr:    policy.Policy.String(),
+			PolicyShadow: policy.PolicyShadow,
+			CreatedAt:    policy.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return &pb.ListPoliciesResponse{
+		TotalCount: policies.TotalCount,
+		Items:      items,
+	}, nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package policy
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
+	mp "github.com/changaolee/skeleton/internal/pkg/model/policy"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+// Create 为当前用户创建一个新的授权策略.
+func (p *PolicyController) Create(c *gin.Context) {
+	log.C(c).Infow("Create policy function called.")
+
+	var r mp.Policy
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+		return
+	}
+
+	if errs := r.Validate(); len(errs) != 0 {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, errs.ToAggregate().Error()), nil)
+		return
+	}
+
+	r.Username = c.GetString(middleware.UsernameKey)
+
+	if err := p.b.Policies().Create(c, &r); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, r)
+}
@@ -0,0 +1,26 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package policy
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+// Delete 删除当前用户指定名称的授权策略.
+func (p *PolicyController) Delete(c *gin.Context) {
+	log.C(c).Infow("Delete policy function called.")
+
+	if err := p.b.Policies().Delete(c, c.GetString(middleware.UsernameKey), c.Param("name")); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
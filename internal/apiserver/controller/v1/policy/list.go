@@ -0,0 +1,36 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package policy
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+)
+
+// List 分页查询当前用户的授权策略列表.
+func (p *PolicyController) List(c *gin.Context) {
+	log.C(c).Infow("List policy function called.")
+
+	var r metav1.ListOptions
+	if err := c.ShouldBindQuery(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+		return
+	}
+
+	policies, err := p.b.Policies().List(c, c.GetString(middleware.UsernameKey), r)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, policies)
+}
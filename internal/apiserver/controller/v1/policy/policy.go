@@ -0,0 +1,20 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package policy
+
+import (
+	"github.com/changaolee/skeleton/internal/apiserver/biz"
+	"github.com/changaolee/skeleton/internal/apiserver/store"
+)
+
+type PolicyController struct {
+	b biz.IBiz
+}
+
+// NewPolicyController 创建一个 policy controller.
+func NewPolicyController(s store.IStore) *PolicyController {
+	return &PolicyController{b: biz.New(s)}
+}
@@ -0,0 +1,50 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package policy
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
+	mp "github.com/changaolee/skeleton/internal/pkg/model/policy"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+// Update 更新当前用户指定的授权策略.
+func (p *PolicyController) Update(c *gin.Context) {
+	log.C(c).Infow("Update policy function called.")
+
+	var r mp.Policy
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+		return
+	}
+
+	pol, err := p.b.Policies().Get(c, c.GetString(middleware.UsernameKey), c.Param("name"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	// 仅允许更新授权策略内容和扩展字段
+	pol.Policy = r.Policy
+	pol.Extend = r.Extend
+
+	if errs := pol.Validate(); len(errs) != 0 {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, errs.ToAggregate().Error()), nil)
+		return
+	}
+
+	if err := p.b.Policies().Update(c, pol); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, pol)
+}
@@ -0,0 +1,46 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package secret
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
+	ms "github.com/changaolee/skeleton/internal/pkg/model/secret"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+	"github.com/changaolee/skeleton/pkg/util/idutil"
+)
+
+// Create 为当前用户创建一个新的 secret.
+func (s *SecretController) Create(c *gin.Context) {
+	log.C(c).Infow("Create secret function called.")
+
+	var r ms.Secret
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+		return
+	}
+
+	if errs := r.Validate(); len(errs) != 0 {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, errs.ToAggregate().Error()), nil)
+		return
+	}
+
+	r.Username = c.GetString(middleware.UsernameKey)
+	r.SecretID = idutil.NewSecretID()
+	r.SecretKey = idutil.NewSecretKey()
+
+	// 用户的 secret 数量达到上限时创建失败
+	if err := s.b.Secrets().Create(c, &r, s.maxCount); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, r)
+}
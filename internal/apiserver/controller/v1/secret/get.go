@@ -0,0 +1,27 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package secret
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+// Get 查询当前用户指定名称的 secret.
+func (s *SecretController) Get(c *gin.Context) {
+	log.C(c).Infow("Get secret function called.")
+
+	secret, err := s.b.Secrets().Get(c, c.GetString(middleware.UsernameKey), c.Param("name"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, secret)
+}
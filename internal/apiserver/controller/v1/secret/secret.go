@@ -0,0 +1,24 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package secret
+
+import (
+	"github.com/changaolee/skeleton/internal/apiserver/biz"
+	"github.com/changaolee/skeleton/internal/apiserver/store"
+)
+
+type SecretController struct {
+	b        biz.IBiz
+	maxCount int
+}
+
+// NewSecretController 创建一个 secret controller，maxCount 为每个用户可创建 secret 的最大数量.
+func NewSecretController(s store.IStore, maxCount int) *SecretController {
+	return &SecretController{
+		b:        biz.New(s),
+		maxCount: maxCount,
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package secret
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
+	ms "github.com/changaolee/skeleton/internal/pkg/model/secret"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+// Update 更新当前用户指定 secret 的描述和过期时间.
+func (s *SecretController) Update(c *gin.Context) {
+	log.C(c).Infow("Update secret function called.")
+
+	var r ms.Secret
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+		return
+	}
+
+	secret, err := s.b.Secrets().Get(c, c.GetString(middleware.UsernameKey), c.Param("name"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	// 仅允许更新描述和过期时间
+	secret.Description = r.Description
+	secret.Expires = r.Expires
+	secret.Extend = r.Extend
+
+	if errs := secret.Validate(); len(errs) != 0 {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, errs.ToAggregate().Error()), nil)
+		return
+	}
+
+	if err := s.b.Secrets().Update(c, secret); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, secret)
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package user
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	mu "github.com/changaolee/skeleton/internal/pkg/model/user"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+// RequestEmailVerification 向用户发送邮箱验证邮件. 无论用户是否存在都返回成功.
+func (u *UserController) RequestEmailVerification(c *gin.Context) {
+	log.C(c).Infow("Request email verification function called.")
+
+	var r mu.AccountMailRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+		return
+	}
+
+	if err := u.b.Users().RequestEmailVerification(c, r.Username); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
+
+// VerifyEmail 使用邮箱验证邮件中的令牌验证用户邮箱.
+func (u *UserController) VerifyEmail(c *gin.Context) {
+	log.C(c).Infow("Verify email function called.")
+
+	var r mu.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+		return
+	}
+
+	if err := u.b.Users().VerifyEmail(c, r.Token); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
+
+// RequestPasswordReset 向用户发送重置密码邮件. 无论用户是否存在都返回成功.
+func (u *UserController) RequestPasswordReset(c *gin.Context) {
+	log.C(c).Infow("Request password reset function called.")
+
+	var r mu.AccountMailRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+		return
+	}
+
+	if err := u.b.Users().RequestPasswordReset(c, r.Username); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
+
+// ResetPassword 使用重置密码邮件中的令牌修改用户密码.
+func (u *UserController) ResetPassword(c *gin.Context) {
+	log.C(c).Infow("Reset password function called.")
+
+	var r mu.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+		return
+	}
+
+	if err := u.b.Users().ResetPassword(c, r.Token, r.NewPassword); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
@@ -0,0 +1,39 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package user
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	mu "github.com/changaolee/skeleton/internal/pkg/model/user"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+// ChangePassword 校验旧密码后修改用户密码.
+func (u *UserController) ChangePassword(c *gin.Context) {
+	log.C(c).Infow("Change password function called.")
+
+	if err := u.requireSelfOrAdmin(c, c.Param("name")); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	var r mu.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+		return
+	}
+
+	if err := u.b.Users().ChangePassword(c, c.Param("name"), r.OldPassword, r.NewPassword); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
@@ -19,7 +19,7 @@ import (
 	"github.com/changaolee/skeleton/pkg/log"
 )
 
-// Create 创建一个新用户.
+// Create 创建一个新用户，只有已登录的管理员可以创建管理员.
 func (u *UserController) Create(c *gin.Context) {
 	log.C(c).Infow("Create user function called")
 
@@ -34,12 +34,28 @@ func (u *UserController) Create(c *gin.Context) {
 		return
 	}
 
-	r.Status = 1
+	r.Status = user.StatusActive
 	r.LoginAt = time.Now()
+	r.LoginIP = ""
+	r.FailedLoginCount = 0
+	r.LockedUntil = nil
+	r.MFAEnabled = 0
+	r.EmailVerifiedAt = nil
+
+	if r.IsAdmin != 0 {
+		admin, err := u.isAuthenticatedAdmin(c)
+		if err != nil {
+			core.WriteResponse(c, err, nil)
+			return
+		}
+		if !admin {
+			r.IsAdmin = 0
+		}
+	}
 
 	if err := u.b.Users().Create(c, &r); err != nil {
 		core.WriteResponse(c, err, nil)
 		return
 	}
-	core.WriteResponse(c, nil, r)
+	core.WriteResponse(c, nil, withoutSecrets(&r))
 }
@@ -0,0 +1,115 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package user
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/apiserver/store/fake"
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
+	"github.com/changaolee/skeleton/internal/pkg/model/user"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+)
+
+func TestCreate_IsAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	s := fake.NewStore()
+	for name, isAdmin := range map[string]int{"root": 1, "plain": 0} {
+		err := s.Users().Create(ctx, &user.User{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status:     user.StatusActive,
+			IsAdmin:    isAdmin,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	g := gin.New()
+	g.POST("/users", func(c *gin.Context) {
+		// 模拟认证中间件设置的当前登录用户
+		if operator := c.GetHeader("X-Operator"); operator != "" {
+			c.Set(middleware.UsernameKey, operator)
+		}
+	}, NewUserController(s).Create)
+
+	tests := []struct {
+		operator string
+		want     int
+	}{
+		{"", 0},
+		{"plain", 0},
+		{"root", 1},
+	}
+	for i, tt := range tests {
+		name := fmt.Sprintf("user%d", i)
+		body := fmt.Sprintf(`{"metadata":{"name":%q},"nickname":%q,"password":"Secret#123",`+
+			`"email":"%s@example.com","isAdmin":1}`, name, name, name)
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Operator", tt.operator)
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Create() by %q = %d %s", tt.operator, w.Code, w.Body.String())
+		}
+		if strings.Contains(w.Body.String(), `"password"`) {
+			t.Errorf("Create() response contains password: %s", w.Body.String())
+		}
+
+		created, err := s.Users().Get(ctx, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created.IsAdmin != tt.want {
+			t.Errorf("Create() by %q isAdmin = %d, want %d", tt.operator, created.IsAdmin, tt.want)
+		}
+	}
+}
+
+func TestList_WithoutPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	s := fake.NewStore()
+	err := s.Users().Create(context.Background(), &user.User{
+		ObjectMeta: metav1.ObjectMeta{Name: "root"},
+		Status:     user.StatusActive,
+		Password:   "$2a$10$hash",
+		IsAdmin:    1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := gin.New()
+	g.GET("/users", func(c *gin.Context) {
+		c.Set(middleware.UsernameKey, "root")
+	}, NewUserController(s).List)
+
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("List() = %d %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"root"`) || strings.Contains(w.Body.String(), `"password"`) {
+		t.Errorf("List() response = %s, want users without password", w.Body.String())
+	}
+
+	stored, err := s.Users().Get(context.Background(), "root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Password == "" {
+		t.Errorf("List() cleared the password of the stored user")
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package user
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+// Delete 删除指定用户，仅管理员可用.
+func (u *UserController) Delete(c *gin.Context) {
+	log.C(c).Infow("Delete user function called.")
+
+	if err := u.requireAdmin(c); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	if err := u.b.Users().Delete(c, c.Param("name")); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
+
+// DeleteCollection 批量删除通过 name 查询参数指定的用户，仅管理员可用.
+func (u *UserController) DeleteCollection(c *gin.Context) {
+	log.C(c).Infow("Batch delete user function called.")
+
+	if err := u.requireAdmin(c); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	usernames := c.QueryArray("name")
+	if len(usernames) == 0 {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, "at least one name is required"), nil)
+		return
+	}
+
+	if err := u.b.Users().DeleteCollection(c, usernames); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
@@ -10,6 +10,12 @@ import (
 func (u *UserController) Get(c *gin.Context) {
 	log.C(c).Infow("Get user function called.")
 
+	if err := u.requireSelfOrAdmin(c, c.Param("name")); err != nil {
+		core.WriteResponse(c, err, nil)
+
+		return
+	}
+
 	user, err := u.b.Users().Get(c, c.Param("name"))
 	if err != nil {
 		core.WriteResponse(c, err, nil)
@@ -17,5 +23,5 @@ func (u *UserController) Get(c *gin.Context) {
 		return
 	}
 
-	core.WriteResponse(c, nil, user)
+	core.WriteResponse(c, nil, withoutSecrets(user))
 }
@@ -0,0 +1,44 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package user
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+)
+
+// List 分页查询用户列表，仅管理员可用.
+func (u *UserController) List(c *gin.Context) {
+	log.C(c).Infow("List user function called.")
+
+	if err := u.requireAdmin(c); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	var r metav1.ListOptions
+	if err := c.ShouldBindQuery(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+		return
+	}
+
+	users, err := u.b.Users().List(c, r)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	for i, item := range users.Items {
+		users.Items[i] = withoutSecrets(item)
+	}
+
+	core.WriteResponse(c, nil, users)
+}
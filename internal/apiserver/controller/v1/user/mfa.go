@@ -0,0 +1,89 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package user
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
+	mu "github.com/changaolee/skeleton/internal/pkg/model/user"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+// EnrollMFA 为当前登录用户生成 TOTP 密钥，用户确认验证码后开启两步验证.
+func (u *UserController) EnrollMFA(c *gin.Context) {
+	log.C(c).Infow("Enroll mfa function called.")
+
+	if err := requireSelf(c, c.Param("name")); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	enrollment, err := u.b.Users().EnrollMFA(c, c.Param("name"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, enrollment)
+}
+
+// ConfirmMFA 校验验证码后为当前登录用户开启两步验证，返回恢复码.
+func (u *UserController) ConfirmMFA(c *gin.Context) {
+	log.C(c).Infow("Confirm mfa function called.")
+
+	if err := requireSelf(c, c.Param("name")); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	var r mu.MFACodeRequest
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+		return
+	}
+
+	recoveryCodes, err := u.b.Users().ConfirmMFA(c, c.Param("name"), r.Code)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, recoveryCodes)
+}
+
+// DisableMFA 关闭用户的两步验证. 用户关闭自己的两步验证时需要提供验证码或恢复码，
+// 管理员可以直接重置其他用户的两步验证.
+func (u *UserController) DisableMFA(c *gin.Context) {
+	log.C(c).Infow("Disable mfa function called.")
+
+	name := c.Param("name")
+	if c.GetString(middleware.UsernameKey) == name {
+		var r mu.MFACodeRequest
+		if err := c.ShouldBindJSON(&r); err != nil {
+			core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+			return
+		}
+
+		if err := u.b.Users().VerifyMFA(c, name, r.Code); err != nil {
+			core.WriteResponse(c, err, nil)
+			return
+		}
+	} else if err := u.requireAdmin(c); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	if err := u.b.Users().DisableMFA(c, name); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
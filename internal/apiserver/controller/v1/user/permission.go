@@ -0,0 +1,64 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package user
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
+	"github.com/changaolee/skeleton/pkg/errors"
+)
+
+// isAdmin 判断当前登录用户是否为管理员.
+func (u *UserController) isAdmin(c *gin.Context) (bool, error) {
+	operator, err := u.b.Users().Get(c, c.GetString(middleware.UsernameKey))
+	if err != nil {
+		return false, err
+	}
+
+	return operator.IsAdmin == 1, nil
+}
+
+// isAuthenticatedAdmin 判断请求是否由已登录的管理员发起，匿名请求返回 false.
+func (u *UserController) isAuthenticatedAdmin(c *gin.Context) (bool, error) {
+	if c.GetString(middleware.UsernameKey) == "" {
+		return false, nil
+	}
+
+	return u.isAdmin(c)
+}
+
+// requireAdmin 要求当前登录用户为管理员.
+func (u *UserController) requireAdmin(c *gin.Context) error {
+	admin, err := u.isAdmin(c)
+	if err != nil {
+		return err
+	}
+	if !admin {
+		return errors.WithCode(code.ErrPermissionDenied, "administrator permission required")
+	}
+
+	return nil
+}
+
+// requireSelfOrAdmin 要求当前登录用户为管理员，或者操作的是自己的用户记录.
+func (u *UserController) requireSelfOrAdmin(c *gin.Context, name string) error {
+	if c.GetString(middleware.UsernameKey) == name {
+		return nil
+	}
+
+	return u.requireAdmin(c)
+}
+
+// requireSelf 要求操作的是当前登录用户自己的用户记录.
+func requireSelf(c *gin.Context, name string) error {
+	if c.GetString(middleware.UsernameKey) != name {
+		return errors.WithCode(code.ErrPermissionDenied, "only the user can perform this operation")
+	}
+
+	return nil
+}
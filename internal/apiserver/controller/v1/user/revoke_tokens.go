@@ -0,0 +1,30 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package user
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+// RevokeTokens 吊销用户已签发的所有 token，只允许管理员调用.
+func (u *UserController) RevokeTokens(c *gin.Context) {
+	log.C(c).Infow("Revoke user tokens function called.")
+
+	if err := u.requireAdmin(c); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	if err := u.b.Users().RevokeTokens(c, c.Param("name")); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, nil)
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package user
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	mu "github.com/changaolee/skeleton/internal/pkg/model/user"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+// Update 更新用户的昵称、邮箱和手机号.
+func (u *UserController) Update(c *gin.Context) {
+	log.C(c).Infow("Update user function called.")
+
+	if err := u.requireSelfOrAdmin(c, c.Param("name")); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	var r mu.User
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+		return
+	}
+
+	user, err := u.b.Users().Get(c, c.Param("name"))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	// 修改邮箱后需要重新验证
+	if user.Email != r.Email {
+		user.EmailVerifiedAt = nil
+	}
+
+	// 仅允许更新昵称、邮箱、手机号和扩展字段
+	user.Nickname = r.Nickname
+	user.Email = r.Email
+	user.Phone = r.Phone
+	user.Extend = r.Extend
+
+	if errs := user.ValidateUpdate(); len(errs) != 0 {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, errs.ToAggregate().Error()), nil)
+		return
+	}
+
+	if err := u.b.Users().Update(c, user); err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, withoutSecrets(user))
+}
@@ -8,6 +8,7 @@ package user
 import (
 	"github.com/changaolee/skeleton/internal/apiserver/biz"
 	"github.com/changaolee/skeleton/internal/apiserver/store"
+	mu "github.com/changaolee/skeleton/internal/pkg/model/user"
 )
 
 type UserController struct {
@@ -18,3 +19,14 @@ type UserController struct {
 func NewUserController(s store.IStore) *UserController {
 	return &UserController{b: biz.New(s)}
 }
+
+// withoutSecrets 返回不包含密码哈希等敏感信息的用户副本，用于返回给客户端.
+func withoutSecrets(u *mu.User) *mu.User {
+	out := *u
+	out.Password = ""
+	out.TOTPSecret = ""
+	out.RecoveryCodes = nil
+	out.PasswordHistory = nil
+
+	return &out
+}
@@ -0,0 +1,134 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package apiserver
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/apiserver/biz"
+	"github.com/changaolee/skeleton/internal/apiserver/idp"
+	"github.com/changaolee/skeleton/internal/apiserver/options"
+	"github.com/changaolee/skeleton/internal/apiserver/store"
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+	"github.com/changaolee/skeleton/pkg/util/iputil"
+)
+
+// federationStateCookie 保存登录过程中的 state、nonce 和 PKCE code verifier，用于在回调时校验.
+const federationStateCookie = "skt_idp_state"
+
+// federatedLogin 提供通过外部身份提供方登录的接口，登录成功后签发 skt 的 token.
+type federatedLogin struct {
+	providers map[string]idp.Provider
+	stateTTL  time.Duration
+	jwtAuth   *reloadableJWTAuth
+}
+
+func newFederatedLogin(opts *options.IdentityProviderOptions, jwtAuth *reloadableJWTAuth) *federatedLogin {
+	return &federatedLogin{
+		providers: idp.NewProviders(opts),
+		stateTTL:  opts.StateTTL,
+		jwtAuth:   jwtAuth,
+	}
+}
+
+func (f *federatedLogin) provider(c *gin.Context) (idp.Provider, bool) {
+	p, ok := f.providers[c.Param("name")]
+	if !ok {
+		core.WriteResponse(c, errors.WithCode(code.ErrIdentityProviderNotFound,
+			"identity provider %s not found", c.Param("name")), nil)
+	}
+
+	return p, ok
+}
+
+// LoginHandler 将用户重定向到身份提供方的登录页面.
+func (f *federatedLogin) LoginHandler(c *gin.Context) {
+	p, ok := f.provider(c)
+	if !ok {
+		return
+	}
+
+	var values [3]string // state、nonce、code verifier
+	for i := range values {
+		v, err := idp.RandomString()
+		if err != nil {
+			core.WriteResponse(c, errors.WithCode(code.ErrUnknown, err.Error()), nil)
+			return
+		}
+		values[i] = v
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	authURL, err := p.AuthCodeURL(c, state, nonce, idp.CodeChallenge(verifier))
+	if err != nil {
+		log.C(c).Errorf("Build auth code url of identity provider %s failed: %s", p.Name(), err.Error())
+		core.WriteResponse(c, errors.WithCode(code.ErrFederatedLoginFailed, err.Error()), nil)
+
+		return
+	}
+
+	// 身份提供方通过顶层跳转回调，需要使用 Lax 才能携带 cookie
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(federationStateCookie, strings.Join(values[:], "."), int(f.stateTTL.Seconds()),
+		c.Request.URL.Path, "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// CallbackHandler 处理身份提供方的回调，校验 state 后使用授权码换取用户身份并签发 token.
+func (f *federatedLogin) CallbackHandler(c *gin.Context) {
+	p, ok := f.provider(c)
+	if !ok {
+		return
+	}
+
+	// 无论成功与否，state 只能使用一次
+	loginPath := strings.TrimSuffix(c.Request.URL.Path, "/callback")
+	cookie, _ := c.Cookie(federationStateCookie)
+	c.SetCookie(federationStateCookie, "", -1, loginPath, "", c.Request.TLS != nil, true)
+
+	if e := c.Query("error"); e != "" {
+		core.WriteResponse(c, errors.WithCode(code.ErrFederatedLoginFailed, "%s: %s", e, c.Query("error_description")), nil)
+		return
+	}
+
+	values := strings.Split(cookie, ".")
+	if len(values) != 3 || subtle.ConstantTimeCompare([]byte(values[0]), []byte(c.Query("state"))) != 1 {
+		core.WriteResponse(c, errors.WithCode(code.ErrFederatedLoginFailed, "state mismatch"), nil)
+		return
+	}
+	nonce, verifier := values[1], values[2]
+
+	identity, err := p.Exchange(c, c.Query("code"), nonce, verifier)
+	if err != nil {
+		log.C(c).Warnf("Login with identity provider %s failed: %s", p.Name(), err.Error())
+		core.WriteResponse(c, errors.WithCode(code.ErrFederatedLoginFailed, err.Error()), nil)
+
+		return
+	}
+
+	users := biz.New(store.Store()).Users()
+	u, err := users.FederatedLogin(c, identity, iputil.RemoteIP(c.Request))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	// 外部身份提供方的认证不能代替 skt 的两步验证
+	if users.RequiresMFA(u) {
+		mfaChallenge(c, u)
+		return
+	}
+
+	f.jwtAuth.load().login(c, u)
+}
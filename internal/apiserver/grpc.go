@@ -0,0 +1,81 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package apiserver
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/changaolee/skeleton/internal/apiserver/controller/v1/cache"
+	"github.com/changaolee/skeleton/internal/apiserver/store"
+	genoptions "github.com/changaolee/skeleton/internal/pkg/options"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+	pb "github.com/changaolee/skeleton/pkg/proto/apiserver/v1"
+)
+
+// grpcAPIServer 是 skt-apiserver 的 gRPC 服务，为 skt-authz-server 提供 secrets 和 policies.
+type grpcAPIServer struct {
+	*grpc.Server
+	address string
+}
+
+// newGRPCAPIServer 基于给定的 gRPC 选项和 store 实例创建 gRPC 服务.
+func newGRPCAPIServer(opts *genoptions.GRPCOptions, storeIns store.IStore) (*grpcAPIServer, error) {
+	creds, err := credentials.NewServerTLSFromFile(opts.ServerCert.CertFile, opts.ServerCert.KeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate grpc credentials")
+	}
+
+	grpcServer := grpc.NewServer(grpc.MaxRecvMsgSize(opts.MaxMsgSize), grpc.Creds(creds))
+
+	pb.RegisterCacheServer(grpcServer, cache.NewCacheController(storeIns))
+	reflection.Register(grpcServer)
+
+	return &grpcAPIServer{
+		Server:  grpcServer,
+		address: opts.Address(),
+	}, nil
+}
+
+// Run 在后台启动 gRPC 服务.
+func (s *grpcAPIServer) Run() {
+	listen, err := net.Listen("tcp", s.address)
+	if err != nil {
+		log.Fatalf("Failed to listen grpc address %s: %s", s.address, err.Error())
+	}
+
+	go func() {
+		if err := s.Serve(listen); err != nil {
+			log.Fatalf("Failed to start grpc server: %s", err.Error())
+		}
+	}()
+
+	log.Infof("Start to listening the incoming requests on grpc address: %s", s.address)
+}
+
+// Close 优雅关闭 gRPC 服务，等待正在处理的请求完成，ctx 结束时强制关闭.
+func (s *grpcAPIServer) Close(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		log.Infof("GRPC server on %s stopped", s.address)
+		return nil
+	case <-ctx.Done():
+		s.Stop()
+		log.Warnf("GRPC server on %s stopped forcibly: %s", s.address, ctx.Err().Error())
+		return ctx.Err()
+	}
+}
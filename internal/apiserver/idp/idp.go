@@ -0,0 +1,65 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package idp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/changaolee/skeleton/internal/apiserver/options"
+)
+
+// Identity 是外部身份提供方认证后返回的用户身份.
+type Identity struct {
+	// Provider 为身份提供方的名称
+	Provider string
+	// Subject 为用户在身份提供方中的唯一标识
+	Subject  string
+	Username string
+	Nickname string
+	Email    string
+	Phone    string
+}
+
+// Provider 定义了使用 OAuth2 授权码流程登录的外部身份提供方.
+type Provider interface {
+	// Name 返回身份提供方的名称，用于区分登录地址.
+	Name() string
+	// AuthCodeURL 返回身份提供方登录页面的地址，用户登录后携带授权码和 state 重定向回 skt-apiserver.
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	// Exchange 使用授权码换取用户身份，nonce 和 codeVerifier 必须与 AuthCodeURL 使用的一致.
+	Exchange(ctx context.Context, code, nonce, codeVerifier string) (*Identity, error)
+}
+
+// NewProviders 根据配置创建所有身份提供方，返回以名称为 key 的 map.
+func NewProviders(opts *options.IdentityProviderOptions) map[string]Provider {
+	providers := make(map[string]Provider, len(opts.OIDC))
+	for i := range opts.OIDC {
+		p := NewOIDCProvider(&opts.OIDC[i])
+		providers[p.Name()] = p
+	}
+
+	return providers
+}
+
+// RandomString 返回一个 URL 安全的随机字符串，用于生成 state、nonce 和 PKCE code verifier.
+func RandomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate random string: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge 返回 PKCE code verifier 对应的 S256 code challenge.
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,270 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package idp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/changaolee/skeleton/internal/apiserver/options"
+	"github.com/changaolee/skeleton/internal/pkg/jwks"
+)
+
+const (
+	// jwksRefreshInterval 为重新获取身份提供方公钥的时间间隔.
+	jwksRefreshInterval = time.Hour
+	// maxResponseSize 为身份提供方响应的最大长度.
+	maxResponseSize = 1 << 20
+)
+
+var defaultScopes = []string{"openid", "profile", "email"}
+
+// discovery 是 OpenID Connect Discovery 文档中用到的字段.
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// tokenResponse 是 token 接口的响应.
+type tokenResponse struct {
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// OIDCProvider 是一个 OpenID Connect 身份提供方，通过 Discovery 获取接口地址，
+// 使用身份提供方发布的公钥验证 ID Token，并根据配置将 claim 映射为用户身份.
+type OIDCProvider struct {
+	name         string
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	scopes       []string
+	claims       options.OIDCClaimOptions
+	client       *http.Client
+
+	// Discovery 在第一次使用时获取，失败时下次使用重新获取
+	mu        sync.Mutex
+	discovery *discovery
+	keys      *jwks.RemoteKeySet
+}
+
+var _ Provider = (*OIDCProvider)(nil)
+
+// NewOIDCProvider 创建一个 OpenID Connect 身份提供方.
+func NewOIDCProvider(opts *options.OIDCProviderOptions) *OIDCProvider {
+	p := &OIDCProvider{
+		name:         opts.Name,
+		issuer:       strings.TrimSuffix(opts.Issuer, "/"),
+		clientID:     opts.ClientID,
+		clientSecret: opts.ClientSecret,
+		redirectURL:  opts.RedirectURL,
+		scopes:       opts.Scopes,
+		claims:       opts.Claims,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}
+
+	if len(p.scopes) == 0 {
+		p.scopes = defaultScopes
+	}
+	if p.claims.Username == "" {
+		p.claims.Username = "preferred_username"
+	}
+	if p.claims.Nickname == "" {
+		p.claims.Nickname = "name"
+	}
+	if p.claims.Email == "" {
+		p.claims.Email = "email"
+	}
+	if p.claims.Phone == "" {
+		p.claims.Phone = "phone_number"
+	}
+
+	return p
+}
+
+// Name 返回身份提供方的名称.
+func (p *OIDCProvider) Name() string {
+	return p.name
+}
+
+// AuthCodeURL 返回身份提供方登录页面的地址.
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	d, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(d.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid authorization_endpoint: %w", err)
+	}
+
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.clientID)
+	q.Set("redirect_uri", p.redirectURL)
+	q.Set("scope", strings.Join(p.scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// Exchange 使用授权码换取 ID Token，验证后返回其中的用户身份.
+func (p *OIDCProvider) Exchange(ctx context.Context, code, nonce, codeVerifier string) (*Identity, error) {
+	d, keys, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.redirectURL)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+
+	var token tokenResponse
+	if err := p.do(req, &token); err != nil && token.Error == "" {
+		return nil, fmt.Errorf("exchange code: %w", err)
+	}
+	if token.Error != "" {
+		return nil, fmt.Errorf("exchange code: %s: %s", token.Error, token.ErrorDescription)
+	}
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("exchange code: no id_token in response")
+	}
+
+	claims, err := p.verify(token.IDToken, keys, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("verify id_token: %w", err)
+	}
+
+	return p.identity(claims)
+}
+
+// verify 验证 ID Token 的签名、签发方、受众、有效期和 nonce，ID Token 必须包含 exp.
+func (p *OIDCProvider) verify(raw string, keys *jwks.RemoteKeySet, nonce string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(raw, claims, keys.Keyfunc); err != nil {
+		return nil, err
+	}
+
+	if iss, _ := claims["iss"].(string); iss != p.issuer {
+		return nil, fmt.Errorf("unexpected issuer %q", iss)
+	}
+	if !claims.VerifyAudience(p.clientID, true) {
+		return nil, fmt.Errorf("audience does not contain client id %s", p.clientID)
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, fmt.Errorf("token is expired")
+	}
+	if got, _ := claims["nonce"].(string); got != nonce {
+		return nil, fmt.Errorf("nonce mismatch")
+	}
+
+	return claims, nil
+}
+
+// identity 根据配置的 claim 映射生成用户身份.
+func (p *OIDCProvider) identity(claims jwt.MapClaims) (*Identity, error) {
+	str := func(name string) string {
+		v, _ := claims[name].(string)
+		return v
+	}
+
+	identity := &Identity{
+		Provider: p.name,
+		Subject:  str("sub"),
+		Username: str(p.claims.Username),
+		Nickname: str(p.claims.Nickname),
+		Email:    str(p.claims.Email),
+		Phone:    str(p.claims.Phone),
+	}
+	if identity.Subject == "" {
+		return nil, fmt.Errorf("no sub claim in id_token")
+	}
+	if identity.Username == "" {
+		return nil, fmt.Errorf("no %s claim in id_token", p.claims.Username)
+	}
+
+	return identity, nil
+}
+
+// discover 获取并缓存身份提供方的 Discovery 文档.
+func (p *OIDCProvider) discover(ctx context.Context) (*discovery, *jwks.RemoteKeySet, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, p.keys, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var d discovery
+	if err := p.do(req, &d); err != nil {
+		return nil, nil, fmt.Errorf("discover %s: %w", p.issuer, err)
+	}
+	if d.Issuer != p.issuer {
+		return nil, nil, fmt.Errorf("discover %s: issuer in discovery document is %q", p.issuer, d.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, nil, fmt.Errorf("discover %s: incomplete discovery document", p.issuer)
+	}
+
+	p.discovery = &d
+	p.keys = jwks.NewRemoteKeySet(d.JWKSURI, jwksRefreshInterval)
+
+	return p.discovery, p.keys, nil
+}
+
+// do 发送请求并将 JSON 响应解析到 v 中，响应状态码不是 200 时仍然尝试解析响应.
+func (p *OIDCProvider) do(req *http.Request, v interface{}) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	decodeErr := json.Unmarshal(data, v)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	return decodeErr
+}
@@ -0,0 +1,183 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package idp
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/changaolee/skeleton/internal/apiserver/options"
+	"github.com/changaolee/skeleton/internal/pkg/jwks"
+)
+
+// fakeIssuer 是一个只实现授权码流程的 OpenID Connect 身份提供方.
+type fakeIssuer struct {
+	*httptest.Server
+	t    *testing.T
+	keys *jwks.KeySet
+
+	// codes 保存授权码对应的 code challenge 和 ID Token 的 claims
+	codes map[string]fakeGrant
+}
+
+type fakeGrant struct {
+	challenge string
+	claims    jwt.MapClaims
+}
+
+func newFakeIssuer(t *testing.T) *fakeIssuer {
+	t.Helper()
+
+	priv, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(priv)
+	key, err := jwks.ParsePrivateKey("k1", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("ParsePrivateKey() error = %v", err)
+	}
+	keys, err := jwks.NewKeySet("k1", []*jwks.Key{key})
+	if err != nil {
+		t.Fatalf("NewKeySet() error = %v", err)
+	}
+
+	f := &fakeIssuer{t: t, keys: keys, codes: map[string]fakeGrant{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 f.URL,
+			"authorization_endpoint": f.URL + "/authorize",
+			"token_endpoint":         f.URL + "/token",
+			"jwks_uri":               f.URL + "/keys",
+		})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(f.keys.JWKS())
+	})
+	mux.HandleFunc("/token", f.token)
+	f.Server = httptest.NewServer(mux)
+	t.Cleanup(f.Close)
+
+	return f
+}
+
+// grant 模拟用户在身份提供方登录，返回授权码.
+func (f *fakeIssuer) grant(authURL string, claims jwt.MapClaims) string {
+	u, err := url.Parse(authURL)
+	if err != nil {
+		f.t.Fatalf("parse auth url: %v", err)
+	}
+	q := u.Query()
+	if q.Get("nonce") != "" {
+		claims["nonce"] = q.Get("nonce")
+	}
+
+	code := "code-" + q.Get("state")
+	f.codes[code] = fakeGrant{challenge: q.Get("code_challenge"), claims: claims}
+
+	return code
+}
+
+func (f *fakeIssuer) token(w http.ResponseWriter, r *http.Request) {
+	writeError := func(code string) {
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": code})
+	}
+
+	if id, secret, _ := r.BasicAuth(); id != "skt" || secret != "s3cret" {
+		writeError("invalid_client")
+		return
+	}
+	g, ok := f.codes[r.PostFormValue("code")]
+	if !ok || CodeChallenge(r.PostFormValue("code_verifier")) != g.challenge {
+		writeError("invalid_grant")
+		return
+	}
+	delete(f.codes, r.PostFormValue("code"))
+
+	idToken, err := f.keys.Sign(g.claims)
+	if err != nil {
+		f.t.Fatalf("Sign() error = %v", err)
+	}
+	_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "at", "token_type": "Bearer", "id_token": idToken})
+}
+
+func TestOIDCProvider(t *testing.T) {
+	ctx := context.Background()
+	issuer := newFakeIssuer(t)
+	p := NewOIDCProvider(&options.OIDCProviderOptions{
+		Name:         "corp",
+		Issuer:       issuer.URL,
+		ClientID:     "skt",
+		ClientSecret: "s3cret",
+		RedirectURL:  "https://skt.example.com/login/idp/corp/callback",
+		Claims:       options.OIDCClaimOptions{Username: "upn"},
+	})
+
+	claims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   issuer.URL,
+			"aud":   []string{"skt", "other"},
+			"sub":   "00u1",
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"upn":   "alice",
+			"name":  "Alice",
+			"email": "alice@example.com",
+		}
+	}
+	login := func(state string, c jwt.MapClaims, nonce, verifier string) (*Identity, error) {
+		authURL, err := p.AuthCodeURL(ctx, state, "n-"+state, CodeChallenge("v-"+state))
+		if err != nil {
+			t.Fatalf("AuthCodeURL() error = %v", err)
+		}
+		return p.Exchange(ctx, issuer.grant(authURL, c), nonce, verifier)
+	}
+
+	identity, err := login("s1", claims(), "n-s1", "v-s1")
+	if err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	want := Identity{Provider: "corp", Subject: "00u1", Username: "alice", Nickname: "Alice", Email: "alice@example.com"}
+	if *identity != want {
+		t.Errorf("Exchange() = %+v, want %+v", *identity, want)
+	}
+
+	tests := []struct {
+		name     string
+		claims   func(jwt.MapClaims)
+		nonce    string
+		verifier string
+	}{
+		{name: "wrong nonce", nonce: "n-other", verifier: "v-s2"},
+		{name: "wrong code verifier", nonce: "n-s2", verifier: "v-other"},
+		{name: "wrong audience", claims: func(c jwt.MapClaims) { c["aud"] = "other" }, nonce: "n-s2", verifier: "v-s2"},
+		{name: "wrong issuer", claims: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }, nonce: "n-s2", verifier: "v-s2"},
+		{name: "expired", claims: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }, nonce: "n-s2", verifier: "v-s2"},
+		{name: "missing exp", claims: func(c jwt.MapClaims) { delete(c, "exp") }, nonce: "n-s2", verifier: "v-s2"},
+		{name: "missing username", claims: func(c jwt.MapClaims) { delete(c, "upn") }, nonce: "n-s2", verifier: "v-s2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := claims()
+			if tt.claims != nil {
+				tt.claims(c)
+			}
+			if _, err := login("s2", c, tt.nonce, tt.verifier); err == nil {
+				t.Errorf("Exchange() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package mail
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+// logMailer 将邮件写入日志，邮件中的令牌会出现在日志中，只适用于开发和测试.
+type logMailer struct {
+	from string
+}
+
+// NewLogMailer 创建一个将邮件写入日志的 Mailer.
+func NewLogMailer(from string) Mailer {
+	return &logMailer{from: from}
+}
+
+func (m *logMailer) Send(ctx context.Context, msg *Message) error {
+	log.C(ctx).Infow("Mail sent to log", "from", m.from, "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+
+	return nil
+}
+
+// fileMailer 将每封邮件写入目录中的一个 .eml 文件，只适用于开发和测试.
+type fileMailer struct {
+	from string
+	dir  string
+}
+
+// NewFileMailer 创建一个将邮件写入 dir 目录的 Mailer.
+func NewFileMailer(from, dir string) (Mailer, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &fileMailer{from: from, dir: dir}, nil
+}
+
+func (m *fileMailer) Send(ctx context.Context, msg *Message) error {
+	now := time.Now()
+	data, err := format(m.from, msg, now)
+	if err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", now.Format("20060102T150405.000000000"), hex.EncodeToString(suffix))
+
+	return os.WriteFile(filepath.Join(m.dir, name), data, 0o600)
+}
@@ -0,0 +1,225 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package mail
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	netmail "net/mail"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/changaolee/skeleton/internal/apiserver/options"
+)
+
+var testData = map[string]string{
+	"Username":  "colin",
+	"Nickname":  "Colin",
+	"Link":      "https://skt.example.com/reset?token=abc",
+	"ExpiresAt": "2023-01-01T00:30:00Z",
+}
+
+func TestTemplates(t *testing.T) {
+	dir := t.TempDir()
+	override := `{{define "subject"}}Reset {{.Username}}{{end}}{{define "body"}}{{.Link}}{{end}}`
+	if err := os.WriteFile(filepath.Join(dir, "password-reset.tmpl"), []byte(override), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tmpls, err := NewTemplates(dir)
+	if err != nil {
+		t.Fatalf("NewTemplates() error = %v", err)
+	}
+
+	msg, err := tmpls.Render(TemplateEmailVerification, "colin@example.com", testData)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if msg.To[0] != "colin@example.com" || msg.Subject == "" || !strings.Contains(msg.Body, testData["Link"]) {
+		t.Errorf("Render() of the built-in template = %+v", msg)
+	}
+
+	msg, err = tmpls.Render(TemplatePasswordReset, "colin@example.com", testData)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if msg.Subject != "Reset colin" || msg.Body != testData["Link"]+"\n" {
+		t.Errorf("Render() of the overridden template = %+v", msg)
+	}
+
+	if _, err := tmpls.Render(TemplatePasswordReset, "colin@example.com", map[string]string{}); err == nil {
+		t.Error("Render() with missing data succeeded")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "email-verification.tmpl"), []byte(`{{define "body"}}{{end}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewTemplates(dir); err == nil {
+		t.Error("NewTemplates() with a template missing subject succeeded")
+	}
+}
+
+func TestFileMailer(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mails")
+	m, err := NewFileMailer("skt <noreply@skt.example.com>", dir)
+	if err != nil {
+		t.Fatalf("NewFileMailer() error = %v", err)
+	}
+
+	msg := &Message{To: []string{"colin@example.com"}, Subject: "验证邮箱", Body: "你好\n" + testData["Link"] + "\n"}
+	if err := m.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("Send() wrote %d files, want 1", len(files))
+	}
+	f, err := os.Open(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	checkMessage(t, f, msg)
+}
+
+func TestSMTPMailer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	received := make(chan fakeSMTPResult, 1)
+	go serveFakeSMTP(ln, received)
+
+	addr := ln.Addr().(*net.TCPAddr)
+	opts := options.NewMailOptions()
+	opts.From = "skt <noreply@skt.example.com>"
+	opts.Host = "127.0.0.1"
+	opts.Port = addr.Port
+	opts.Username = "skt"
+	opts.Password = "secret"
+
+	msg := &Message{To: []string{"colin@example.com"}, Subject: "重置密码", Body: testData["Link"] + "\n"}
+	if err := NewSMTPMailer(opts).Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	select {
+	case r := <-received:
+		if r.auth != "\x00skt\x00secret" {
+			t.Errorf("auth = %q", r.auth)
+		}
+		if r.from != "noreply@skt.example.com" || len(r.rcpt) != 1 || r.rcpt[0] != "colin@example.com" {
+			t.Errorf("envelope = %s -> %v", r.from, r.rcpt)
+		}
+		checkMessage(t, strings.NewReader(r.data), msg)
+	case <-time.After(5 * time.Second):
+		t.Fatal("fake smtp server received nothing")
+	}
+}
+
+func checkMessage(t *testing.T, r io.Reader, want *Message) {
+	t.Helper()
+
+	parsed, err := netmail.ReadMessage(r)
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil || subject != want.Subject {
+		t.Errorf("Subject = %q (%v), want %q", subject, err, want.Subject)
+	}
+	if parsed.Header.Get("To") != want.To[0] {
+		t.Errorf("To = %q, want %q", parsed.Header.Get("To"), want.To[0])
+	}
+	body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.ReplaceAll(string(body), "\r\n", "\n"); got != want.Body {
+		t.Errorf("Body = %q, want %q", got, want.Body)
+	}
+}
+
+type fakeSMTPResult struct {
+	auth string
+	from string
+	rcpt []string
+	data string
+}
+
+// serveFakeSMTP 接受一个连接，按 SMTP 协议接收一封邮件.
+func serveFakeSMTP(ln net.Listener, received chan<- fakeSMTPResult) {
+	conn, err := ln.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(s string) { _, _ = conn.Write([]byte(s + "\r\n")) }
+
+	var result fakeSMTPResult
+	reply("220 fake ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+
+		switch cmd {
+		case "EHLO":
+			reply("250-fake")
+			reply("250 AUTH PLAIN")
+		case "AUTH":
+			fields := strings.Fields(line)
+			decoded, _ := base64.StdEncoding.DecodeString(fields[len(fields)-1])
+			result.auth = string(decoded)
+			reply("235 ok")
+		case "MAIL":
+			result.from = strings.Trim(strings.TrimPrefix(line[len("MAIL FROM:"):], " "), "<>")
+			reply("250 ok")
+		case "RCPT":
+			result.rcpt = append(result.rcpt, strings.Trim(line[len("RCPT TO:"):], "<>"))
+			reply("250 ok")
+		case "DATA":
+			reply("354 go ahead")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			result.data = data.String()
+			reply("250 queued")
+		case "QUIT":
+			reply("221 bye")
+			received <- result
+			return
+		default:
+			reply("502 " + strconv.Quote(cmd) + " not implemented")
+		}
+	}
+}
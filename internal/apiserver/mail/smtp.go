@@ -0,0 +1,128 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/changaolee/skeleton/internal/apiserver/options"
+)
+
+// smtpMailer 通过 SMTP 服务器发送邮件.
+type smtpMailer struct {
+	from        string
+	addr        string
+	host        string
+	username    string
+	password    string
+	implicitTLS bool
+	tlsConfig   *tls.Config
+	timeout     time.Duration
+}
+
+// NewSMTPMailer 基于邮件选项创建一个通过 SMTP 服务器发送邮件的 Mailer.
+func NewSMTPMailer(opts *options.MailOptions) Mailer {
+	return &smtpMailer{
+		from:        opts.From,
+		addr:        net.JoinHostPort(opts.Host, strconv.Itoa(opts.Port)),
+		host:        opts.Host,
+		username:    opts.Username,
+		password:    opts.Password,
+		implicitTLS: opts.ImplicitTLS,
+		tlsConfig: &tls.Config{
+			ServerName:         opts.Host,
+			InsecureSkipVerify: opts.InsecureSkipVerify, //nolint:gosec // 由配置显式开启
+			MinVersion:         tls.VersionTLS12,
+		},
+		timeout: opts.Timeout,
+	}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, msg *Message) error {
+	sender, err := netmail.ParseAddress(m.from)
+	if err != nil {
+		return err
+	}
+	data, err := format(m.from, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	c, err := m.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	if err := c.Mail(sender.Address); err != nil {
+		return fmt.Errorf("mail from: %w", err)
+	}
+	for _, to := range msg.To {
+		if err := c.Rcpt(to); err != nil {
+			return fmt.Errorf("rcpt to %s: %w", to, err)
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("data: %w", err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("write data: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("write data: %w", err)
+	}
+
+	return c.Quit()
+}
+
+// dial 连接 SMTP 服务器，服务器支持时升级为 TLS 连接，配置了用户名时进行认证.
+func (m *smtpMailer) dial(ctx context.Context) (*smtp.Client, error) {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	var conn net.Conn
+	var err error
+	if m.implicitTLS {
+		conn, err = (&tls.Dialer{Config: m.tlsConfig}).DialContext(ctx, "tcp", m.addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", m.addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dial smtp server %s: %w", m.addr, err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(m.timeout))
+
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if ok, _ := c.Extension("STARTTLS"); ok && !m.implicitTLS {
+		if err := c.StartTLS(m.tlsConfig); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("start tls: %w", err)
+		}
+	}
+	if m.username != "" {
+		// PlainAuth 拒绝在未加密的连接上发送密码，本机地址除外
+		if err := c.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			c.Close()
+			return nil, fmt.Errorf("auth: %w", err)
+		}
+	}
+
+	return c, nil
+}
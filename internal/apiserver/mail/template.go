@@ -0,0 +1,87 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+const (
+	// TemplateEmailVerification 为验证邮箱邮件的模板名称.
+	TemplateEmailVerification = "email-verification"
+	// TemplatePasswordReset 为重置密码邮件的模板名称.
+	TemplatePasswordReset = "password-reset"
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// Templates 是邮件模板，每个模板定义 subject 和 body 两个子模板.
+type Templates struct {
+	templates map[string]*template.Template
+}
+
+// NewTemplates 加载内置的邮件模板，dir 不为空时使用 dir 中的同名 .tmpl 文件替换内置模板.
+func NewTemplates(dir string) (*Templates, error) {
+	t := &Templates{templates: map[string]*template.Template{}}
+
+	for _, name := range []string{TemplateEmailVerification, TemplatePasswordReset} {
+		file := name + ".tmpl"
+		text, err := builtinTemplates.ReadFile("templates/" + file)
+		if err != nil {
+			return nil, err
+		}
+		if dir != "" {
+			override, err := os.ReadFile(filepath.Join(dir, file))
+			if err == nil {
+				text = override
+			} else if !os.IsNotExist(err) {
+				return nil, err
+			}
+		}
+
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(string(text))
+		if err != nil {
+			return nil, fmt.Errorf("parse mail template %s: %w", file, err)
+		}
+		for _, part := range []string{"subject", "body"} {
+			if tmpl.Lookup(part) == nil {
+				return nil, fmt.Errorf("mail template %s does not define %q", file, part)
+			}
+		}
+		t.templates[name] = tmpl
+	}
+
+	return t, nil
+}
+
+// Render 使用 data 渲染名为 name 的模板，生成发送给 to 的邮件.
+func (t *Templates) Render(name string, to string, data interface{}) (*Message, error) {
+	tmpl, ok := t.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("mail template %s not found", name)
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, err
+	}
+	if err := tmpl.ExecuteTemplate(&body, "body", data); err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		To:      []string{to},
+		Subject: strings.TrimSpace(subject.String()),
+		Body:    strings.TrimSpace(body.String()) + "\n",
+	}, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package apiserver
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/apiserver/biz"
+	"github.com/changaolee/skeleton/internal/apiserver/store"
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/internal/pkg/model/user"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/util/iputil"
+)
+
+type mfaEnrollInfo struct {
+	Challenge string `json:"challenge" binding:"required"`
+}
+
+type mfaLoginInfo struct {
+	Challenge string `json:"challenge" binding:"required"`
+	Code      string `json:"code"      binding:"required"`
+}
+
+// mfaChallenge 为通过密码校验的用户创建登录挑战. 未开启两步验证的用户需要先使用挑战开启.
+func mfaChallenge(c *gin.Context, u *user.User) {
+	challenge, expire, err := biz.New(store.Store()).Users().CreateMFAChallenge(c, u.Name)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"mfaRequired":        true,
+		"enrollmentRequired": u.MFAEnabled != 1,
+		"challenge":          challenge,
+		"expire":             expire.Format(time.RFC3339),
+	})
+}
+
+// MFAEnrollHandler 为被要求在登录时开启两步验证的用户生成 TOTP 密钥.
+func (j *reloadableJWTAuth) MFAEnrollHandler(c *gin.Context) {
+	var r mfaEnrollInfo
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+		return
+	}
+
+	enrollment, err := biz.New(store.Store()).Users().EnrollMFAWithChallenge(c, r.Challenge)
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	core.WriteResponse(c, nil, enrollment)
+}
+
+// MFALoginHandler 校验登录挑战和验证码，通过后签发 token. 登录时开启两步验证的用户同时获得恢复码.
+func (j *reloadableJWTAuth) MFALoginHandler(c *gin.Context) {
+	var r mfaLoginInfo
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+		return
+	}
+
+	u, recoveryCodes, err := biz.New(store.Store()).Users().LoginWithMFA(c, r.Challenge, r.Code,
+		iputil.RemoteIP(c.Request))
+	if err != nil {
+		core.WriteResponse(c, err, nil)
+		return
+	}
+
+	state := j.load()
+	if recoveryCodes == nil {
+		state.login(c, u)
+		return
+	}
+
+	state.issueToken(c, state.claims(u), func(c *gin.Context, code int, token string, expire time.Time) {
+		c.JSON(http.StatusOK, gin.H{
+			"token":         token,
+			"expire":        expire.Format(time.RFC3339),
+			"recoveryCodes": recoveryCodes.RecoveryCodes,
+		})
+	})
+}
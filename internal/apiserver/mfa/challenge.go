@@ -0,0 +1,116 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package mfa
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrChallengeNotFound 表示登录挑战不存在、已过期、已被使用或验证失败次数过多.
+var ErrChallengeNotFound = errors.New("mfa challenge not found")
+
+// Challenges 保存通过密码校验、等待两步验证的登录挑战.
+type Challenges interface {
+	// Create 为通过密码校验的用户创建一个 ttl 后过期的登录挑战，返回挑战 ID.
+	Create(ctx context.Context, username string, ttl time.Duration) (string, error)
+	// Attempt 记录一次验证尝试并返回挑战对应的用户名，尝试次数超过 maxAttempts 后挑战失效.
+	Attempt(ctx context.Context, id string, maxAttempts int) (string, error)
+	// Consume 使挑战失效，返回挑战在此之前是否有效，保证每个挑战只能成功使用一次.
+	Consume(ctx context.Context, id string) (bool, error)
+}
+
+var ins Challenges = newMemoryChallenges()
+
+// Get 获取 Challenges 实例，未设置时返回一个保存在内存中的实例.
+func Get() Challenges {
+	return ins
+}
+
+// Set 设置 Challenges 实例.
+func Set(c Challenges) {
+	ins = c
+}
+
+// newChallengeID 生成一个随机的挑战 ID.
+func newChallengeID() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+type memoryChallenge struct {
+	username  string
+	attempts  int
+	expiresAt time.Time
+}
+
+// memoryChallenges 将登录挑战保存在内存中，只适用于单副本部署.
+type memoryChallenges struct {
+	mu         sync.Mutex
+	challenges map[string]*memoryChallenge
+	now        func() time.Time
+}
+
+func newMemoryChallenges() *memoryChallenges {
+	return &memoryChallenges{challenges: map[string]*memoryChallenge{}, now: time.Now}
+}
+
+func (m *memoryChallenges) Create(ctx context.Context, username string, ttl time.Duration) (string, error) {
+	id, err := newChallengeID()
+	if err != nil {
+		return "", err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	for k, c := range m.challenges {
+		if !now.Before(c.expiresAt) {
+			delete(m.challenges, k)
+		}
+	}
+	m.challenges[id] = &memoryChallenge{username: username, expiresAt: now.Add(ttl)}
+
+	return id, nil
+}
+
+func (m *memoryChallenges) Attempt(ctx context.Context, id string, maxAttempts int) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, ok := m.challenges[id]
+	if !ok || !m.now().Before(c.expiresAt) {
+		delete(m.challenges, id)
+		return "", ErrChallengeNotFound
+	}
+
+	c.attempts++
+	if c.attempts > maxAttempts {
+		delete(m.challenges, id)
+		return "", ErrChallengeNotFound
+	}
+
+	return c.username, nil
+}
+
+func (m *memoryChallenges) Consume(ctx context.Context, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	c, ok := m.challenges[id]
+	delete(m.challenges, id)
+
+	return ok && m.now().Before(c.expiresAt), nil
+}
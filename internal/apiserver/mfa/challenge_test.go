@@ -0,0 +1,53 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package mfa
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryChallenges(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	m := newMemoryChallenges()
+	m.now = func() time.Time { return now }
+
+	id, err := m.Create(ctx, "alice", time.Minute)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if username, err := m.Attempt(ctx, id, 3); err != nil || username != "alice" {
+			t.Fatalf("Attempt() #%d = (%q, %v), want alice", i+1, username, err)
+		}
+	}
+	if _, err := m.Attempt(ctx, id, 3); !errors.Is(err, ErrChallengeNotFound) {
+		t.Errorf("Attempt() after max attempts error = %v, want %v", err, ErrChallengeNotFound)
+	}
+
+	// 挑战只能成功使用一次
+	id, _ = m.Create(ctx, "alice", time.Minute)
+	if ok, _ := m.Consume(ctx, id); !ok {
+		t.Errorf("Consume() = false, want true")
+	}
+	if ok, _ := m.Consume(ctx, id); ok {
+		t.Errorf("Consume() again = true, want false")
+	}
+	if _, err := m.Attempt(ctx, id, 3); !errors.Is(err, ErrChallengeNotFound) {
+		t.Errorf("Attempt() after Consume() error = %v, want %v", err, ErrChallengeNotFound)
+	}
+
+	// 过期的挑战失效
+	id, _ = m.Create(ctx, "alice", time.Minute)
+	now = now.Add(time.Minute)
+	if _, err := m.Attempt(ctx, id, 3); !errors.Is(err, ErrChallengeNotFound) {
+		t.Errorf("Attempt() after expiry error = %v, want %v", err, ErrChallengeNotFound)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package mfa
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	genoptions "github.com/changaolee/skeleton/internal/pkg/options"
+	"github.com/changaolee/skeleton/pkg/db"
+)
+
+// challengeKeyPrefix 为登录挑战在 Redis 中的 key 前缀，值为包含 username 和 attempts 字段的 hash.
+const challengeKeyPrefix = "skt:mfa:challenge:"
+
+// attemptScript 在挑战存在时将尝试次数加一并返回用户名，超过最大尝试次数时删除挑战.
+var attemptScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return false
+end
+if redis.call('HINCRBY', KEYS[1], 'attempts', 1) > tonumber(ARGV[1]) then
+	redis.call('DEL', KEYS[1])
+	return false
+end
+return redis.call('HGET', KEYS[1], 'username')
+`)
+
+// RedisChallenges 将登录挑战保存在 Redis 中，所有 skt-apiserver 副本共享.
+type RedisChallenges struct {
+	client redis.UniversalClient
+}
+
+var _ Challenges = (*RedisChallenges)(nil)
+
+// NewRedisChallenges 创建一个基于 Redis 的登录挑战存储.
+func NewRedisChallenges(opts *genoptions.RedisOptions) (*RedisChallenges, error) {
+	client, err := db.NewRedis(&db.RedisOptions{
+		Host:     opts.Host,
+		Port:     opts.Port,
+		Username: opts.Username,
+		Password: opts.Password,
+		Database: opts.Database,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RedisChallenges{client: client}, nil
+}
+
+// Create 创建一个登录挑战.
+func (r *RedisChallenges) Create(ctx context.Context, username string, ttl time.Duration) (string, error) {
+	id, err := newChallengeID()
+	if err != nil {
+		return "", err
+	}
+
+	key := challengeKeyPrefix + id
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "username", username, "attempts", 0)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// Attempt 记录一次验证尝试并返回挑战对应的用户名.
+func (r *RedisChallenges) Attempt(ctx context.Context, id string, maxAttempts int) (string, error) {
+	username, err := attemptScript.Run(ctx, r.client, []string{challengeKeyPrefix + id}, maxAttempts).Text()
+	if err == redis.Nil {
+		return "", ErrChallengeNotFound
+	}
+
+	return username, err
+}
+
+// Consume 使挑战失效.
+func (r *RedisChallenges) Consume(ctx context.Context, id string) (bool, error) {
+	n, err := r.client.Del(ctx, challengeKeyPrefix+id).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return n == 1, nil
+}
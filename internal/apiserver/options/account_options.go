@@ -0,0 +1,88 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// minTokenKeyLength 为签发验证令牌的密钥的最小长度.
+const minTokenKeyLength = 32
+
+// AccountOptions 定义了邮箱验证和找回密码相关的选项.
+type AccountOptions struct {
+	TokenKey             string        `json:"-"                      mapstructure:"token-key"`
+	EmailVerificationTTL time.Duration `json:"email-verification-ttl" mapstructure:"email-verification-ttl"`
+	PasswordResetTTL     time.Duration `json:"password-reset-ttl"     mapstructure:"password-reset-ttl"`
+	ResendInterval       time.Duration `json:"resend-interval"        mapstructure:"resend-interval"`
+	RequireVerifiedEmail bool          `json:"require-verified-email" mapstructure:"require-verified-email"`
+	VerifyEmailURL       string        `json:"verify-email-url"       mapstructure:"verify-email-url"`
+	ResetPasswordURL     string        `json:"reset-password-url"     mapstructure:"reset-password-url"`
+}
+
+// NewAccountOptions 创建一个默认值的账户选项实例.
+func NewAccountOptions() *AccountOptions {
+	return &AccountOptions{
+		TokenKey:             "",
+		EmailVerificationTTL: 24 * time.Hour,
+		PasswordResetTTL:     30 * time.Minute,
+		ResendInterval:       time.Minute,
+		RequireVerifiedEmail: false,
+		VerifyEmailURL:       "",
+		ResetPasswordURL:     "",
+	}
+}
+
+// Validate 验证账户选项.
+func (o *AccountOptions) Validate() []error {
+	var errs []error
+
+	if o.TokenKey != "" && len(o.TokenKey) < minTokenKeyLength {
+		errs = append(errs, fmt.Errorf("--account.token-key must be at least %d characters", minTokenKeyLength))
+	}
+	if o.EmailVerificationTTL <= 0 {
+		errs = append(errs, fmt.Errorf("--account.email-verification-ttl %v must be greater than 0", o.EmailVerificationTTL))
+	}
+	if o.PasswordResetTTL <= 0 {
+		errs = append(errs, fmt.Errorf("--account.password-reset-ttl %v must be greater than 0", o.PasswordResetTTL))
+	}
+	if o.ResendInterval < 0 {
+		errs = append(errs, fmt.Errorf("--account.resend-interval %v must not be negative", o.ResendInterval))
+	}
+	if o.VerifyEmailURL != "" && !isAbsoluteURL(o.VerifyEmailURL) {
+		errs = append(errs, fmt.Errorf("--account.verify-email-url %q must be an absolute URL", o.VerifyEmailURL))
+	}
+	if o.ResetPasswordURL != "" && !isAbsoluteURL(o.ResetPasswordURL) {
+		errs = append(errs, fmt.Errorf("--account.reset-password-url %q must be an absolute URL", o.ResetPasswordURL))
+	}
+
+	return errs
+}
+
+// AddFlags 向指定 FlagSet 中添加账户选项相关标志.
+func (o *AccountOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.TokenKey, "account.token-key", o.TokenKey, ""+
+		"Key used to sign email verification and password reset tokens, at least 32 characters. "+
+		"A random key is used if empty, which invalidates issued tokens on restart and "+
+		"does not work with multiple replicas.")
+	fs.DurationVar(&o.EmailVerificationTTL, "account.email-verification-ttl", o.EmailVerificationTTL,
+		"How long an email verification token stays valid.")
+	fs.DurationVar(&o.PasswordResetTTL, "account.password-reset-ttl", o.PasswordResetTTL,
+		"How long a password reset token stays valid.")
+	fs.DurationVar(&o.ResendInterval, "account.resend-interval", o.ResendInterval,
+		"The minimum interval between two mails of the same kind sent to a user.")
+	fs.BoolVar(&o.RequireVerifiedEmail, "account.require-verified-email", o.RequireVerifiedEmail, ""+
+		"Create users with an unverified status and block them from logging in until they verify their email.")
+	fs.StringVar(&o.VerifyEmailURL, "account.verify-email-url", o.VerifyEmailURL, ""+
+		"URL of the page confirming email verification, the token is appended as the token query parameter. "+
+		"Mails contain the bare token if empty.")
+	fs.StringVar(&o.ResetPasswordURL, "account.reset-password-url", o.ResetPasswordURL, ""+
+		"URL of the page setting a new password, the token is appended as the token query parameter. "+
+		"Mails contain the bare token if empty.")
+}
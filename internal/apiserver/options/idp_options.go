@@ -0,0 +1,105 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+var providerNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// IdentityProviderOptions 定义了通过外部身份提供方登录相关的选项.
+type IdentityProviderOptions struct {
+	StateTTL time.Duration         `json:"state-ttl" mapstructure:"state-ttl"`
+	OIDC     []OIDCProviderOptions `json:"oidc"      mapstructure:"oidc"`
+}
+
+// OIDCProviderOptions 定义了一个 OpenID Connect 身份提供方.
+type OIDCProviderOptions struct {
+	Name         string           `json:"name"         mapstructure:"name"`
+	Issuer       string           `json:"issuer"       mapstructure:"issuer"`
+	ClientID     string           `json:"client-id"    mapstructure:"client-id"`
+	ClientSecret string           `json:"-"            mapstructure:"client-secret"`
+	RedirectURL  string           `json:"redirect-url" mapstructure:"redirect-url"`
+	Scopes       []string         `json:"scopes"       mapstructure:"scopes"`
+	Claims       OIDCClaimOptions `json:"claims"       mapstructure:"claims"`
+}
+
+// OIDCClaimOptions 定义了 ID Token 中的 claim 与用户字段的对应关系，为空时使用默认的 claim.
+type OIDCClaimOptions struct {
+	Username string `json:"username" mapstructure:"username"`
+	Nickname string `json:"nickname" mapstructure:"nickname"`
+	Email    string `json:"email"    mapstructure:"email"`
+	Phone    string `json:"phone"    mapstructure:"phone"`
+}
+
+// NewIdentityProviderOptions 创建一个默认值的身份提供方选项实例.
+func NewIdentityProviderOptions() *IdentityProviderOptions {
+	return &IdentityProviderOptions{
+		StateTTL: 10 * time.Minute,
+		OIDC:     nil,
+	}
+}
+
+// Validate 验证身份提供方选项.
+func (o *IdentityProviderOptions) Validate() []error {
+	var errs []error
+
+	if o.StateTTL <= 0 {
+		errs = append(errs, fmt.Errorf("--idp.state-ttl %v must be greater than 0", o.StateTTL))
+	}
+
+	names := make(map[string]bool, len(o.OIDC))
+	for i, p := range o.OIDC {
+		if !providerNameRegexp.MatchString(p.Name) {
+			errs = append(errs, fmt.Errorf("idp.oidc[%d].name %q must consist of lower case letters, digits and '-'", i, p.Name))
+		} else if names[p.Name] {
+			errs = append(errs, fmt.Errorf("idp.oidc[%d].name %q is duplicated", i, p.Name))
+		}
+		names[p.Name] = true
+
+		if !isAbsoluteURL(p.Issuer) {
+			errs = append(errs, fmt.Errorf("idp.oidc[%d].issuer %q must be an absolute url", i, p.Issuer))
+		}
+		if !isAbsoluteURL(p.RedirectURL) {
+			errs = append(errs, fmt.Errorf("idp.oidc[%d].redirect-url %q must be an absolute url", i, p.RedirectURL))
+		}
+		if p.ClientID == "" {
+			errs = append(errs, fmt.Errorf("idp.oidc[%d].client-id must be specified", i))
+		}
+		if len(p.Scopes) > 0 && !contains(p.Scopes, "openid") {
+			errs = append(errs, fmt.Errorf("idp.oidc[%d].scopes must contain openid", i))
+		}
+	}
+
+	return errs
+}
+
+// AddFlags 向指定 FlagSet 中添加身份提供方选项相关标志.
+func (o *IdentityProviderOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&o.StateTTL, "idp.state-ttl", o.StateTTL, ""+
+		"How long a user has to finish logging in at the identity provider. "+
+		"The identity providers themselves can only be configured in the config file.")
+}
+
+func isAbsoluteURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+func contains(items []string, item string) bool {
+	for _, v := range items {
+		if v == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// LDAPOptions 定义了使用 LDAP 校验用户密码相关的选项.
+type LDAPOptions struct {
+	Enabled            bool          `json:"enabled"              mapstructure:"enabled"`
+	URL                string        `json:"url"                  mapstructure:"url"`
+	StartTLS           bool          `json:"start-tls"            mapstructure:"start-tls"`
+	CAFile             string        `json:"ca-file"              mapstructure:"ca-file"`
+	InsecureSkipVerify bool          `json:"insecure-skip-verify" mapstructure:"insecure-skip-verify"`
+	Timeout            time.Duration `json:"timeout"              mapstructure:"timeout"`
+	BindDN             string        `json:"bind-dn"              mapstructure:"bind-dn"`
+	BindPassword       string        `json:"-"                    mapstructure:"bind-password"`
+	BaseDN             string        `json:"base-dn"              mapstructure:"base-dn"`
+	UserFilter         string        `json:"user-filter"          mapstructure:"user-filter"`
+	NicknameAttribute  string        `json:"nickname-attribute"   mapstructure:"nickname-attribute"`
+	EmailAttribute     string        `json:"email-attribute"      mapstructure:"email-attribute"`
+	PhoneAttribute     string        `json:"phone-attribute"      mapstructure:"phone-attribute"`
+	GroupAttribute     string        `json:"group-attribute"      mapstructure:"group-attribute"`
+	AdminGroups        []string      `json:"admin-groups"         mapstructure:"admin-groups"`
+	FallbackToLocal    bool          `json:"fallback-to-local"    mapstructure:"fallback-to-local"`
+}
+
+// NewLDAPOptions 创建一个默认值的 LDAP 选项实例.
+func NewLDAPOptions() *LDAPOptions {
+	return &LDAPOptions{
+		Enabled:            false,
+		URL:                "",
+		StartTLS:           false,
+		CAFile:             "",
+		InsecureSkipVerify: false,
+		Timeout:            5 * time.Second,
+		BindDN:             "",
+		BindPassword:       "",
+		BaseDN:             "",
+		UserFilter:         "(uid=%s)",
+		NicknameAttribute:  "cn",
+		EmailAttribute:     "mail",
+		PhoneAttribute:     "telephoneNumber",
+		GroupAttribute:     "memberOf",
+		AdminGroups:        []string{},
+		FallbackToLocal:    false,
+	}
+}
+
+// Validate 验证 LDAP 选项.
+func (o *LDAPOptions) Validate() []error {
+	if !o.Enabled {
+		return nil
+	}
+
+	var errs []error
+
+	u, err := url.Parse(o.URL)
+	if err != nil || (u.Scheme != "ldap" && u.Scheme != "ldaps") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("--ldap.url %q must be an ldap:// or ldaps:// url", o.URL))
+	} else if u.Scheme == "ldaps" && o.StartTLS {
+		errs = append(errs, fmt.Errorf("--ldap.start-tls cannot be used with an ldaps:// url"))
+	}
+	if o.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("--ldap.timeout %v must be greater than 0", o.Timeout))
+	}
+	if o.BaseDN == "" {
+		errs = append(errs, fmt.Errorf("--ldap.base-dn must be specified"))
+	}
+	if strings.Count(o.UserFilter, "%s") != 1 {
+		errs = append(errs, fmt.Errorf("--ldap.user-filter %q must contain exactly one %%s", o.UserFilter))
+	}
+	if len(o.AdminGroups) > 0 && o.GroupAttribute == "" {
+		errs = append(errs, fmt.Errorf("--ldap.group-attribute must be specified when --ldap.admin-groups is set"))
+	}
+
+	return errs
+}
+
+// AddFlags 向指定 FlagSet 中添加 LDAP 选项相关标志.
+func (o *LDAPOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.Enabled, "ldap.enabled", o.Enabled, "Verify user passwords with LDAP instead of local accounts.")
+	fs.StringVar(&o.URL, "ldap.url", o.URL, "The url of LDAP server, e.g. ldaps://ldap.example.com:636.")
+	fs.BoolVar(&o.StartTLS, "ldap.start-tls", o.StartTLS, "Upgrade an ldap:// connection with StartTLS.")
+	fs.StringVar(&o.CAFile, "ldap.ca-file", o.CAFile, ""+
+		"The CA certificate used to verify the LDAP server. Empty means using the system CA pool.")
+	fs.BoolVar(&o.InsecureSkipVerify, "ldap.insecure-skip-verify", o.InsecureSkipVerify, ""+
+		"Skip verifying the certificate of LDAP server. Only for testing.")
+	fs.DurationVar(&o.Timeout, "ldap.timeout", o.Timeout, "Timeout of connecting to and requesting LDAP server.")
+	fs.StringVar(&o.BindDN, "ldap.bind-dn", o.BindDN, ""+
+		"The DN used to search users. Empty means searching anonymously.")
+	fs.StringVar(&o.BindPassword, "ldap.bind-password", o.BindPassword, "The password of ldap.bind-dn.")
+	fs.StringVar(&o.BaseDN, "ldap.base-dn", o.BaseDN, "The DN under which users are searched.")
+	fs.StringVar(&o.UserFilter, "ldap.user-filter", o.UserFilter, ""+
+		"The filter used to search a user, %s is replaced with the escaped username.")
+	fs.StringVar(&o.NicknameAttribute, "ldap.nickname-attribute", o.NicknameAttribute, ""+
+		"The attribute used as nickname of users created on first login.")
+	fs.StringVar(&o.EmailAttribute, "ldap.email-attribute", o.EmailAttribute, ""+
+		"The attribute used as email of users created on first login.")
+	fs.StringVar(&o.PhoneAttribute, "ldap.phone-attribute", o.PhoneAttribute, ""+
+		"The attribute used as phone of users created on first login.")
+	fs.StringVar(&o.GroupAttribute, "ldap.group-attribute", o.GroupAttribute, ""+
+		"The attribute of user entry that lists the DNs of groups the user belongs to.")
+	fs.StringSliceVar(&o.AdminGroups, "ldap.admin-groups", o.AdminGroups, ""+
+		"Members of these group DNs are administrators, others are not. "+
+		"Empty means administrators are managed by skt instead of LDAP.")
+	fs.BoolVar(&o.FallbackToLocal, "ldap.fallback-to-local", o.FallbackToLocal, ""+
+		"Verify with local accounts when the user does not exist in LDAP or LDAP is unavailable.")
+}
@@ -0,0 +1,49 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// LoginOptions 定义了用户登录相关的选项.
+type LoginOptions struct {
+	MaxFailedAttempts int           `json:"max-failed-attempts" mapstructure:"max-failed-attempts"`
+	LockoutDuration   time.Duration `json:"lockout-duration"    mapstructure:"lockout-duration"`
+}
+
+// NewLoginOptions 创建一个默认值的登录选项实例.
+func NewLoginOptions() *LoginOptions {
+	return &LoginOptions{
+		MaxFailedAttempts: 5,
+		LockoutDuration:   15 * time.Minute,
+	}
+}
+
+// Validate 验证登录选项.
+func (o *LoginOptions) Validate() []error {
+	var errs []error
+
+	if o.MaxFailedAttempts < 0 {
+		errs = append(errs, fmt.Errorf("--login.max-failed-attempts %v must not be negative", o.MaxFailedAttempts))
+	}
+	if o.MaxFailedAttempts > 0 && o.LockoutDuration <= 0 {
+		errs = append(errs, fmt.Errorf("--login.lockout-duration %v must be greater than 0", o.LockoutDuration))
+	}
+
+	return errs
+}
+
+// AddFlags 向指定 FlagSet 中添加登录选项相关标志.
+func (o *LoginOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&o.MaxFailedAttempts, "login.max-failed-attempts", o.MaxFailedAttempts, ""+
+		"The number of consecutive failed logins after which the account is locked. 0 disables account lockout.")
+	fs.DurationVar(&o.LockoutDuration, "login.lockout-duration", o.LockoutDuration,
+		"How long an account stays locked after too many failed logins.")
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// MailOptions 定义了发送邮件相关的选项.
+type MailOptions struct {
+	Driver             string        `json:"driver"               mapstructure:"driver"`
+	From               string        `json:"from"                 mapstructure:"from"`
+	Host               string        `json:"host"                 mapstructure:"host"`
+	Port               int           `json:"port"                 mapstructure:"port"`
+	Username           string        `json:"username"             mapstructure:"username"`
+	Password           string        `json:"-"                    mapstructure:"password"`
+	ImplicitTLS        bool          `json:"implicit-tls"         mapstructure:"implicit-tls"`
+	InsecureSkipVerify bool          `json:"insecure-skip-verify" mapstructure:"insecure-skip-verify"`
+	Timeout            time.Duration `json:"timeout"              mapstructure:"timeout"`
+	Dir                string        `json:"dir"                  mapstructure:"dir"`
+	TemplateDir        string        `json:"template-dir"         mapstructure:"template-dir"`
+}
+
+// NewMailOptions 创建一个默认值的邮件选项实例.
+func NewMailOptions() *MailOptions {
+	return &MailOptions{
+		Driver:             "log",
+		From:               "skt <noreply@localhost>",
+		Host:               "",
+		Port:               587,
+		Username:           "",
+		Password:           "",
+		ImplicitTLS:        false,
+		InsecureSkipVerify: false,
+		Timeout:            10 * time.Second,
+		Dir:                "",
+		TemplateDir:        "",
+	}
+}
+
+// Validate 验证邮件选项.
+func (o *MailOptions) Validate() []error {
+	var errs []error
+
+	if _, err := mail.ParseAddress(o.From); err != nil {
+		errs = append(errs, fmt.Errorf("--mail.from %q is not a valid address: %w", o.From, err))
+	}
+
+	switch o.Driver {
+	case "log":
+	case "file":
+		if o.Dir == "" {
+			errs = append(errs, fmt.Errorf("--mail.dir must be specified when --mail.driver is file"))
+		}
+	case "smtp":
+		if o.Host == "" {
+			errs = append(errs, fmt.Errorf("--mail.host must be specified when --mail.driver is smtp"))
+		}
+		if o.Port <= 0 || o.Port > 65535 {
+			errs = append(errs, fmt.Errorf("--mail.port %v must be between 1 and 65535", o.Port))
+		}
+		if o.Timeout <= 0 {
+			errs = append(errs, fmt.Errorf("--mail.timeout %v must be greater than 0", o.Timeout))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("--mail.driver %q must be one of log, file and smtp", o.Driver))
+	}
+
+	return errs
+}
+
+// AddFlags 向指定 FlagSet 中添加邮件选项相关标志.
+func (o *MailOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Driver, "mail.driver", o.Driver, ""+
+		"How to send mails: smtp sends through an SMTP server, file writes each mail to --mail.dir "+
+		"and log writes mails to the log. file and log are meant for development and testing.")
+	fs.StringVar(&o.From, "mail.from", o.From, "The sender address of mails.")
+	fs.StringVar(&o.Host, "mail.host", o.Host, "The SMTP server host.")
+	fs.IntVar(&o.Port, "mail.port", o.Port, "The SMTP server port.")
+	fs.StringVar(&o.Username, "mail.username", o.Username, "The username to authenticate to the SMTP server.")
+	fs.StringVar(&o.Password, "mail.password", o.Password, "The password to authenticate to the SMTP server.")
+	fs.BoolVar(&o.ImplicitTLS, "mail.implicit-tls", o.ImplicitTLS, ""+
+		"Connect to the SMTP server over TLS, usually on port 465. "+
+		"Otherwise STARTTLS is used when the server supports it.")
+	fs.BoolVar(&o.InsecureSkipVerify, "mail.insecure-skip-verify", o.InsecureSkipVerify,
+		"Skip verifying the certificate of the SMTP server.")
+	fs.DurationVar(&o.Timeout, "mail.timeout", o.Timeout, "Timeout of connecting to and sending mails through the SMTP server.")
+	fs.StringVar(&o.Dir, "mail.dir", o.Dir, "The directory to write mails to when --mail.driver is file.")
+	fs.StringVar(&o.TemplateDir, "mail.template-dir", o.TemplateDir, ""+
+		"Directory of mail templates overriding the built-in ones, e.g. password-reset.tmpl.")
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// MFAOptions 定义了两步验证相关的选项.
+type MFAOptions struct {
+	Issuer           string        `json:"issuer"             mapstructure:"issuer"`
+	RequireForAdmins bool          `json:"require-for-admins" mapstructure:"require-for-admins"`
+	ChallengeTTL     time.Duration `json:"challenge-ttl"      mapstructure:"challenge-ttl"`
+	MaxAttempts      int           `json:"max-attempts"       mapstructure:"max-attempts"`
+	RecoveryCodes    int           `json:"recovery-codes"     mapstructure:"recovery-codes"`
+}
+
+// NewMFAOptions 创建一个默认值的两步验证选项实例.
+func NewMFAOptions() *MFAOptions {
+	return &MFAOptions{
+		Issuer:           "skt",
+		RequireForAdmins: false,
+		ChallengeTTL:     5 * time.Minute,
+		MaxAttempts:      5,
+		RecoveryCodes:    10,
+	}
+}
+
+// Validate 验证两步验证选项.
+func (o *MFAOptions) Validate() []error {
+	var errs []error
+
+	if o.Issuer == "" {
+		errs = append(errs, fmt.Errorf("--mfa.issuer must be specified"))
+	}
+	if o.ChallengeTTL <= 0 {
+		errs = append(errs, fmt.Errorf("--mfa.challenge-ttl %v must be greater than 0", o.ChallengeTTL))
+	}
+	if o.MaxAttempts <= 0 {
+		errs = append(errs, fmt.Errorf("--mfa.max-attempts %v must be greater than 0", o.MaxAttempts))
+	}
+	if o.RecoveryCodes < 0 {
+		errs = append(errs, fmt.Errorf("--mfa.recovery-codes %v must not be negative", o.RecoveryCodes))
+	}
+
+	return errs
+}
+
+// AddFlags 向指定 FlagSet 中添加两步验证选项相关标志.
+func (o *MFAOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.Issuer, "mfa.issuer", o.Issuer, "The issuer shown in authenticator apps.")
+	fs.BoolVar(&o.RequireForAdmins, "mfa.require-for-admins", o.RequireForAdmins, ""+
+		"Require administrators to log in with multi-factor authentication. "+
+		"Administrators who have not enabled it are asked to enroll when they log in.")
+	fs.DurationVar(&o.ChallengeTTL, "mfa.challenge-ttl", o.ChallengeTTL, ""+
+		"How long a user has to provide the code after the password is verified.")
+	fs.IntVar(&o.MaxAttempts, "mfa.max-attempts", o.MaxAttempts, ""+
+		"The number of codes that can be tried for one login before the password must be entered again.")
+	fs.IntVar(&o.RecoveryCodes, "mfa.recovery-codes", o.RecoveryCodes, ""+
+		"The number of recovery codes generated when multi-factor authentication is enabled.")
+}
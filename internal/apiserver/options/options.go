@@ -12,20 +12,50 @@ import (
 )
 
 type Options struct {
-	GenericServerRunOptions *genoptions.ServerRunOptions       `json:"server"   mapstructure:"server"`
-	InsecureServing         *genoptions.InsecureServingOptions `json:"insecure" mapstructure:"insecure"`
-	SecureServing           *genoptions.SecureServingOptions   `json:"secure"   mapstructure:"secure"`
-	MySQLOptions            *genoptions.MySQLOptions           `json:"mysql"    mapstructure:"mysql"`
-	Log                     *log.Options                       `json:"log"      mapstructure:"log"`
+	GenericServerRunOptions *genoptions.ServerRunOptions       `json:"server"       mapstructure:"server"`
+	FeatureOptions          *genoptions.FeatureOptions         `json:"feature"      mapstructure:"feature"`
+	CORSOptions             *genoptions.CORSOptions            `json:"cors"         mapstructure:"cors"`
+	RateLimitOptions        *genoptions.RateLimitOptions       `json:"ratelimit"    mapstructure:"ratelimit"`
+	InsecureServing         *genoptions.InsecureServingOptions `json:"insecure"     mapstructure:"insecure"`
+	SecureServing           *genoptions.SecureServingOptions   `json:"secure"       mapstructure:"secure"`
+	GRPCOptions             *genoptions.GRPCOptions            `json:"grpc"         mapstructure:"grpc"`
+	MySQLOptions            *genoptions.MySQLOptions           `json:"mysql"        mapstructure:"mysql"`
+	RedisOptions            *genoptions.RedisOptions           `json:"redis"        mapstructure:"redis"`
+	NotificationOptions     *genoptions.NotificationOptions    `json:"notification" mapstructure:"notification"`
+	JwtOptions              *genoptions.JwtOptions             `json:"jwt"          mapstructure:"jwt"`
+	LoginOptions            *LoginOptions                      `json:"login"        mapstructure:"login"`
+	MFAOptions              *MFAOptions                        `json:"mfa"          mapstructure:"mfa"`
+	PasswordOptions         *PasswordOptions                   `json:"password"     mapstructure:"password"`
+	AccountOptions          *AccountOptions                    `json:"account"      mapstructure:"account"`
+	MailOptions             *MailOptions                       `json:"mail"         mapstructure:"mail"`
+	IdentityProviderOptions *IdentityProviderOptions           `json:"idp"          mapstructure:"idp"`
+	LDAPOptions             *LDAPOptions                       `json:"ldap"         mapstructure:"ldap"`
+	SecretOptions           *SecretOptions                     `json:"secret"       mapstructure:"secret"`
+	Log                     *log.Options                       `json:"log"          mapstructure:"log"`
 }
 
 // NewOptions 使用默认参数创建一个 options 对象.
 func NewOptions() *Options {
 	o := Options{
 		GenericServerRunOptions: genoptions.NewServerRunOptions(),
+		FeatureOptions:          genoptions.NewFeatureOptions(),
+		CORSOptions:             genoptions.NewCORSOptions(),
+		RateLimitOptions:        genoptions.NewRateLimitOptions(),
 		InsecureServing:         genoptions.NewInsecureServingOptions(),
 		SecureServing:           genoptions.NewSecureServingOptions(),
+		GRPCOptions:             genoptions.NewGRPCOptions(),
 		MySQLOptions:            genoptions.NewMySQLOptions(),
+		RedisOptions:            genoptions.NewRedisOptions(),
+		NotificationOptions:     genoptions.NewNotificationOptions(),
+		JwtOptions:              genoptions.NewJwtOptions(),
+		LoginOptions:            NewLoginOptions(),
+		MFAOptions:              NewMFAOptions(),
+		PasswordOptions:         NewPasswordOptions(),
+		AccountOptions:          NewAccountOptions(),
+		MailOptions:             NewMailOptions(),
+		IdentityProviderOptions: NewIdentityProviderOptions(),
+		LDAPOptions:             NewLDAPOptions(),
+		SecretOptions:           NewSecretOptions(),
 		Log:                     log.NewOptions(),
 	}
 	return &o
@@ -33,9 +63,24 @@ func NewOptions() *Options {
 
 func (o *Options) Flags() (fss app.NamedFlagSets) {
 	o.GenericServerRunOptions.AddFlags(fss.FlagSet("generic"))
+	o.FeatureOptions.AddFlags(fss.FlagSet("features"))
+	o.CORSOptions.AddFlags(fss.FlagSet("cors"))
+	o.RateLimitOptions.AddFlags(fss.FlagSet("ratelimit"))
 	o.InsecureServing.AddFlags(fss.FlagSet("insecure serving"))
 	o.SecureServing.AddFlags(fss.FlagSet("secure serving"))
+	o.GRPCOptions.AddFlags(fss.FlagSet("grpc"))
 	o.MySQLOptions.AddFlags(fss.FlagSet("mysql"))
+	o.RedisOptions.AddFlags(fss.FlagSet("redis"))
+	o.NotificationOptions.AddFlags(fss.FlagSet("notification"))
+	o.JwtOptions.AddFlags(fss.FlagSet("jwt"))
+	o.LoginOptions.AddFlags(fss.FlagSet("login"))
+	o.MFAOptions.AddFlags(fss.FlagSet("mfa"))
+	o.PasswordOptions.AddFlags(fss.FlagSet("password"))
+	o.AccountOptions.AddFlags(fss.FlagSet("account"))
+	o.MailOptions.AddFlags(fss.FlagSet("mail"))
+	o.IdentityProviderOptions.AddFlags(fss.FlagSet("idp"))
+	o.LDAPOptions.AddFlags(fss.FlagSet("ldap"))
+	o.SecretOptions.AddFlags(fss.FlagSet("secret"))
 	o.Log.AddFlags(fss.FlagSet("log"))
 
 	return fss
@@ -44,7 +89,23 @@ func (o *Options) Flags() (fss app.NamedFlagSets) {
 func (o *Options) Validate() []error {
 	var errs []error
 
+	errs = append(errs, o.GenericServerRunOptions.Validate()...)
+	errs = append(errs, o.FeatureOptions.Validate()...)
+	errs = append(errs, o.CORSOptions.Validate()...)
+	errs = append(errs, o.RateLimitOptions.Validate()...)
+	errs = append(errs, o.GRPCOptions.Validate()...)
 	errs = append(errs, o.MySQLOptions.Validate()...)
+	errs = append(errs, o.RedisOptions.Validate()...)
+	errs = append(errs, o.NotificationOptions.Validate()...)
+	errs = append(errs, o.JwtOptions.Validate()...)
+	errs = append(errs, o.LoginOptions.Validate()...)
+	errs = append(errs, o.MFAOptions.Validate()...)
+	errs = append(errs, o.PasswordOptions.Validate()...)
+	errs = append(errs, o.AccountOptions.Validate()...)
+	errs = append(errs, o.MailOptions.Validate()...)
+	errs = append(errs, o.IdentityProviderOptions.Validate()...)
+	errs = append(errs, o.LDAPOptions.Validate()...)
+	errs = append(errs, o.SecretOptions.Validate()...)
 	errs = append(errs, o.Log.Validate()...)
 
 	return errs
@@ -0,0 +1,105 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// bcrypt 只使用密码的前 72 个字节.
+const maxBcryptPasswordLength = 72
+
+// PasswordOptions 定义了用户密码策略相关的选项.
+type PasswordOptions struct {
+	MinLength          int           `json:"min-length"            mapstructure:"min-length"`
+	MaxLength          int           `json:"max-length"            mapstructure:"max-length"`
+	RequireUppercase   bool          `json:"require-uppercase"     mapstructure:"require-uppercase"`
+	RequireLowercase   bool          `json:"require-lowercase"     mapstructure:"require-lowercase"`
+	RequireDigit       bool          `json:"require-digit"         mapstructure:"require-digit"`
+	RequireSpecial     bool          `json:"require-special"       mapstructure:"require-special"`
+	BannedFile         string        `json:"banned-file"           mapstructure:"banned-file"`
+	HistorySize        int           `json:"history-size"          mapstructure:"history-size"`
+	MaxAge             time.Duration `json:"max-age"               mapstructure:"max-age"`
+	BcryptCost         int           `json:"bcrypt-cost"           mapstructure:"bcrypt-cost"`
+	UpgradeCostOnLogin bool          `json:"upgrade-cost-on-login" mapstructure:"upgrade-cost-on-login"`
+}
+
+// NewPasswordOptions 创建一个默认值的密码策略选项实例.
+func NewPasswordOptions() *PasswordOptions {
+	return &PasswordOptions{
+		MinLength:          8,
+		MaxLength:          16,
+		RequireUppercase:   true,
+		RequireLowercase:   true,
+		RequireDigit:       true,
+		RequireSpecial:     true,
+		BannedFile:         "",
+		HistorySize:        0,
+		MaxAge:             0,
+		BcryptCost:         bcrypt.DefaultCost,
+		UpgradeCostOnLogin: false,
+	}
+}
+
+// Validate 验证密码策略选项.
+func (o *PasswordOptions) Validate() []error {
+	var errs []error
+
+	if o.MinLength < 1 {
+		errs = append(errs, fmt.Errorf("--password.min-length %v must be greater than 0", o.MinLength))
+	}
+	if o.MaxLength < o.MinLength || o.MaxLength > maxBcryptPasswordLength {
+		errs = append(errs, fmt.Errorf("--password.max-length %v must be between --password.min-length and %d",
+			o.MaxLength, maxBcryptPasswordLength))
+	}
+	if o.BannedFile != "" {
+		if _, err := os.Stat(o.BannedFile); err != nil {
+			errs = append(errs, fmt.Errorf("--password.banned-file: %w", err))
+		}
+	}
+	if o.HistorySize < 0 {
+		errs = append(errs, fmt.Errorf("--password.history-size %v must not be negative", o.HistorySize))
+	}
+	if o.MaxAge < 0 {
+		errs = append(errs, fmt.Errorf("--password.max-age %v must not be negative", o.MaxAge))
+	}
+	if o.BcryptCost < bcrypt.MinCost || o.BcryptCost > bcrypt.MaxCost {
+		errs = append(errs, fmt.Errorf("--password.bcrypt-cost %v must be between %d and %d",
+			o.BcryptCost, bcrypt.MinCost, bcrypt.MaxCost))
+	}
+
+	return errs
+}
+
+// AddFlags 向指定 FlagSet 中添加密码策略选项相关标志.
+func (o *PasswordOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&o.MinLength, "password.min-length", o.MinLength, "The minimum number of characters in a password.")
+	fs.IntVar(&o.MaxLength, "password.max-length", o.MaxLength, ""+
+		"The maximum number of characters in a password, at most 72.")
+	fs.BoolVar(&o.RequireUppercase, "password.require-uppercase", o.RequireUppercase,
+		"Require passwords to contain an uppercase letter.")
+	fs.BoolVar(&o.RequireLowercase, "password.require-lowercase", o.RequireLowercase,
+		"Require passwords to contain a lowercase letter.")
+	fs.BoolVar(&o.RequireDigit, "password.require-digit", o.RequireDigit,
+		"Require passwords to contain a digit.")
+	fs.BoolVar(&o.RequireSpecial, "password.require-special", o.RequireSpecial,
+		"Require passwords to contain a punctuation or symbol character.")
+	fs.StringVar(&o.BannedFile, "password.banned-file", o.BannedFile, ""+
+		"File of passwords that must not be used, one per line, compared case-insensitively.")
+	fs.IntVar(&o.HistorySize, "password.history-size", o.HistorySize, ""+
+		"The number of most recent passwords, including the current one, that cannot be reused. 0 allows reuse.")
+	fs.DurationVar(&o.MaxAge, "password.max-age", o.MaxAge, ""+
+		"How long a password stays valid. Users with an expired password must change it on their next login. "+
+		"0 disables expiry.")
+	fs.IntVar(&o.BcryptCost, "password.bcrypt-cost", o.BcryptCost, "The bcrypt cost used to hash passwords.")
+	fs.BoolVar(&o.UpgradeCostOnLogin, "password.upgrade-cost-on-login", o.UpgradeCostOnLogin, ""+
+		"Rehash passwords stored with a lower bcrypt cost than --password.bcrypt-cost when users log in.")
+}
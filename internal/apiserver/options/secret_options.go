@@ -0,0 +1,41 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+)
+
+// SecretOptions 定义了 secret 资源相关的选项.
+type SecretOptions struct {
+	MaxCount int `json:"max-count" mapstructure:"max-count"`
+}
+
+// NewSecretOptions 创建一个默认值的 secret 选项实例.
+func NewSecretOptions() *SecretOptions {
+	return &SecretOptions{
+		MaxCount: 10,
+	}
+}
+
+// Validate 验证 secret 选项.
+func (o *SecretOptions) Validate() []error {
+	var errs []error
+
+	if o.MaxCount <= 0 {
+		errs = append(errs, fmt.Errorf("--secret.max-count %v must be greater than 0", o.MaxCount))
+	}
+
+	return errs
+}
+
+// AddFlags 向指定 FlagSet 中添加 secret 选项相关标志.
+func (o *SecretOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&o.MaxCount, "secret.max-count", o.MaxCount, ""+
+		"The maximum number of secrets each user can create.")
+}
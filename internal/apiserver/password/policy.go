@@ -0,0 +1,175 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package password
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/changaolee/skeleton/pkg/validation/field"
+)
+
+// Policy 定义了密码策略.
+type Policy struct {
+	// MinLength 和 MaxLength 限制密码的字符数
+	MinLength int
+	MaxLength int
+	// RequireUppercase、RequireLowercase、RequireDigit 和 RequireSpecial 要求密码包含对应类别的字符
+	RequireUppercase bool
+	RequireLowercase bool
+	RequireDigit     bool
+	RequireSpecial   bool
+	// Banned 为禁止使用的密码，使用小写形式保存
+	Banned map[string]struct{}
+	// HistorySize 为不能重复使用的最近密码个数，包括当前密码，为 0 时不限制
+	HistorySize int
+	// MaxAge 为密码的有效期，过期后需要修改密码才能登录，为 0 时不过期
+	MaxAge time.Duration
+	// BcryptCost 为加密密码使用的 bcrypt cost
+	BcryptCost int
+	// UpgradeCostOnLogin 为 true 时，登录时使用 BcryptCost 重新加密 cost 较低的密码
+	UpgradeCostOnLogin bool
+}
+
+// DefaultPolicy 返回默认的密码策略，与 validation.IsValidPassword 的规则一致.
+func DefaultPolicy() Policy {
+	return Policy{
+		MinLength:        8,
+		MaxLength:        16,
+		RequireUppercase: true,
+		RequireLowercase: true,
+		RequireDigit:     true,
+		RequireSpecial:   true,
+		BcryptCost:       bcrypt.DefaultCost,
+	}
+}
+
+// Validate 检查密码是否符合密码策略，每条不满足的规则对应一个错误. 错误中不包含密码明文.
+func (p Policy) Validate(fldPath *field.Path, password string) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if password == "" {
+		return append(allErrs, field.Required(fldPath, ""))
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSpecial bool
+	for _, ch := range password {
+		switch {
+		case unicode.IsNumber(ch):
+			hasDigit = true
+		case unicode.IsUpper(ch):
+			hasUpper = true
+		case unicode.IsLower(ch):
+			hasLower = true
+		case unicode.IsPunct(ch) || unicode.IsSymbol(ch):
+			hasSpecial = true
+		}
+	}
+
+	if n := utf8.RuneCountInString(password); n < p.MinLength {
+		allErrs = append(allErrs, field.Invalid(fldPath, "", fmt.Sprintf("must be at least %d characters long", p.MinLength)))
+	} else if p.MaxLength > 0 && n > p.MaxLength {
+		allErrs = append(allErrs, field.TooLong(fldPath, "", p.MaxLength))
+	}
+	if p.RequireUppercase && !hasUpper {
+		allErrs = append(allErrs, field.Invalid(fldPath, "", "uppercase letter missing"))
+	}
+	if p.RequireLowercase && !hasLower {
+		allErrs = append(allErrs, field.Invalid(fldPath, "", "lowercase letter missing"))
+	}
+	if p.RequireDigit && !hasDigit {
+		allErrs = append(allErrs, field.Invalid(fldPath, "", "at least one numeric character required"))
+	}
+	if p.RequireSpecial && !hasSpecial {
+		allErrs = append(allErrs, field.Invalid(fldPath, "", "special character missing"))
+	}
+	if _, ok := p.Banned[strings.ToLower(password)]; ok {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "password is too common"))
+	}
+
+	return allErrs
+}
+
+// ValidateReuse 检查密码是否与最近使用过的密码相同，hashes 为按时间倒序排列的密码密文，第一个为当前密码.
+func (p Policy) ValidateReuse(fldPath *field.Path, password string, hashes []string) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	for _, hash := range p.Recent(hashes) {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath,
+				fmt.Sprintf("must not reuse any of the last %d passwords", p.HistorySize)))
+			break
+		}
+	}
+
+	return allErrs
+}
+
+// Recent 返回 hashes 中密码策略不允许重复使用的部分.
+func (p Policy) Recent(hashes []string) []string {
+	if len(hashes) > p.HistorySize {
+		return hashes[:p.HistorySize]
+	}
+
+	return hashes
+}
+
+// Hash 使用 BcryptCost 加密密码.
+func (p Policy) Hash(password string) (string, error) {
+	cost := p.BcryptCost
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), cost)
+
+	return string(hashed), err
+}
+
+// NeedsRehash 判断密码密文是否需要使用 BcryptCost 重新加密.
+func (p Policy) NeedsRehash(hash string) bool {
+	if !p.UpgradeCostOnLogin {
+		return false
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+
+	return err == nil && cost < p.BcryptCost
+}
+
+// Expired 判断在 changedAt 时刻设置的密码在 now 时刻是否已经过期.
+func (p Policy) Expired(changedAt, now time.Time) bool {
+	return p.MaxAge > 0 && now.After(changedAt.Add(p.MaxAge))
+}
+
+// LoadBanned 从文件中读取禁止使用的密码，每行一个，忽略空行和以 # 开头的行.
+func LoadBanned(file string) (map[string]struct{}, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	banned := make(map[string]struct{})
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		banned[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read banned passwords from %s: %w", file, err)
+	}
+
+	return banned, nil
+}
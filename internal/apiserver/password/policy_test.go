@@ -0,0 +1,130 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package password
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/changaolee/skeleton/pkg/validation/field"
+)
+
+func TestValidate(t *testing.T) {
+	p := DefaultPolicy()
+	p.Banned = map[string]struct{}{"passw0rd!": {}}
+
+	tests := []struct {
+		password string
+		want     []field.ErrorType
+	}{
+		{"Secret#123", nil},
+		{"", []field.ErrorType{field.ErrorTypeRequired}},
+		{"Ab1!", []field.ErrorType{field.ErrorTypeInvalid}},
+		{"Secret#1234567890", []field.ErrorType{field.ErrorTypeTooLong}},
+		{"secret#123", []field.ErrorType{field.ErrorTypeInvalid}},
+		{"secretpassword", []field.ErrorType{field.ErrorTypeInvalid, field.ErrorTypeInvalid, field.ErrorTypeInvalid}},
+		{"PassW0rd!", []field.ErrorType{field.ErrorTypeForbidden}},
+	}
+	for _, tt := range tests {
+		errs := p.Validate(field.NewPath("password"), tt.password)
+		if len(errs) != len(tt.want) {
+			t.Errorf("Validate(%q) = %v, want %d errors", tt.password, errs, len(tt.want))
+			continue
+		}
+		for i, err := range errs {
+			if err.Type != tt.want[i] {
+				t.Errorf("Validate(%q)[%d] type = %s, want %s", tt.password, i, err.Type, tt.want[i])
+			}
+			if tt.password != "" && strings.Contains(err.Error(), tt.password) {
+				t.Errorf("Validate(%q)[%d] = %q leaks the password", tt.password, i, err.Error())
+			}
+		}
+	}
+}
+
+func TestValidateReuse(t *testing.T) {
+	p := Policy{HistorySize: 2, BcryptCost: bcrypt.MinCost}
+
+	var hashes []string
+	for _, pwd := range []string{"current", "previous", "oldest"} {
+		hash, err := p.Hash(pwd)
+		if err != nil {
+			t.Fatalf("Hash() error = %v", err)
+		}
+		hashes = append(hashes, hash)
+	}
+
+	for pwd, reused := range map[string]bool{"current": true, "previous": true, "oldest": false, "new": false} {
+		if errs := p.ValidateReuse(field.NewPath("newPassword"), pwd, hashes); (len(errs) > 0) != reused {
+			t.Errorf("ValidateReuse(%q) = %v, want reused %v", pwd, errs, reused)
+		}
+	}
+
+	p.HistorySize = 0
+	if errs := p.ValidateReuse(field.NewPath("newPassword"), "current", hashes); len(errs) > 0 {
+		t.Errorf("ValidateReuse() with HistorySize 0 = %v, want no errors", errs)
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	hash, err := Policy{BcryptCost: bcrypt.MinCost}.Hash("secret")
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	p := Policy{BcryptCost: bcrypt.MinCost + 1}
+	if p.NeedsRehash(hash) {
+		t.Error("NeedsRehash() = true when UpgradeCostOnLogin is disabled")
+	}
+	p.UpgradeCostOnLogin = true
+	if !p.NeedsRehash(hash) {
+		t.Error("NeedsRehash() = false for a hash with a lower cost")
+	}
+	p.BcryptCost = bcrypt.MinCost
+	if p.NeedsRehash(hash) {
+		t.Error("NeedsRehash() = true for a hash with the same cost")
+	}
+}
+
+func TestExpired(t *testing.T) {
+	changedAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if (Policy{}).Expired(changedAt, changedAt.AddDate(10, 0, 0)) {
+		t.Error("Expired() = true when MaxAge is 0")
+	}
+	p := Policy{MaxAge: 24 * time.Hour}
+	if p.Expired(changedAt, changedAt.Add(time.Hour)) {
+		t.Error("Expired() = true before MaxAge")
+	}
+	if !p.Expired(changedAt, changedAt.Add(25*time.Hour)) {
+		t.Error("Expired() = false after MaxAge")
+	}
+}
+
+func TestLoadBanned(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "banned.txt")
+	if err := os.WriteFile(file, []byte("# common passwords\nPassword1!\n\n  qwerty  \n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	banned, err := LoadBanned(file)
+	if err != nil {
+		t.Fatalf("LoadBanned() error = %v", err)
+	}
+	if len(banned) != 2 {
+		t.Fatalf("LoadBanned() = %v, want 2 entries", banned)
+	}
+	for _, pwd := range []string{"password1!", "qwerty"} {
+		if _, ok := banned[pwd]; !ok {
+			t.Errorf("LoadBanned() missing %q", pwd)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+// Package publisher 用于在 secret 和 policy 变更后向 skt-authz-server 发布变更通知.
+package publisher
+
+import (
+	"github.com/changaolee/skeleton/internal/pkg/notification"
+)
+
+// Publisher 定义了变更通知发布者.
+type Publisher interface {
+	// Publish 发布一条变更通知，应当在数据变更成功提交后调用.
+	Publish(command notification.Command, payload notification.Payload)
+	// Close 等待未完成的通知发送结束并释放资源.
+	Close() error
+}
+
+var ins Publisher = nopPublisher{}
+
+// Get 获取 Publisher 实例，未设置时返回一个不做任何事情的 Publisher.
+func Get() Publisher {
+	return ins
+}
+
+// Set 设置 Publisher 实例.
+func Set(p Publisher) {
+	ins = p
+}
+
+type nopPublisher struct{}
+
+func (nopPublisher) Publish(notification.Command, notification.Payload) {}
+
+func (nopPublisher) Close() error {
+	return nil
+}
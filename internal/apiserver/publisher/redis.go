@@ -0,0 +1,126 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/changaolee/skeleton/internal/pkg/notification"
+	genoptions "github.com/changaolee/skeleton/internal/pkg/options"
+	"github.com/changaolee/skeleton/pkg/db"
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+const (
+	// maxRetries 为发布失败后的最大重试次数.
+	maxRetries = 5
+	// initialBackoff 为第一次重试前的等待时间，之后每次翻倍.
+	initialBackoff = 200 * time.Millisecond
+	// publishTimeout 为单次发布的超时时间.
+	publishTimeout = 3 * time.Second
+)
+
+// redisClient 是 redisPublisher 依赖的 Redis 客户端方法集合.
+type redisClient interface {
+	Publish(ctx context.Context, channel string, message interface{}) *redis.IntCmd
+	Close() error
+}
+
+type redisPublisher struct {
+	client     redisClient
+	signingKey []byte
+	maxRetries int
+	backoff    time.Duration
+	wg         sync.WaitGroup
+}
+
+var _ Publisher = (*redisPublisher)(nil)
+
+// NewRedisPublisher 创建一个基于 Redis 发布订阅的 Publisher，通知使用 notifyOpts 中的密钥签名.
+func NewRedisPublisher(opts *genoptions.RedisOptions, notifyOpts *genoptions.NotificationOptions) (Publisher, error) {
+	client, err := db.NewRedis(&db.RedisOptions{
+		Host:     opts.Host,
+		Port:     opts.Port,
+		Username: opts.Username,
+		Password: opts.Password,
+		Database: opts.Database,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newRedisPublisher(client, []byte(notifyOpts.SigningKey), maxRetries, initialBackoff), nil
+}
+
+func newRedisPublisher(client redisClient, key []byte, retries int, backoff time.Duration) *redisPublisher {
+	return &redisPublisher{
+		client:     client,
+		signingKey: key,
+		maxRetries: retries,
+		backoff:    backoff,
+	}
+}
+
+// Publish 异步发布一条已签名的通知，Redis 暂时不可用时按指数退避重试.
+func (p *redisPublisher) Publish(command notification.Command, payload notification.Payload) {
+	n, err := notification.New(command, payload, p.signingKey)
+	if err != nil {
+		log.Errorf("Failed to create notification %s: %s", command, err.Error())
+		return
+	}
+
+	data, err := json.Marshal(n)
+	if err != nil {
+		log.Errorf("Failed to marshal notification %s: %s", command, err.Error())
+		return
+	}
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+
+		if err := p.publish(data); err != nil {
+			log.Errorf("Failed to publish notification %s after %d retries: %s", command, p.maxRetries, err.Error())
+			return
+		}
+		log.Debugf("Published notification %s: %s", command, n.Payload)
+	}()
+}
+
+func (p *redisPublisher) publish(data []byte) error {
+	var err error
+	backoff := p.backoff
+
+	for attempt := 0; attempt <= p.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+		err = p.client.Publish(ctx, notification.RedisPubSubChannel, data).Err()
+		cancel()
+		if err == nil {
+			return nil
+		}
+
+		log.Warnf("Publish notification failed (attempt %d): %s", attempt+1, err.Error())
+	}
+
+	return err
+}
+
+// Close 等待所有正在发送的通知完成后关闭 Redis 连接.
+func (p *redisPublisher) Close() error {
+	p.wg.Wait()
+
+	return p.client.Close()
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/changaolee/skeleton/internal/pkg/notification"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+type fakeRedisClient struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+	messages [][]byte
+	closed   bool
+}
+
+func (c *fakeRedisClient) Publish(ctx context.Context, channel string, message interface{}) *redis.IntCmd {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.calls++
+	if c.calls <= c.failures {
+		return redis.NewIntResult(0, errors.New("connection refused"))
+	}
+	c.messages = append(c.messages, message.([]byte))
+
+	return redis.NewIntResult(1, nil)
+}
+
+func (c *fakeRedisClient) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestRedisPublisher_PublishRetry(t *testing.T) {
+	client := &fakeRedisClient{failures: 2}
+	p := newRedisPublisher(client, testKey, 3, time.Millisecond)
+
+	p.Publish(notification.NoticeSecretChanged, notification.Payload{
+		Action:   notification.ActionCreate,
+		Username: "admin",
+		Names:    []string{"secret0"},
+	})
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if client.calls != 3 {
+		t.Errorf("Publish called %d times, want 3", client.calls)
+	}
+	if len(client.messages) != 1 {
+		t.Fatalf("got %d published messages, want 1", len(client.messages))
+	}
+	if !client.closed {
+		t.Errorf("client not closed")
+	}
+
+	var n notification.Notification
+	if err := json.Unmarshal(client.messages[0], &n); err != nil {
+		t.Fatalf("unmarshal notification: %v", err)
+	}
+	if n.Command != notification.NoticeSecretChanged {
+		t.Errorf("unexpected notification command: %s", n.Command)
+	}
+	if err := notification.NewVerifier(testKey, time.Minute).Verify(&n); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+}
+
+func TestRedisPublisher_PublishGiveUp(t *testing.T) {
+	client := &fakeRedisClient{failures: 10}
+	p := newRedisPublisher(client, testKey, 2, time.Millisecond)
+
+	p.Publish(notification.NoticePolicyChanged, notification.Payload{Action: notification.ActionDelete})
+	_ = p.Close()
+
+	if client.calls != 3 {
+		t.Errorf("Publish called %d times, want 3", client.calls)
+	}
+	if len(client.messages) != 0 {
+		t.Errorf("got %d published messages, want 0", len(client.messages))
+	}
+}
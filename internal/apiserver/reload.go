@@ -0,0 +1,117 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package apiserver
+
+import (
+	"strings"
+
+	"github.com/changaolee/skeleton/internal/apiserver/biz"
+	"github.com/changaolee/skeleton/internal/apiserver/options"
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
+	genoptions "github.com/changaolee/skeleton/internal/pkg/options"
+	"github.com/changaolee/skeleton/pkg/app"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+	"github.com/changaolee/skeleton/pkg/reload"
+)
+
+// addReloadCallbacks 注册配置热加载回调.
+func (s *apiServer) addReloadCallbacks() {
+	s.gr.SetErrorHandler(reload.ErrorFunc(func(err error) {
+		log.Errorf("Reload config failed, keep running with the current config: %s", err.Error())
+	}))
+	s.gr.AddCallback(reload.CallbackFunc(s.reloadConfig))
+}
+
+// reloadConfig 重新读取并校验配置，只应用可以在运行时修改的配置项，其他配置项的变更需要重启服务才能生效.
+func (s *apiServer) reloadConfig(manager string) error {
+	opts := options.NewOptions()
+	if err := app.ReloadOptions(opts); err != nil {
+		return errors.Wrap(err, "invalid config")
+	}
+
+	running := s.cfg.Options
+
+	// jwt 的变更一起校验和应用，校验通过后才更新 running，避免 keys 和 active-kid 同时修改时分别校验失败
+	var jwtReloaded bool
+	reloadJwt := func() {
+		if jwtReloaded {
+			return
+		}
+		jwtReloaded = true
+
+		next := *running.JwtOptions
+		next.Timeout = opts.JwtOptions.Timeout
+		next.Keys = opts.JwtOptions.Keys
+		next.ActiveKID = opts.JwtOptions.ActiveKID
+		if err := s.setJwtOptions(&next); err != nil {
+			log.Errorf("Reload jwt options failed, keep using the previous keys: %s", err.Error())
+			return
+		}
+		*running.JwtOptions = next
+	}
+
+	applied, rejected := genoptions.ApplyChangedFields(running, opts, map[string]func(){
+		"log.level": func() {
+			running.Log.Level = opts.Log.Level
+			log.Init(running.Log)
+		},
+		"log.format": func() {
+			running.Log.Format = opts.Log.Format
+			log.Init(running.Log)
+		},
+		"server.middlewares": func() {
+			running.GenericServerRunOptions.Middlewares = opts.GenericServerRunOptions.Middlewares
+			s.genericAPIServer.SetMiddlewares(running.GenericServerRunOptions.Middlewares)
+		},
+		"cors": func() {
+			running.CORSOptions = opts.CORSOptions
+			_ = middleware.SetCorsConfig(running.CORSOptions.CorsConfig())
+		},
+		"login": func() {
+			running.LoginOptions = opts.LoginOptions
+			biz.SetLoginPolicy(newLoginPolicy(running.LoginOptions))
+		},
+		"mfa": func() {
+			running.MFAOptions = opts.MFAOptions
+			biz.SetMFAPolicy(newMFAPolicy(running.MFAOptions))
+		},
+		"password": func() {
+			policy, err := newPasswordPolicy(opts.PasswordOptions)
+			if err != nil {
+				log.Errorf("Reload password policy failed, keep using the previous policy: %s", err.Error())
+				return
+			}
+			running.PasswordOptions = opts.PasswordOptions
+			biz.SetPasswordPolicy(policy)
+		},
+		"jwt.timeout":    reloadJwt,
+		"jwt.keys":       reloadJwt,
+		"jwt.active-kid": reloadJwt,
+	})
+
+	if len(rejected) > 0 {
+		log.Warnf("Reload config (triggered by %s): changes to %s require a restart and are ignored",
+			manager, strings.Join(rejected, ", "))
+	}
+	if len(applied) > 0 {
+		log.Infof("Reload config (triggered by %s): applied changes to %s", manager, strings.Join(applied, ", "))
+	} else {
+		log.Infof("Reload config (triggered by %s): nothing to apply", manager)
+	}
+
+	return nil
+}
+
+// setJwtOptions 校验并更新签发 token 使用的有效时间和密钥，校验失败时继续使用之前的配置.
+func (s *apiServer) setJwtOptions(opts *genoptions.JwtOptions) error {
+	if err := s.jwtAuth.SetOptions(opts); err != nil {
+		return err
+	}
+	s.revoker.SetTokenLifetime(tokenLifetime(opts))
+
+	return nil
+}
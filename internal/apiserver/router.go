@@ -8,33 +8,50 @@ package apiserver
 import (
 	"github.com/changaolee/skeleton/internal/pkg/code"
 	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
+	"github.com/changaolee/skeleton/internal/pkg/middleware/auth"
 	"github.com/changaolee/skeleton/pkg/errors"
 	"github.com/gin-gonic/gin"
 
 	_ "github.com/changaolee/skeleton/internal/pkg/validator"
 
+	"github.com/changaolee/skeleton/internal/apiserver/config"
+	"github.com/changaolee/skeleton/internal/apiserver/controller/v1/policy"
+	"github.com/changaolee/skeleton/internal/apiserver/controller/v1/secret"
 	"github.com/changaolee/skeleton/internal/apiserver/controller/v1/user"
 	"github.com/changaolee/skeleton/internal/apiserver/store/mysql"
-	"github.com/changaolee/skeleton/internal/pkg/middleware/auth"
 )
 
-func initRouter(g *gin.Engine) {
+func initRouter(g *gin.Engine, cfg *config.Config, jwtAuth *reloadableJWTAuth) {
 	installMiddleware(g)
-	installController(g)
+	installController(g, cfg, jwtAuth)
 }
 
 func installMiddleware(g *gin.Engine) {
 }
 
-func installController(g *gin.Engine) {
-	jwtStrategy, _ := newJWTAuth().(auth.JWTStrategy)
-
+func installController(g *gin.Engine, cfg *config.Config, jwtAuth *reloadableJWTAuth) {
 	// 认证相关接口
-	g.POST("/login", jwtStrategy.LoginHandler)     // 用户登录
-	g.POST("/logout", jwtStrategy.LogoutHandler)   // 用户登出
-	g.POST("/refresh", jwtStrategy.RefreshHandler) // 刷新 Token
+	g.POST("/login", jwtAuth.LoginHandler)     // 用户登录
+	g.POST("/logout", jwtAuth.LogoutHandler)   // 用户登出
+	g.POST("/refresh", jwtAuth.RefreshHandler) // 刷新 Token
+
+	// 密码过期后修改密码并登录
+	g.POST("/login/password", jwtAuth.ChangeExpiredPasswordHandler)
 
-	auto := newAutoAuth()
+	// 两步验证登录
+	g.POST("/login/mfa", jwtAuth.MFALoginHandler)         // 提交验证码完成登录
+	g.POST("/login/mfa/enroll", jwtAuth.MFAEnrollHandler) // 登录时开启两步验证
+
+	// 通过外部身份提供方登录
+	federation := newFederatedLogin(cfg.IdentityProviderOptions, jwtAuth)
+	g.GET("/login/idp/:name", federation.LoginHandler)
+	g.GET("/login/idp/:name/callback", federation.CallbackHandler)
+
+	// 验证 token 使用的公钥
+	g.GET("/.well-known/jwks.json", jwtAuth.JWKSHandler)
+
+	auto := newAutoAuth(jwtAuth)
 	g.NoRoute(auto.AuthFunc(), func(c *gin.Context) {
 		core.WriteResponse(c, errors.WithCode(code.ErrPageNotFound, "Page not found."), c.FullPath())
 	})
@@ -48,15 +65,54 @@ func installController(g *gin.Engine) {
 		{
 			userController := user.NewUserController(storeIns)
 
-			userv1.POST("", userController.Create) // 创建用户
+			// 匿名注册普通用户，管理员登录后可以创建管理员
+			userv1.POST("", auth.Optional(auto), userController.Create) // 创建用户
+
+			// 权限检查中间件，按用户限流的规则需要在认证之后执行
+			userv1.Use(auto.AuthFunc(), middleware.AuthenticatedRateLimit())
 
-			// 权限检查中间件
-			userv1.Use(auto.AuthFunc())
+			userv1.DELETE("", userController.DeleteCollection)                 // 批量删除用户
+			userv1.DELETE(":name", userController.Delete)                      // 删除用户
+			userv1.PUT(":name/change-password", userController.ChangePassword) // 修改用户密码
+			userv1.PUT(":name/unlock", userController.Unlock)                  // 解除用户账户锁定
+			userv1.DELETE(":name/tokens", userController.RevokeTokens)         // 吊销用户的所有 token
+			userv1.POST(":name/mfa", userController.EnrollMFA)                 // 生成两步验证密钥
+			userv1.PUT(":name/mfa", userController.ConfirmMFA)                 // 确认开启两步验证
+			userv1.DELETE(":name/mfa", userController.DisableMFA)              // 关闭两步验证
+			userv1.PUT(":name", userController.Update)                         // 更新用户信息
+			userv1.GET("", userController.List)                                // 查询用户列表
+			userv1.GET(":name", userController.Get)                            // 查询用户详情
+		}
+
+		// 邮箱验证和找回密码相关接口，不需要认证
+		accountController := user.NewUserController(storeIns)
+		v1.POST("/email-verification", accountController.RequestEmailVerification) // 发送邮箱验证邮件
+		v1.POST("/email-verification/confirm", accountController.VerifyEmail)      // 验证邮箱
+		v1.POST("/password-reset", accountController.RequestPasswordReset)         // 发送重置密码邮件
+		v1.POST("/password-reset/confirm", accountController.ResetPassword)        // 重置密码
+
+		// 密钥相关接口
+		secretv1 := v1.Group("/secrets", auto.AuthFunc(), middleware.AuthenticatedRateLimit())
+		{
+			secretController := secret.NewSecretController(storeIns, cfg.SecretOptions.MaxCount)
+
+			secretv1.POST("", secretController.Create)        // 创建密钥
+			secretv1.DELETE(":name", secretController.Delete) // 删除密钥
+			secretv1.PUT(":name", secretController.Update)    // 更新密钥
+			secretv1.GET("", secretController.List)           // 查询密钥列表
+			secretv1.GET(":name", secretController.Get)       // 查询密钥详情
+		}
+
+		// 授权策略相关接口
+		policyv1 := v1.Group("/policies", auto.AuthFunc(), middleware.AuthenticatedRateLimit())
+		{
+			policyController := policy.NewPolicyController(storeIns)
 
-			// todo: 用户管理接口
-			// userv1.PUT(":name", userController.Update)
-			// userv1.GET("", userController.List)
-			userv1.GET(":name", userController.Get)
+			policyv1.POST("", policyController.Create)        // 创建授权策略
+			policyv1.DELETE(":name", policyController.Delete) // 删除授权策略
+			policyv1.PUT(":name", policyController.Update)    // 更新授权策略
+			policyv1.GET("", policyController.List)           // 查询授权策略列表
+			policyv1.GET(":name", policyController.Get)       // 查询授权策略详情
 		}
 	}
 }
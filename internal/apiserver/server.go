@@ -6,17 +6,42 @@
 package apiserver
 
 import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+
+	"github.com/changaolee/skeleton/internal/apiserver/authn"
+	"github.com/changaolee/skeleton/internal/apiserver/biz"
 	"github.com/changaolee/skeleton/internal/apiserver/config"
+	"github.com/changaolee/skeleton/internal/apiserver/mail"
+	"github.com/changaolee/skeleton/internal/apiserver/mfa"
+	"github.com/changaolee/skeleton/internal/apiserver/publisher"
 	"github.com/changaolee/skeleton/internal/apiserver/store"
 	"github.com/changaolee/skeleton/internal/apiserver/store/mysql"
+	"github.com/changaolee/skeleton/internal/apiserver/verification"
+	"github.com/changaolee/skeleton/internal/pkg/revocation"
 	genericapiserver "github.com/changaolee/skeleton/internal/pkg/server"
+	"github.com/changaolee/skeleton/pkg/log"
+	"github.com/changaolee/skeleton/pkg/reload"
+	"github.com/changaolee/skeleton/pkg/reload/managers/filewatch"
+	reloadsignal "github.com/changaolee/skeleton/pkg/reload/managers/posixsignal"
 	"github.com/changaolee/skeleton/pkg/shutdown"
 	"github.com/changaolee/skeleton/pkg/shutdown/managers/posixsignal"
 )
 
+// closeTimeout 为关闭单个存储连接或刷新缓冲数据的最长等待时间.
+const closeTimeout = 5 * time.Second
+
 type apiServer struct {
+	cfg              *config.Config
 	gs               *shutdown.GracefulShutdown
+	gr               *reload.GracefulReload
+	jwtAuth          *reloadableJWTAuth
+	revoker          *revocation.RedisRevoker
 	genericAPIServer *genericapiserver.GenericAPIServer
+	gRPCAPIServer    *grpcAPIServer
 }
 
 type preparedAPIServer struct {
@@ -28,6 +53,13 @@ func createAPIServer(cfg *config.Config) (*apiServer, error) {
 	gs := shutdown.New()
 	gs.AddManager(posixsignal.NewPosixSignalManager())
 
+	// 配置热加载组件，收到 SIGHUP 或配置文件变更时触发
+	gr := reload.New()
+	gr.AddManager(reloadsignal.NewPosixSignalManager())
+	if file := viper.ConfigFileUsed(); file != "" {
+		gr.AddManager(filewatch.NewFileWatchManager(file))
+	}
+
 	// Store 实例
 	storeIns, err := mysql.GetMySQLInstance(cfg.MySQLOptions)
 	if err != nil {
@@ -35,8 +67,81 @@ func createAPIServer(cfg *config.Config) (*apiServer, error) {
 	}
 	store.SetStore(storeIns)
 
+	// 变更通知发布者
+	pub, err := publisher.NewRedisPublisher(cfg.RedisOptions, cfg.NotificationOptions)
+	if err != nil {
+		return nil, err
+	}
+	publisher.Set(pub)
+
+	// JWT 吊销列表
+	revoker, err := revocation.NewRedisRevoker(cfg.RedisOptions, tokenLifetime(cfg.JwtOptions))
+	if err != nil {
+		return nil, err
+	}
+	revocation.Set(revoker)
+
+	// 两步验证的登录挑战
+	challenges, err := mfa.NewRedisChallenges(cfg.RedisOptions)
+	if err != nil {
+		return nil, err
+	}
+	mfa.Set(challenges)
+
+	// 邮箱验证和重置密码令牌的使用记录
+	once, err := verification.NewRedisOnce(cfg.RedisOptions)
+	if err != nil {
+		return nil, err
+	}
+	verification.Set(once)
+
+	// 发送邮件的方式
+	mailer, err := newMailer(cfg.MailOptions)
+	if err != nil {
+		return nil, err
+	}
+	mail.Set(mailer)
+
+	// 用户密码的校验方式，未启用 LDAP 时使用本地账户校验
+	if cfg.LDAPOptions.Enabled {
+		verifier, err := authn.NewLDAPVerifier(cfg.LDAPOptions)
+		if err != nil {
+			return nil, err
+		}
+		if cfg.LDAPOptions.FallbackToLocal {
+			verifier = authn.NewFallbackVerifier(verifier, authn.NewLocalVerifier(storeIns))
+		}
+		authn.Set(verifier)
+	}
+
+	// 登录失败锁定账户的策略
+	biz.SetLoginPolicy(newLoginPolicy(cfg.LoginOptions))
+
+	// 两步验证策略
+	biz.SetMFAPolicy(newMFAPolicy(cfg.MFAOptions))
+
+	// 密码策略
+	passwordPolicy, err := newPasswordPolicy(cfg.PasswordOptions)
+	if err != nil {
+		return nil, err
+	}
+	biz.SetPasswordPolicy(passwordPolicy)
+
+	// 邮箱验证和找回密码策略
+	accountPolicy, err := newAccountPolicy(cfg.AccountOptions, cfg.MailOptions)
+	if err != nil {
+		return nil, err
+	}
+	biz.SetAccountPolicy(accountPolicy)
+
+	// 签发和验证 token 使用的认证策略
+	jwtAuth, err := newReloadableJWTAuth(cfg.JwtOptions)
+	if err != nil {
+		return nil, err
+	}
+
 	// APIServer
-	genericConfig, err := buildGenericConfig(cfg)
+	genericConfig, err := buildGenericConfig(cfg, jwtAuth)
 	if err != nil {
 		return nil, err
 	}
@@ -44,24 +149,50 @@ func createAPIServer(cfg *config.Config) (*apiServer, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := genericServer.AddHealthChecks(genericapiserver.NamedCheck("mysql", storeIns.Ping)); err != nil {
+		return nil, err
+	}
 
-	// todo: gRPCServer
+	// gRPCServer
+	gRPCServer, err := newGRPCAPIServer(cfg.GRPCOptions, storeIns)
+	if err != nil {
+		return nil, err
+	}
 
 	server := &apiServer{
+		cfg:              cfg,
 		gs:               gs,
+		gr:               gr,
+		jwtAuth:          jwtAuth,
+		revoker:          revoker,
 		genericAPIServer: genericServer,
+		gRPCAPIServer:    gRPCServer,
 	}
 
 	return server, nil
 }
 
-func buildGenericConfig(cfg *config.Config) (genericConfig *genericapiserver.Config, err error) {
+func buildGenericConfig(cfg *config.Config, jwtAuth *reloadableJWTAuth) (genericConfig *genericapiserver.Config, err error) {
 	genericConfig = genericapiserver.NewConfig()
 
 	// 将 cfg 中的配置更新到 genericConfig 中
 	if err = cfg.GenericServerRunOptions.ApplyTo(genericConfig); err != nil {
 		return
 	}
+	if err = cfg.FeatureOptions.ApplyTo(genericConfig); err != nil {
+		return
+	}
+	if err = cfg.CORSOptions.ApplyTo(genericConfig); err != nil {
+		return
+	}
+	if err = cfg.RateLimitOptions.ApplyTo(genericConfig, cfg.RedisOptions); err != nil {
+		return
+	}
+	// profiling-access 为 admin 时，/debug/pprof 只允许已登录的管理员访问
+	genericConfig.ProfilingAuth = []gin.HandlerFunc{newAutoAuth(jwtAuth).AuthFunc(), adminOnly()}
+	if err = cfg.JwtOptions.ApplyTo(genericConfig); err != nil {
+		return
+	}
 	if err = cfg.InsecureServing.ApplyTo(genericConfig); err != nil {
 		return
 	}
@@ -73,24 +204,68 @@ func buildGenericConfig(cfg *config.Config) (genericConfig *genericapiserver.Con
 }
 
 func (s *apiServer) PrepareRun() *preparedAPIServer {
-	initRouter(s.genericAPIServer.Engine)
+	initRouter(s.genericAPIServer.Engine, s.cfg, s.jwtAuth)
+
+	s.addShutdownCallbacks()
+	s.addReloadCallbacks()
+
+	return &preparedAPIServer{s}
+}
+
+// addShutdownCallbacks 按顺序注册关闭回调：就绪检查失败、等待 HTTP/gRPC 请求处理完成、
+// 发送剩余的变更通知、关闭 MySQL 连接.
+func (s *apiServer) addShutdownCallbacks() {
+	s.gs.SetTimeout(s.cfg.GenericServerRunOptions.ShutdownTimeout)
+	s.gs.SetErrorHandler(shutdown.ErrorFunc(func(err error) {
+		log.Warnf("Graceful shutdown: %s", err.Error())
+	}))
+
+	s.gs.AddPriorityCallback(shutdown.PriorityStopAccepting, 0,
+		shutdown.ContextCallbackFunc(func(ctx context.Context, _ string) error {
+			return s.genericAPIServer.PrepareShutdown(ctx)
+		}))
 
-	s.gs.AddCallback(shutdown.CallbackFunc(func(string) error {
-		// todo: grpc 优雅关闭
+	s.gs.AddPriorityCallback(shutdown.PriorityDrain, 0,
+		shutdown.ContextCallbackFunc(func(ctx context.Context, _ string) error {
+			return s.genericAPIServer.Shutdown(ctx)
+		}))
+	s.gs.AddPriorityCallback(shutdown.PriorityDrain, 0,
+		shutdown.ContextCallbackFunc(func(ctx context.Context, _ string) error {
+			return s.gRPCAPIServer.Close(ctx)
+		}))
 
+	s.gs.AddPriorityCallback(shutdown.PriorityFlush, closeTimeout, shutdown.CallbackFunc(func(string) error {
+		return publisher.Get().Close()
+	}))
+
+	s.gs.AddPriorityCallback(shutdown.PriorityCloseStores, closeTimeout, shutdown.CallbackFunc(func(string) error {
 		mysqlIns, _ := mysql.GetMySQLInstance(nil)
 		if mysqlIns != nil {
-			_ = mysqlIns.Close()
+			return mysqlIns.Close()
 		}
-
-		s.genericAPIServer.Shutdown()
-
 		return nil
 	}))
-
-	return &preparedAPIServer{s}
 }
 
 func (s *preparedAPIServer) Run() error {
-	return s.genericAPIServer.Run()
+	s.gRPCAPIServer.Run()
+
+	// 启动 shutdown 监听
+	if err := s.gs.Start(); err != nil {
+		log.Fatalf("Start shutdown manager failed: %s", err.Error())
+	}
+
+	// 启动配置热加载监听，监听失败不影响服务运行
+	if err := s.gr.Start(); err != nil {
+		log.Warnf("Start reload manager failed: %s", err.Error())
+	}
+
+	if err := s.genericAPIServer.Run(); err != nil {
+		return err
+	}
+
+	// HTTP 服务因优雅关闭而退出时，等待关闭流程结束
+	<-s.gs.Done()
+
+	return nil
 }
@@ -0,0 +1,258 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+// Package fake 提供保存在内存中的 store 实现，用于测试.
+package fake
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/changaolee/skeleton/internal/apiserver/store"
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/model/user"
+	"github.com/changaolee/skeleton/pkg/errors"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+)
+
+// Store 是保存在内存中的 store.IStore，只实现了用户相关的接口.
+type Store struct {
+	users *userStore
+}
+
+var _ store.IStore = (*Store)(nil)
+
+// NewStore 创建一个空的 Store.
+func NewStore() *Store {
+	return &Store{users: &userStore{users: map[string]*user.User{}, identities: map[string]string{}}}
+}
+
+func (s *Store) Users() store.UserStore         { return s.users }
+func (s *Store) Secrets() store.SecretStore     { return nil }
+func (s *Store) Policies() store.PolicyStore    { return nil }
+func (s *Store) Ping(ctx context.Context) error { return nil }
+func (s *Store) Close() error                   { return nil }
+
+// userStore 保存用户的副本，与 MySQL 实现一样只返回可用或等待验证邮箱的用户.
+type userStore struct {
+	mu    sync.Mutex
+	users map[string]*user.User
+	// identities 以 provider/subject 为 key 保存关联的用户名
+	identities map[string]string
+}
+
+func (s *userStore) Create(ctx context.Context, u *user.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[u.Name]; ok {
+		return errors.WithCode(code.ErrUserAlreadyExist, "user %s already exists", u.Name)
+	}
+	u.ID = uint64(len(s.users) + 1)
+	s.users[u.Name] = copyUser(u)
+	return nil
+}
+
+func (s *userStore) CreateWithIdentity(ctx context.Context, u *user.User, identity *user.Identity) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := identity.Provider + "/" + identity.Subject
+	if _, ok := s.users[u.Name]; ok {
+		return errors.WithCode(code.ErrUserAlreadyExist, "user %s already exists", u.Name)
+	}
+	if _, ok := s.identities[key]; ok {
+		return errors.WithCode(code.ErrUserAlreadyExist, "identity %s already exists", key)
+	}
+	u.ID = uint64(len(s.users) + 1)
+	s.users[u.Name] = copyUser(u)
+	identity.Username = u.Name
+	s.identities[key] = u.Name
+	return nil
+}
+
+func (s *userStore) GetByIdentity(ctx context.Context, provider, subject string) (*user.User, error) {
+	s.mu.Lock()
+	username, ok := s.identities[provider+"/"+subject]
+	s.mu.Unlock()
+	if !ok {
+		return nil, errors.WithCode(code.ErrUserNotFound, "identity %s/%s not found", provider, subject)
+	}
+
+	return s.Get(ctx, username)
+}
+
+func (s *userStore) Update(ctx context.Context, u *user.User) error {
+	return s.update(u.Name, func(stored *user.User) {
+		stored.Nickname = u.Nickname
+		stored.Email = u.Email
+		stored.Phone = u.Phone
+		stored.Extend = u.Extend
+		stored.ExtendShadow = u.ExtendShadow
+		stored.EmailVerifiedAt = u.EmailVerifiedAt
+	})
+}
+
+func (s *userStore) UpdatePassword(ctx context.Context, u *user.User) error {
+	return s.update(u.Name, func(stored *user.User) {
+		stored.Password = u.Password
+		stored.PasswordChangedAt = u.PasswordChangedAt
+		stored.PasswordHistory = append([]string(nil), u.PasswordHistory...)
+	})
+}
+
+func (s *userStore) UpdateIsAdmin(ctx context.Context, u *user.User) error {
+	return s.update(u.Name, func(stored *user.User) {
+		stored.IsAdmin = u.IsAdmin
+	})
+}
+
+func (s *userStore) UpdateEmailVerified(ctx context.Context, u *user.User) error {
+	return s.update(u.Name, func(stored *user.User) {
+		stored.EmailVerifiedAt = u.EmailVerifiedAt
+		stored.Status = u.Status
+	})
+}
+
+// update 在持有锁时修改已保存的用户，与 MySQL 实现一样只更新指定的字段.
+func (s *userStore) update(username string, set func(stored *user.User)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored, ok := s.users[username]; ok {
+		set(stored)
+	}
+	return nil
+}
+
+func (s *userStore) Delete(ctx context.Context, username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.users, username)
+	for key, linked := range s.identities {
+		if linked == username {
+			delete(s.identities, key)
+		}
+	}
+	return nil
+}
+
+func (s *userStore) DeleteCollection(ctx context.Context, usernames []string) error {
+	for _, username := range usernames {
+		_ = s.Delete(ctx, username)
+	}
+	return nil
+}
+
+func (s *userStore) Get(ctx context.Context, username string) (*user.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[username]
+	if !ok || (u.Status != user.StatusActive && u.Status != user.StatusUnverified) {
+		return nil, errors.WithCode(code.ErrUserNotFound, "user %s not found", username)
+	}
+	return copyUser(u), nil
+}
+
+func (s *userStore) List(ctx context.Context, opts metav1.ListOptions) (*user.UserList, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := &user.UserList{}
+	for _, u := range s.users {
+		list.Items = append(list.Items, copyUser(u))
+	}
+	list.TotalCount = int64(len(list.Items))
+	return list, nil
+}
+
+func (s *userStore) RecordLoginFailure(ctx context.Context, username string, maxAttempts int, lockedUntil time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u, ok := s.users[username]; ok {
+		u.FailedLoginCount++
+		if u.FailedLoginCount >= maxAttempts {
+			u.FailedLoginCount = 0
+			u.LockedUntil = &lockedUntil
+		}
+	}
+	return nil
+}
+
+func (s *userStore) RecordLoginSuccess(ctx context.Context, username string, ip string, loginAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u, ok := s.users[username]; ok {
+		u.LoginAt = loginAt
+		u.LoginIP = ip
+		u.FailedLoginCount = 0
+		u.LockedUntil = nil
+	}
+	return nil
+}
+
+func (s *userStore) Unlock(ctx context.Context, username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if u, ok := s.users[username]; ok {
+		u.FailedLoginCount = 0
+		u.LockedUntil = nil
+	}
+	return nil
+}
+
+func (s *userStore) UpdateMFA(ctx context.Context, u *user.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored, ok := s.users[u.Name]; ok {
+		stored.MFAEnabled = u.MFAEnabled
+		stored.TOTPSecret = u.TOTPSecret
+		stored.RecoveryCodes = append([]string(nil), u.RecoveryCodes...)
+	}
+	return nil
+}
+
+func (s *userStore) ConsumeRecoveryCode(ctx context.Context, username, hash string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[username]
+	if !ok {
+		return false, nil
+	}
+	for i, h := range u.RecoveryCodes {
+		if h == hash {
+			u.RecoveryCodes = append(u.RecoveryCodes[:i:i], u.RecoveryCodes[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *userStore) RecordTOTPStep(ctx context.Context, username string, step int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[username]
+	if !ok || u.TOTPLastStep >= step {
+		return false, nil
+	}
+	u.TOTPLastStep = step
+	return true, nil
+}
+
+func copyUser(u *user.User) *user.User {
+	c := *u
+	c.RecoveryCodes = append([]string(nil), u.RecoveryCodes...)
+	c.PasswordHistory = append([]string(nil), u.PasswordHistory...)
+	return &c
+}
@@ -6,6 +6,7 @@
 package mysql
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
@@ -26,6 +27,23 @@ func (ds *datastore) Users() store.UserStore {
 	return newUsers(ds)
 }
 
+func (ds *datastore) Secrets() store.SecretStore {
+	return newSecrets(ds)
+}
+
+func (ds *datastore) Policies() store.PolicyStore {
+	return newPolicies(ds)
+}
+
+// Ping 检查数据库连接是否可用.
+func (ds *datastore) Ping(ctx context.Context) error {
+	conn, err := ds.db.DB()
+	if err != nil {
+		return fmt.Errorf("get gorm db instance failed")
+	}
+	return conn.PingContext(ctx)
+}
+
 func (ds *datastore) Close() error {
 	conn, err := ds.db.DB()
 	if err != nil {
@@ -0,0 +1,96 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package mysql
+
+import (
+	"context"
+	"regexp"
+
+	mp "github.com/changaolee/skeleton/internal/pkg/model/policy"
+	"gorm.io/gorm"
+
+	"github.com/changaolee/skeleton/internal/apiserver/store"
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/pkg/errors"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+	"github.com/changaolee/skeleton/pkg/util/gormutil"
+)
+
+type policyStore struct {
+	ds *datastore
+}
+
+var _ store.PolicyStore = (*policyStore)(nil)
+
+func newPolicies(ds *datastore) *policyStore {
+	return &policyStore{ds: ds}
+}
+
+func (p *policyStore) Create(ctx context.Context, policy *mp.Policy) error {
+	err := p.ds.db.Create(&policy).Error
+	if err != nil {
+		if matched, _ := regexp.MatchString("Duplicate entry '.*' for key '.*index_username_name'", err.Error()); matched {
+			return errors.WithCode(code.ErrPolicyAlreadyExist, err.Error())
+		}
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+	return nil
+}
+
+func (p *policyStore) Update(ctx context.Context, policy *mp.Policy) error {
+	err := p.ds.db.Save(policy).Error
+	if err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+	return nil
+}
+
+func (p *policyStore) Delete(ctx context.Context, username, name string) error {
+	result := p.ds.db.Where("username = ? and name = ?", username, name).Delete(&mp.Policy{})
+	if result.Error != nil {
+		return errors.WithCode(code.ErrDatabase, result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return errors.WithCode(code.ErrPolicyNotFound, "policy %s not found", name)
+	}
+	return nil
+}
+
+func (p *policyStore) Get(ctx context.Context, username, name string) (*mp.Policy, error) {
+	policy := &mp.Policy{}
+	err := p.ds.db.Where("username = ? and name = ?", username, name).First(&policy).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithCode(code.ErrPolicyNotFound, err.Error())
+		}
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+	return policy, nil
+}
+
+// List 返回指定用户的 policy 列表，username 为空时返回全部用户的 policy.
+func (p *policyStore) List(ctx context.Context, username string, opts metav1.ListOptions) (*mp.PolicyList, error) {
+	ret := &mp.PolicyList{}
+	ol := gormutil.Unpointer(opts.Offset, opts.Limit)
+
+	db := p.ds.db
+	if username != "" {
+		db = db.Where("username = ?", username)
+	}
+
+	err := db.Offset(ol.Offset).
+		Limit(ol.Limit).
+		Order("id desc").
+		Find(&ret.Items).
+		Offset(-1).
+		Limit(-1).
+		Count(&ret.TotalCount).
+		Error
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+	return ret, nil
+}
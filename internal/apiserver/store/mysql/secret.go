@@ -0,0 +1,155 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package mysql
+
+import (
+	"context"
+	"regexp"
+
+	ms "github.com/changaolee/skeleton/internal/pkg/model/secret"
+	mu "github.com/changaolee/skeleton/internal/pkg/model/user"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/changaolee/skeleton/internal/apiserver/store"
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/pkg/errors"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+	"github.com/changaolee/skeleton/pkg/util/gormutil"
+)
+
+type secretStore struct {
+	ds *datastore
+}
+
+var _ store.SecretStore = (*secretStore)(nil)
+
+func newSecrets(ds *datastore) *secretStore {
+	return &secretStore{ds: ds}
+}
+
+func (s *secretStore) Create(ctx context.Context, secret *ms.Secret) error {
+	return wrapSecretCreateError(s.ds.db.Create(&secret).Error)
+}
+
+// CreateWithQuota 在用户的 secret 数量小于 maxCount 时创建 secret.
+// 事务中先锁定 secret 所属的用户记录，同一用户并发创建 secret 时依次检查数量，不会超过上限.
+func (s *secretStore) CreateWithQuota(ctx context.Context, secret *ms.Secret, maxCount int) error {
+	err := s.ds.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").
+			Where("name = ?", secret.Username).
+			First(&mu.User{}).
+			Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.WithCode(code.ErrUserNotFound, "user %s not found", secret.Username)
+			}
+			return errors.WithCode(code.ErrDatabase, err.Error())
+		}
+
+		count, err := countSecrets(tx, secret.Username)
+		if err != nil {
+			return err
+		}
+		if count >= int64(maxCount) {
+			return errors.WithCode(code.ErrReachMaxCount, "secret count: %d", count)
+		}
+
+		return wrapSecretCreateError(tx.Create(&secret).Error)
+	})
+
+	return wrapSecretTxError(err)
+}
+
+func wrapSecretTxError(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch errors.ParseCoder(err).Code() {
+	case code.ErrUserNotFound, code.ErrReachMaxCount, code.ErrSecretAlreadyExist, code.ErrDatabase:
+		return err
+	}
+	return errors.WithCode(code.ErrDatabase, err.Error())
+}
+
+func wrapSecretCreateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if matched, _ := regexp.MatchString("Duplicate entry '.*' for key '.*index_username_name'", err.Error()); matched {
+		return errors.WithCode(code.ErrSecretAlreadyExist, err.Error())
+	}
+	return errors.WithCode(code.ErrDatabase, err.Error())
+}
+
+func (s *secretStore) Update(ctx context.Context, secret *ms.Secret) error {
+	err := s.ds.db.Save(secret).Error
+	if err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+	return nil
+}
+
+func (s *secretStore) Delete(ctx context.Context, username, name string) error {
+	result := s.ds.db.Where("username = ? and name = ?", username, name).Delete(&ms.Secret{})
+	if result.Error != nil {
+		return errors.WithCode(code.ErrDatabase, result.Error.Error())
+	}
+	if result.RowsAffected == 0 {
+		return errors.WithCode(code.ErrSecretNotFound, "secret %s not found", name)
+	}
+	return nil
+}
+
+func (s *secretStore) Get(ctx context.Context, username, name string) (*ms.Secret, error) {
+	secret := &ms.Secret{}
+	err := s.ds.db.Where("username = ? and name = ?", username, name).First(&secret).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithCode(code.ErrSecretNotFound, err.Error())
+		}
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+	return secret, nil
+}
+
+// List 返回指定用户的 secret 列表，username 为空时返回全部用户的 secret.
+func (s *secretStore) List(ctx context.Context, username string, opts metav1.ListOptions) (*ms.SecretList, error) {
+	ret := &ms.SecretList{}
+	ol := gormutil.Unpointer(opts.Offset, opts.Limit)
+
+	db := s.ds.db
+	if username != "" {
+		db = db.Where("username = ?", username)
+	}
+
+	err := db.Offset(ol.Offset).
+		Limit(ol.Limit).
+		Order("id desc").
+		Find(&ret.Items).
+		Offset(-1).
+		Limit(-1).
+		Count(&ret.TotalCount).
+		Error
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+	return ret, nil
+}
+
+// Count 返回指定用户拥有的 secret 数量.
+func (s *secretStore) Count(ctx context.Context, username string) (int64, error) {
+	return countSecrets(s.ds.db, username)
+}
+
+func countSecrets(db *gorm.DB, username string) (int64, error) {
+	var count int64
+	if err := db.Model(&ms.Secret{}).Where("username = ?", username).Count(&count).Error; err != nil {
+		return 0, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+	return count, nil
+}
@@ -8,15 +8,32 @@ package mysql
 import (
 	"context"
 	"regexp"
+	"time"
 
+	mp "github.com/changaolee/skeleton/internal/pkg/model/policy"
+	ms "github.com/changaolee/skeleton/internal/pkg/model/secret"
 	mu "github.com/changaolee/skeleton/internal/pkg/model/user"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"github.com/changaolee/skeleton/internal/apiserver/store"
 	"github.com/changaolee/skeleton/internal/pkg/code"
 	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/fields"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+	"github.com/changaolee/skeleton/pkg/util/gormutil"
 )
 
+// userSelectableFields 是 list 接口允许通过 fieldSelector 过滤的字段.
+var userSelectableFields = map[string]string{
+	"name":     "name",
+	"nickname": "nickname",
+	"email":    "email",
+	"phone":    "phone",
+	"status":   "status",
+	"isAdmin":  "isAdmin",
+}
+
 type userStore struct {
 	ds *datastore
 }
@@ -30,25 +47,123 @@ func newUsers(ds *datastore) *userStore {
 func (u *userStore) Create(ctx context.Context, user *mu.User) error {
 	err := u.ds.db.Create(&user).Error
 	if err != nil {
-		if matched, _ := regexp.MatchString("Duplicate entry '.*' for key 'index_name'", err.Error()); matched {
-			return errors.WithCode(code.ErrUserAlreadyExist, err.Error())
+		return wrapUserCreateError(err)
+	}
+	return nil
+}
+
+// CreateWithIdentity 在同一事务中创建用户和外部身份关联.
+func (u *userStore) CreateWithIdentity(ctx context.Context, user *mu.User, identity *mu.Identity) error {
+	err := u.ds.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
 		}
-		return errors.WithCode(code.ErrDatabase, err.Error())
+
+		identity.Username = user.Name
+		return tx.Create(identity).Error
+	})
+	if err != nil {
+		return wrapUserCreateError(err)
 	}
 	return nil
 }
 
+// wrapUserCreateError 将用户名或外部身份重复的错误转换为 ErrUserAlreadyExist.
+func wrapUserCreateError(err error) error {
+	if matched, _ := regexp.MatchString("Duplicate entry '.*' for key '.*index_(name|provider_subject)'", err.Error()); matched {
+		return errors.WithCode(code.ErrUserAlreadyExist, err.Error())
+	}
+	return errors.WithCode(code.ErrDatabase, err.Error())
+}
+
+// Update 只更新用户资料相关的字段.
 func (u *userStore) Update(ctx context.Context, user *mu.User) error {
-	err := u.ds.db.Save(user).Error
+	return u.updateColumns(user, "nickname", "email", "phone", "extendShadow", "emailVerifiedAt")
+}
+
+// UpdatePassword 只更新用户密码相关的字段.
+func (u *userStore) UpdatePassword(ctx context.Context, user *mu.User) error {
+	return u.updateColumns(user, "password", "passwordChangedAt", "passwordHistory")
+}
+
+// UpdateIsAdmin 只更新用户是否为管理员.
+func (u *userStore) UpdateIsAdmin(ctx context.Context, user *mu.User) error {
+	return u.updateColumns(user, "isAdmin")
+}
+
+// UpdateEmailVerified 只更新用户的邮箱验证时间和状态.
+func (u *userStore) UpdateEmailVerified(ctx context.Context, user *mu.User) error {
+	return u.updateColumns(user, "emailVerifiedAt", "status")
+}
+
+// updateColumns 只更新 user 中指定的列，零值也会被更新.
+func (u *userStore) updateColumns(user *mu.User, columns ...string) error {
+	err := u.ds.db.Model(user).Select(columns).Updates(user).Error
 	if err != nil {
 		return errors.WithCode(code.ErrDatabase, err.Error())
 	}
 	return nil
 }
 
+// Delete 删除用户，同时删除该用户拥有的 secret 和 policy.
+func (u *userStore) Delete(ctx context.Context, username string) error {
+	err := u.ds.db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteUserResources(tx, []string{username}); err != nil {
+			return err
+		}
+
+		result := tx.Where("name = ?", username).Delete(&mu.User{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.WithCode(code.ErrUserNotFound, "user %s not found", username)
+		}
+		return nil
+	})
+
+	return wrapUserTxError(err)
+}
+
+// DeleteCollection 批量删除用户，同时删除这些用户拥有的 secret 和 policy.
+func (u *userStore) DeleteCollection(ctx context.Context, usernames []string) error {
+	err := u.ds.db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteUserResources(tx, usernames); err != nil {
+			return err
+		}
+
+		return tx.Where("name in (?)", usernames).Delete(&mu.User{}).Error
+	})
+
+	return wrapUserTxError(err)
+}
+
+func deleteUserResources(tx *gorm.DB, usernames []string) error {
+	if err := tx.Where("username in (?)", usernames).Delete(&mu.Identity{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("username in (?)", usernames).Delete(&mp.Policy{}).Error; err != nil {
+		return err
+	}
+
+	return tx.Where("username in (?)", usernames).Delete(&ms.Secret{}).Error
+}
+
+func wrapUserTxError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.IsCode(err, code.ErrUserNotFound) {
+		return err
+	}
+	return errors.WithCode(code.ErrDatabase, err.Error())
+}
+
+// Get 返回可用或等待验证邮箱的用户，是否允许等待验证邮箱的用户登录由 biz 层决定.
 func (u *userStore) Get(ctx context.Context, username string) (*mu.User, error) {
 	user := &mu.User{}
-	err := u.ds.db.Where("name = ? and status = 1", username).First(&user).Error
+	err := u.ds.db.Where("name = ? and status in (?)", username, []int{mu.StatusActive, mu.StatusUnverified}).
+		First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, errors.WithCode(code.ErrUserNotFound, err.Error())
@@ -57,3 +172,156 @@ func (u *userStore) Get(ctx context.Context, username string) (*mu.User, error)
 	}
 	return user, nil
 }
+
+// GetByIdentity 通过外部身份关联查找用户，只返回可用或等待验证邮箱的用户.
+func (u *userStore) GetByIdentity(ctx context.Context, provider, subject string) (*mu.User, error) {
+	user := &mu.User{}
+	err := u.ds.db.Joins("JOIN user_identity ON user_identity.username = user.name").
+		Where("user_identity.provider = ? and user_identity.subject = ? and user.status in (?)",
+			provider, subject, []int{mu.StatusActive, mu.StatusUnverified}).
+		First(&user).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.WithCode(code.ErrUserNotFound, err.Error())
+		}
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+	return user, nil
+}
+
+// List 返回用户列表，支持通过 fieldSelector 按字段过滤.
+func (u *userStore) List(ctx context.Context, opts metav1.ListOptions) (*mu.UserList, error) {
+	ret := &mu.UserList{}
+	ol := gormutil.Unpointer(opts.Offset, opts.Limit)
+
+	selector, err := fields.ParseSelector(opts.FieldSelector)
+	if err != nil {
+		return nil, errors.WithCode(code.ErrValidation, err.Error())
+	}
+
+	db := u.ds.db
+	for _, r := range selector {
+		column, ok := userSelectableFields[r.Field]
+		if !ok {
+			return nil, errors.WithCode(code.ErrValidation, "field %s is not selectable", r.Field)
+		}
+
+		if r.Operator == fields.NotEquals {
+			db = db.Where(column+" <> ?", r.Value)
+		} else {
+			db = db.Where(column+" = ?", r.Value)
+		}
+	}
+
+	err = db.Offset(ol.Offset).
+		Limit(ol.Limit).
+		Order("id desc").
+		Find(&ret.Items).
+		Offset(-1).
+		Limit(-1).
+		Count(&ret.TotalCount).
+		Error
+	if err != nil {
+		return nil, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+	return ret, nil
+}
+
+// RecordLoginFailure 原子地增加用户连续登录失败的次数，达到 maxAttempts 时锁定账户.
+// MySQL 按顺序执行 SET 中的赋值，lockedUntil 需要在 failedLoginCount 之前计算.
+func (u *userStore) RecordLoginFailure(ctx context.Context, username string, maxAttempts int, lockedUntil time.Time) error {
+	err := u.ds.db.Exec("UPDATE user SET "+
+		"lockedUntil = IF(failedLoginCount + 1 >= ?, ?, lockedUntil), "+
+		"failedLoginCount = IF(failedLoginCount + 1 >= ?, 0, failedLoginCount + 1) "+
+		"WHERE name = ?", maxAttempts, lockedUntil, maxAttempts, username).Error
+	if err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+	return nil
+}
+
+// RecordLoginSuccess 记录用户登录成功的时间和来源 IP，并清零失败次数.
+func (u *userStore) RecordLoginSuccess(ctx context.Context, username string, ip string, loginAt time.Time) error {
+	err := u.ds.db.Model(&mu.User{}).Where("name = ?", username).Updates(map[string]interface{}{
+		"loginAt":          loginAt,
+		"loginIP":          ip,
+		"failedLoginCount": 0,
+		"lockedUntil":      nil,
+	}).Error
+	if err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+	return nil
+}
+
+// Unlock 解除用户账户的锁定并清零失败次数.
+func (u *userStore) Unlock(ctx context.Context, username string) error {
+	err := u.ds.db.Model(&mu.User{}).Where("name = ?", username).Updates(map[string]interface{}{
+		"failedLoginCount": 0,
+		"lockedUntil":      nil,
+	}).Error
+	if err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+	return nil
+}
+
+// UpdateMFA 只更新用户两步验证相关的字段，避免覆盖并发记录的 TOTP 时间步.
+func (u *userStore) UpdateMFA(ctx context.Context, user *mu.User) error {
+	err := u.ds.db.Model(user).Select("mfaEnabled", "totpSecret", "recoveryCodes").Updates(user).Error
+	if err != nil {
+		return errors.WithCode(code.ErrDatabase, err.Error())
+	}
+	return nil
+}
+
+// RecordTOTPStep 原子地记录用户最近使用的 TOTP 时间步，同一时间步的验证码只能使用一次.
+func (u *userStore) RecordTOTPStep(ctx context.Context, username string, step int64) (bool, error) {
+	result := u.ds.db.Model(&mu.User{}).
+		Where("name = ? and totpLastStep < ?", username, step).
+		Update("totpLastStep", step)
+	if result.Error != nil {
+		return false, errors.WithCode(code.ErrDatabase, result.Error.Error())
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ConsumeRecoveryCode 在事务中锁定用户记录后删除恢复码，同一个恢复码并发使用时只有一个请求成功.
+func (u *userStore) ConsumeRecoveryCode(ctx context.Context, username, hash string) (bool, error) {
+	var consumed bool
+	err := u.ds.db.Transaction(func(tx *gorm.DB) error {
+		user := &mu.User{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "recoveryCodes").
+			Where("name = ?", username).
+			First(user).
+			Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		for i, h := range user.RecoveryCodes {
+			if h != hash {
+				continue
+			}
+
+			user.RecoveryCodes = append(user.RecoveryCodes[:i:i], user.RecoveryCodes[i+1:]...)
+			if err := tx.Model(user).Select("recoveryCodes").Updates(user).Error; err != nil {
+				return err
+			}
+			consumed = true
+
+			return nil
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, errors.WithCode(code.ErrDatabase, err.Error())
+	}
+
+	return consumed, nil
+}
@@ -0,0 +1,21 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package store
+
+import (
+	"context"
+
+	"github.com/changaolee/skeleton/internal/pkg/model/policy"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+)
+
+type PolicyStore interface {
+	Create(ctx context.Context, policy *policy.Policy) error
+	Update(ctx context.Context, policy *policy.Policy) error
+	Delete(ctx context.Context, username, name string) error
+	Get(ctx context.Context, username, name string) (*policy.Policy, error)
+	List(ctx context.Context, username string, opts metav1.ListOptions) (*policy.PolicyList, error)
+}
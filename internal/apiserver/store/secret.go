@@ -0,0 +1,24 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package store
+
+import (
+	"context"
+
+	"github.com/changaolee/skeleton/internal/pkg/model/secret"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+)
+
+type SecretStore interface {
+	Create(ctx context.Context, secret *secret.Secret) error
+	// CreateWithQuota 在用户的 secret 数量小于 maxCount 时创建 secret，检查数量和创建在同一个事务中完成.
+	CreateWithQuota(ctx context.Context, secret *secret.Secret, maxCount int) error
+	Update(ctx context.Context, secret *secret.Secret) error
+	Delete(ctx context.Context, username, name string) error
+	Get(ctx context.Context, username, name string) (*secret.Secret, error)
+	List(ctx context.Context, username string, opts metav1.ListOptions) (*secret.SecretList, error)
+	Count(ctx context.Context, username string) (int64, error)
+}
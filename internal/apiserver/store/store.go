@@ -5,9 +5,14 @@
 
 package store
 
+import "context"
+
 // IStore 定义了 Store 层接口.
 type IStore interface {
 	Users() UserStore
+	Secrets() SecretStore
+	Policies() PolicyStore
+	Ping(ctx context.Context) error
 	Close() error
 }
 
@@ -7,12 +7,42 @@ package store
 
 import (
 	"context"
+	"time"
 
 	"github.com/changaolee/skeleton/internal/pkg/model/user"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
 )
 
 type UserStore interface {
 	Create(ctx context.Context, user *user.User) error
+	// Update 只更新用户的昵称、邮箱、手机号、扩展字段和邮箱验证时间，其他字段使用专门的方法更新，
+	// 避免使用之前读取的用户覆盖并发修改的登录状态和两步验证信息.
 	Update(ctx context.Context, user *user.User) error
+	// UpdatePassword 只更新用户的密码、密码修改时间和历史密码.
+	UpdatePassword(ctx context.Context, user *user.User) error
+	// UpdateIsAdmin 只更新用户是否为管理员.
+	UpdateIsAdmin(ctx context.Context, user *user.User) error
+	// UpdateEmailVerified 只更新用户的邮箱验证时间和状态.
+	UpdateEmailVerified(ctx context.Context, user *user.User) error
+	Delete(ctx context.Context, username string) error
+	DeleteCollection(ctx context.Context, usernames []string) error
+	// Get 返回可用或等待验证邮箱的用户，不可用的用户视为不存在.
 	Get(ctx context.Context, username string) (*user.User, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*user.UserList, error)
+	// GetByIdentity 返回关联了外部身份提供方 provider 中 subject 身份的用户，没有关联时返回 ErrUserNotFound.
+	GetByIdentity(ctx context.Context, provider, subject string) (*user.User, error)
+	// CreateWithIdentity 在同一事务中创建用户并关联外部身份，用户名或外部身份已存在时返回 ErrUserAlreadyExist.
+	CreateWithIdentity(ctx context.Context, user *user.User, identity *user.Identity) error
+	// RecordLoginFailure 将用户连续登录失败的次数加一，达到 maxAttempts 时锁定账户到 lockedUntil 并清零失败次数.
+	RecordLoginFailure(ctx context.Context, username string, maxAttempts int, lockedUntil time.Time) error
+	// RecordLoginSuccess 记录用户登录成功的时间和来源 IP，并清零失败次数.
+	RecordLoginSuccess(ctx context.Context, username string, ip string, loginAt time.Time) error
+	// Unlock 解除用户账户的锁定并清零失败次数.
+	Unlock(ctx context.Context, username string) error
+	// UpdateMFA 只更新用户两步验证的开启状态、TOTP 密钥和恢复码.
+	UpdateMFA(ctx context.Context, user *user.User) error
+	// RecordTOTPStep 在 step 大于用户最近使用的 TOTP 时间步时记录 step 并返回 true，否则返回 false.
+	RecordTOTPStep(ctx context.Context, username string, step int64) (bool, error)
+	// ConsumeRecoveryCode 在用户的恢复码中仍有 hash 时删除 hash 并返回 true，否则返回 false.
+	ConsumeRecoveryCode(ctx context.Context, username, hash string) (bool, error)
 }
@@ -0,0 +1,60 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package verification
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Once 记录在一段时间内只能发生一次的事件，用于保证令牌只能使用一次和限制邮件的发送频率.
+type Once interface {
+	// Mark 标记 key 在 ttl 内已经发生，返回 false 表示 key 在此之前已被标记.
+	Mark(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+var ins Once = newMemoryOnce()
+
+// Get 获取 Once 实例，未设置时返回一个保存在内存中的实例.
+func Get() Once {
+	return ins
+}
+
+// Set 设置 Once 实例.
+func Set(o Once) {
+	ins = o
+}
+
+// memoryOnce 将标记保存在内存中，只适用于单副本部署.
+type memoryOnce struct {
+	mu     sync.Mutex
+	marked map[string]time.Time
+	now    func() time.Time
+}
+
+func newMemoryOnce() *memoryOnce {
+	return &memoryOnce{marked: map[string]time.Time{}, now: time.Now}
+}
+
+func (m *memoryOnce) Mark(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	for k, expiresAt := range m.marked {
+		if !now.Before(expiresAt) {
+			delete(m.marked, k)
+		}
+	}
+
+	if _, ok := m.marked[key]; ok {
+		return false, nil
+	}
+	m.marked[key] = now.Add(ttl)
+
+	return true, nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package verification
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	genoptions "github.com/changaolee/skeleton/internal/pkg/options"
+	"github.com/changaolee/skeleton/pkg/db"
+)
+
+// onceKeyPrefix 为标记在 Redis 中的 key 前缀.
+const onceKeyPrefix = "skt:verification:"
+
+// RedisOnce 将标记保存在 Redis 中，所有 skt-apiserver 副本共享.
+type RedisOnce struct {
+	client redis.UniversalClient
+}
+
+var _ Once = (*RedisOnce)(nil)
+
+// NewRedisOnce 创建一个基于 Redis 的 Once.
+func NewRedisOnce(opts *genoptions.RedisOptions) (*RedisOnce, error) {
+	client, err := db.NewRedis(&db.RedisOptions{
+		Host:     opts.Host,
+		Port:     opts.Port,
+		Username: opts.Username,
+		Password: opts.Password,
+		Database: opts.Database,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RedisOnce{client: client}, nil
+}
+
+// Mark 标记 key 在 ttl 内已经发生.
+func (r *RedisOnce) Mark(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, onceKeyPrefix+key, 1, ttl).Result()
+}
@@ -0,0 +1,117 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package verification
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Purpose 为令牌的用途，不同用途的令牌不能混用.
+type Purpose string
+
+const (
+	// PurposeEmailVerification 表示验证邮箱的令牌.
+	PurposeEmailVerification Purpose = "email-verification"
+	// PurposePasswordReset 表示重置密码的令牌.
+	PurposePasswordReset Purpose = "password-reset"
+)
+
+var (
+	// ErrInvalidToken 表示令牌格式错误、签名错误或用途不匹配.
+	ErrInvalidToken = errors.New("invalid verification token")
+	// ErrTokenExpired 表示令牌已过期.
+	ErrTokenExpired = errors.New("verification token has expired")
+)
+
+// Claims 是令牌中携带的信息.
+type Claims struct {
+	// ID 为令牌的唯一标识，用于保证令牌只能使用一次
+	ID      string  `json:"jti"`
+	Purpose Purpose `json:"pur"`
+	// Username 为令牌所属的用户
+	Username string `json:"sub"`
+	// Binding 为签发令牌时用户状态的指纹，状态变化后令牌失效，见 Fingerprint
+	Binding   string `json:"bnd"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// Signer 使用 HMAC-SHA256 签发和校验令牌.
+type Signer struct {
+	key []byte
+}
+
+// NewSigner 使用 key 创建一个 Signer.
+func NewSigner(key []byte) *Signer {
+	return &Signer{key: key}
+}
+
+// Sign 签发一个 ttl 后过期的令牌，返回令牌和令牌中的信息.
+func (s *Signer) Sign(purpose Purpose, username, binding string, ttl time.Duration) (string, *Claims, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", nil, err
+	}
+
+	claims := &Claims{
+		ID:        base64.RawURLEncoding.EncodeToString(id),
+		Purpose:   purpose,
+		Username:  username,
+		Binding:   binding,
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", nil, err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	return encoded + "." + s.signature(encoded), claims, nil
+}
+
+// Parse 校验令牌的签名、用途和有效期，返回令牌中的信息.
+func (s *Signer) Parse(token string, purpose Purpose, now time.Time) (*Claims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(s.signature(encoded))) {
+		return nil, ErrInvalidToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Purpose != purpose || claims.ID == "" {
+		return nil, ErrInvalidToken
+	}
+	if !now.Before(time.Unix(claims.ExpiresAt, 0)) {
+		return nil, ErrTokenExpired
+	}
+
+	return &claims, nil
+}
+
+func (s *Signer) signature(encoded string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(encoded))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Fingerprint 返回 value 的摘要，用作令牌的 Binding，避免在令牌中暴露原始值.
+func Fingerprint(value string) string {
+	sum := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(sum[:8])
+}
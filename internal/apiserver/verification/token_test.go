@@ -0,0 +1,79 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package verification
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSigner(t *testing.T) {
+	s := NewSigner([]byte("0123456789abcdef0123456789abcdef"))
+	binding := Fingerprint("colin@example.com")
+
+	token, claims, err := s.Sign(PurposeEmailVerification, "colin", binding, time.Hour)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	if strings.Contains(token, "example.com") {
+		t.Errorf("Sign() = %s exposes the bound value", token)
+	}
+
+	got, err := s.Parse(token, PurposeEmailVerification, time.Now())
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if *got != *claims || got.Username != "colin" || got.Binding != binding {
+		t.Errorf("Parse() = %+v, want %+v", got, claims)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		purpose Purpose
+		now     time.Time
+		want    error
+	}{
+		{"wrong purpose", token, PurposePasswordReset, time.Now(), ErrInvalidToken},
+		{"expired", token, PurposeEmailVerification, time.Now().Add(2 * time.Hour), ErrTokenExpired},
+		{"tampered", "e30" + token[3:], PurposeEmailVerification, time.Now(), ErrInvalidToken},
+		{"no signature", strings.Split(token, ".")[0], PurposeEmailVerification, time.Now(), ErrInvalidToken},
+		{"other key", token, PurposeEmailVerification, time.Now(), ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		signer := s
+		if tt.name == "other key" {
+			signer = NewSigner([]byte("another key"))
+		}
+		if _, err := signer.Parse(tt.token, tt.purpose, tt.now); err != tt.want {
+			t.Errorf("%s: Parse() error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestMemoryOnce(t *testing.T) {
+	now := time.Now()
+	o := newMemoryOnce()
+	o.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	if ok, _ := o.Mark(ctx, "a", time.Minute); !ok {
+		t.Fatal("first Mark() = false")
+	}
+	if ok, _ := o.Mark(ctx, "a", time.Minute); ok {
+		t.Fatal("second Mark() = true")
+	}
+	if ok, _ := o.Mark(ctx, "b", time.Minute); !ok {
+		t.Fatal("Mark() of another key = false")
+	}
+
+	now = now.Add(time.Minute)
+	if ok, _ := o.Mark(ctx, "a", time.Minute); !ok {
+		t.Fatal("Mark() after ttl = false")
+	}
+}
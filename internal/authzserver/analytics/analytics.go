@@ -5,8 +5,22 @@
 
 package analytics
 
-import "time"
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"github.com/changaolee/skeleton/internal/authzserver/options"
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+// AnalyticsKeyName 是 Redis 中存储授权审计日志的列表名称.
+const AnalyticsKeyName = "skt-system-analytics"
+
+const recordsBufferForcedFlushInterval = 1 * time.Second
+
+// Record 是一条授权审计日志.
 type Record struct {
 	TimeStamp  int64     `json:"timestamp"`
 	Username   string    `json:"username"`
@@ -17,3 +31,207 @@ type Record struct {
 	Deciders   string    `json:"deciders"`
 	ExpireAt   time.Time `json:"expireAt"`
 }
+
+// SetExpiry 设置审计日志的过期时间，expiresIn 为 0 时表示永不过期.
+func (r *Record) SetExpiry(expiresIn time.Duration) {
+	if expiresIn <= 0 {
+		r.ExpireAt = time.Time{}
+		return
+	}
+
+	r.ExpireAt = time.Unix(r.TimeStamp, 0).Add(expiresIn)
+}
+
+// Expired 判断审计日志在 now 时刻是否已经过期.
+func (r *Record) Expired(now time.Time) bool {
+	return !r.ExpireAt.IsZero() && !now.Before(r.ExpireAt)
+}
+
+// AnalyticsHandler 定义了授权审计日志的存储接口.
+type AnalyticsHandler interface {
+	AppendToSetPipelined(ctx context.Context, key string, values [][]byte) error
+}
+
+// Stats 是授权审计日志处理的统计信息.
+type Stats struct {
+	// Recorded 是写入缓冲区的审计日志数量.
+	Recorded uint64
+	// Dropped 是因为缓冲区已满而丢弃的审计日志数量.
+	Dropped uint64
+	// Expired 是写入存储前已经过期而丢弃的审计日志数量.
+	Expired uint64
+	// Written 是成功写入存储的审计日志数量.
+	Written uint64
+	// Failed 是写入存储失败的审计日志数量.
+	Failed uint64
+}
+
+// Analytics 将授权审计日志缓存到内存中，并由一组 worker 批量写入 Redis.
+type Analytics struct {
+	store                 AnalyticsHandler
+	serializer            Serializer
+	poolSize              int
+	recordsChan           chan *Record
+	workerBufferSize      int
+	flushInterval         time.Duration
+	storageExpirationTime time.Duration
+
+	// mu 保证 recordsChan 关闭后不会再有写入
+	mu         sync.RWMutex
+	shouldStop bool
+	poolWg     sync.WaitGroup
+	stopOnce   sync.Once
+
+	recorded uint64
+	dropped  uint64
+	expired  uint64
+	written  uint64
+	failed   uint64
+}
+
+var analytics *Analytics
+
+// NewAnalytics 创建一个 Analytics 实例，并设置为全局实例.
+func NewAnalytics(opts *options.AnalyticsOptions, store AnalyticsHandler) *Analytics {
+	workerBufferSize := opts.RecordsBufferSize / opts.PoolSize
+	if workerBufferSize <= 0 {
+		workerBufferSize = 1
+	}
+	log.Debugf("Analytics pool worker buffer size: %d", workerBufferSize)
+
+	analytics = &Analytics{
+		store:                 store,
+		serializer:            NewSerializer(opts.Serializer),
+		poolSize:              opts.PoolSize,
+		recordsChan:           make(chan *Record, opts.RecordsBufferSize),
+		workerBufferSize:      workerBufferSize,
+		flushInterval:         opts.FlushInterval,
+		storageExpirationTime: opts.StorageExpirationTime,
+	}
+
+	return analytics
+}
+
+// GetAnalytics 返回全局的 Analytics 实例，未启用时返回 nil.
+func GetAnalytics() *Analytics {
+	return analytics
+}
+
+// Start 启动所有 worker.
+func (r *Analytics) Start() {
+	for i := 0; i < r.poolSize; i++ {
+		r.poolWg.Add(1)
+		go r.recordWorker()
+	}
+}
+
+// Stop 停止接收新的审计日志，并等待所有缓冲的审计日志写入存储.
+func (r *Analytics) Stop() {
+	r.stopOnce.Do(func() {
+		r.mu.Lock()
+		r.shouldStop = true
+		close(r.recordsChan)
+		r.mu.Unlock()
+
+		r.poolWg.Wait()
+
+		stats := r.Stats()
+		log.Infow("Analytics stopped",
+			"recorded", stats.Recorded, "dropped", stats.Dropped, "expired", stats.Expired,
+			"written", stats.Written, "failed", stats.Failed)
+	})
+}
+
+// RecordHit 将审计日志写入缓冲区，缓冲区已满时丢弃该日志.
+func (r *Analytics) RecordHit(record *Record) error {
+	if record.ExpireAt.IsZero() {
+		record.SetExpiry(r.storageExpirationTime)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.shouldStop {
+		atomic.AddUint64(&r.dropped, 1)
+		return nil
+	}
+
+	select {
+	case r.recordsChan <- record:
+		atomic.AddUint64(&r.recorded, 1)
+	default:
+		if atomic.AddUint64(&r.dropped, 1)%1000 == 1 {
+			log.Warnf("Analytics records buffer is full, %d records dropped so far", atomic.LoadUint64(&r.dropped))
+		}
+	}
+
+	return nil
+}
+
+// Stats 返回授权审计日志处理的统计信息.
+func (r *Analytics) Stats() Stats {
+	return Stats{
+		Recorded: atomic.LoadUint64(&r.recorded),
+		Dropped:  atomic.LoadUint64(&r.dropped),
+		Expired:  atomic.LoadUint64(&r.expired),
+		Written:  atomic.LoadUint64(&r.written),
+		Failed:   atomic.LoadUint64(&r.failed),
+	}
+}
+
+func (r *Analytics) recordWorker() {
+	defer r.poolWg.Done()
+
+	// 缓冲区大小固定，避免每次 flush 后重新分配内存
+	recordsBuffer := make([][]byte, 0, r.workerBufferSize)
+
+	lastSentTS := time.Now()
+	for {
+		var readyToSend bool
+		select {
+		case record, ok := <-r.recordsChan:
+			// recordsChan 已关闭，写入剩余的审计日志后退出
+			if !ok {
+				r.flush(recordsBuffer)
+				return
+			}
+
+			if record.Expired(time.Now()) {
+				atomic.AddUint64(&r.expired, 1)
+				break
+			}
+
+			encoded, err := r.serializer.Encode(record)
+			if err != nil {
+				log.Errorf("Error encoding analytics record: %s", err.Error())
+				atomic.AddUint64(&r.failed, 1)
+				break
+			}
+			recordsBuffer = append(recordsBuffer, encoded)
+
+			readyToSend = len(recordsBuffer) == r.workerBufferSize
+		case <-time.After(r.flushInterval):
+			readyToSend = true
+		}
+
+		if len(recordsBuffer) > 0 && (readyToSend || time.Since(lastSentTS) >= recordsBufferForcedFlushInterval) {
+			r.flush(recordsBuffer)
+			recordsBuffer = recordsBuffer[:0]
+			lastSentTS = time.Now()
+		}
+	}
+}
+
+func (r *Analytics) flush(records [][]byte) {
+	if len(records) == 0 {
+		return
+	}
+
+	if err := r.store.AppendToSetPipelined(context.Background(), AnalyticsKeyName, records); err != nil {
+		log.Errorf("Failed to write %d analytics records to storage: %s", len(records), err.Error())
+		atomic.AddUint64(&r.failed, uint64(len(records)))
+		return
+	}
+
+	atomic.AddUint64(&r.written, uint64(len(records)))
+}
@@ -0,0 +1,89 @@
+package analytics
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/changaolee/skeleton/internal/authzserver/options"
+)
+
+type fakeHandler struct {
+	mu     sync.Mutex
+	values map[string][][]byte
+}
+
+func (h *fakeHandler) AppendToSetPipelined(ctx context.Context, key string, values [][]byte) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.values == nil {
+		h.values = map[string][][]byte{}
+	}
+	for _, v := range values {
+		h.values[key] = append(h.values[key], append([]byte(nil), v...))
+	}
+
+	return nil
+}
+
+func newTestOptions() *options.AnalyticsOptions {
+	opts := options.NewAnalyticsOptions()
+	opts.PoolSize = 2
+	opts.RecordsBufferSize = 10
+	opts.FlushInterval = time.Hour
+
+	return opts
+}
+
+func TestAnalyticsFlushOnStop(t *testing.T) {
+	handler := &fakeHandler{}
+	a := NewAnalytics(newTestOptions(), handler)
+	a.Start()
+
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		_ = a.RecordHit(&Record{TimeStamp: now.Unix(), Username: "foo", Effect: "allow"})
+	}
+	_ = a.RecordHit(&Record{TimeStamp: now.Unix(), ExpireAt: now.Add(-time.Second)})
+	a.Stop()
+
+	records := handler.values[AnalyticsKeyName]
+	if len(records) != 5 {
+		t.Fatalf("Expected 5 records written, got %d", len(records))
+	}
+
+	var r Record
+	if err := a.serializer.Decode(records[0], &r); err != nil {
+		t.Fatalf("Unexpected decode error: %v", err)
+	}
+	if r.Username != "foo" || r.ExpireAt.IsZero() {
+		t.Errorf("Unexpected record: %+v", r)
+	}
+
+	stats := a.Stats()
+	if stats.Written != 5 || stats.Expired != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	// Stop 之后写入的审计日志会被丢弃
+	_ = a.RecordHit(&Record{TimeStamp: now.Unix()})
+	if a.Stats().Dropped != 1 {
+		t.Errorf("Expected 1 record dropped after stop, got %d", a.Stats().Dropped)
+	}
+}
+
+func TestAnalyticsDropOnFull(t *testing.T) {
+	a := NewAnalytics(newTestOptions(), &fakeHandler{})
+
+	// 未启动 worker，缓冲区写满后丢弃
+	for i := 0; i < 15; i++ {
+		_ = a.RecordHit(&Record{TimeStamp: time.Now().Unix()})
+	}
+
+	stats := a.Stats()
+	if stats.Recorded != 10 || stats.Dropped != 5 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
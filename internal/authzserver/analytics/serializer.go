@@ -0,0 +1,47 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package analytics
+
+import (
+	"encoding/json"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Serializer 定义了授权审计日志的序列化接口.
+type Serializer interface {
+	Encode(record *Record) ([]byte, error)
+	Decode(data []byte, record *Record) error
+}
+
+// NewSerializer 根据名称创建序列化器，默认使用 msgpack.
+func NewSerializer(name string) Serializer {
+	if name == "json" {
+		return &jsonSerializer{}
+	}
+
+	return &msgpackSerializer{}
+}
+
+type msgpackSerializer struct{}
+
+func (s *msgpackSerializer) Encode(record *Record) ([]byte, error) {
+	return msgpack.Marshal(record)
+}
+
+func (s *msgpackSerializer) Decode(data []byte, record *Record) error {
+	return msgpack.Unmarshal(data, record)
+}
+
+type jsonSerializer struct{}
+
+func (s *jsonSerializer) Encode(record *Record) ([]byte, error) {
+	return json.Marshal(record)
+}
+
+func (s *jsonSerializer) Decode(data []byte, record *Record) error {
+	return json.Unmarshal(data, record)
+}
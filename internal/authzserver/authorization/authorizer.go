@@ -15,6 +15,7 @@ import (
 	"github.com/ory/ladon"
 
 	"github.com/changaolee/skeleton/internal/authzserver/analytics"
+	"github.com/changaolee/skeleton/internal/authzserver/metrics"
 
 	"github.com/changaolee/skeleton/pkg/log"
 )
@@ -45,12 +46,16 @@ func (a *Authorizer) Authorize(request *ladon.Request) *response.AuthzResponse {
 	log.Debugw("authorize request", "request", request)
 
 	if err := a.warden.IsAllowed(request); err != nil {
+		metrics.AuthzDecisions.WithLabelValues(metrics.DecisionDeny).Inc()
+
 		return &response.AuthzResponse{
 			Denied: true,
 			Reason: err.Error(),
 		}
 	}
 
+	metrics.AuthzDecisions.WithLabelValues(metrics.DecisionAllow).Inc()
+
 	return &response.AuthzResponse{
 		Allowed: true,
 	}
@@ -109,8 +114,7 @@ func (a *client) LogRejectedAccessRequest(r *ladon.Request, p ladon.Policies, d
 		Deciders:   dstring,
 	}
 
-	// todo: local cache + redis
-	log.Infof("Log rejected access request: %+v", record)
+	recordAccessRequest(&record)
 }
 
 func (a *client) LogGrantedAccessRequest(r *ladon.Request, p ladon.Policies, d ladon.Policies) {
@@ -126,8 +130,17 @@ func (a *client) LogGrantedAccessRequest(r *ladon.Request, p ladon.Policies, d l
 		Deciders:   dstring,
 	}
 
-	// todo: local cache + redis
-	log.Infof("Log granted access request: %+v", record)
+	recordAccessRequest(&record)
+}
+
+// recordAccessRequest 将授权审计日志写入 analytics，未启用 analytics 时仅打印日志.
+func recordAccessRequest(record *analytics.Record) {
+	if a := analytics.GetAnalytics(); a != nil {
+		_ = a.RecordHit(record)
+		return
+	}
+
+	log.Infof("Log %s access request: %+v", record.Effect, record)
 }
 
 func joinPoliciesNames(policies ladon.Policies) string {
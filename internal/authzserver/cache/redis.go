@@ -54,6 +54,11 @@ func GetRedisInstance(opts *genoptions.RedisOptions) (*RedisCache, error) {
 	return redisIns, nil
 }
 
+// Ping 检查 Redis 连接是否可用.
+func (r *RedisCache) Ping(ctx context.Context) error {
+	return r.rd.Ping(ctx).Err()
+}
+
 func (r *RedisCache) StartPubSubHandler(ctx context.Context, channel string, callback func(interface{})) error {
 	subscriber := r.rd.Subscribe(ctx, channel)
 	defer subscriber.Close()
@@ -71,3 +76,21 @@ func (r *RedisCache) StartPubSubHandler(ctx context.Context, channel string, cal
 		callback(msg)
 	}
 }
+
+// AppendToSetPipelined 通过 pipeline 将多个值批量追加到 Redis 列表中.
+func (r *RedisCache) AppendToSetPipelined(ctx context.Context, key string, values [][]byte) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	pipe := r.rd.Pipeline()
+	for _, val := range values {
+		pipe.RPush(ctx, key, val)
+	}
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to append values to redis list %s: %w", key, err)
+	}
+
+	return nil
+}
@@ -6,14 +6,33 @@
 package authzserver
 
 import (
+	"github.com/gin-gonic/gin"
+
 	"github.com/changaolee/skeleton/internal/authzserver/load"
+	"github.com/changaolee/skeleton/internal/authzserver/options"
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/internal/pkg/jwks"
 	"github.com/changaolee/skeleton/internal/pkg/middleware"
 	"github.com/changaolee/skeleton/internal/pkg/middleware/auth"
+	"github.com/changaolee/skeleton/internal/pkg/revocation"
 	"github.com/changaolee/skeleton/pkg/errors"
 )
 
-func newCacheAuth() middleware.AuthStrategy {
-	return auth.NewCacheStrategy(getSecretFunc())
+func newCacheAuth(opts *options.JwksOptions) middleware.AuthStrategy {
+	var issued *auth.IssuedTokens
+	// 配置了 apiserver 的 JWKS 地址时，同时接受 apiserver 使用非对称密钥签发的 token，
+	// 并通过与 apiserver 共享的吊销列表检查 token 是否已被吊销
+	if opts.URL != "" {
+		issued = &auth.IssuedTokens{
+			Keyfunc:  jwks.NewRemoteKeySet(opts.URL, opts.RefreshInterval).Keyfunc,
+			Audience: opts.Audience,
+			Issuer:   opts.Issuer,
+			Revoker:  revocation.Get(),
+		}
+	}
+
+	return auth.NewCacheStrategy(getSecretFunc(), issued)
 }
 
 func getSecretFunc() func(string) (auth.Secret, error) {
@@ -36,3 +55,22 @@ func getSecretFunc() func(string) (auth.Secret, error) {
 		}, nil
 	}
 }
+
+// adminOnly 只允许 admins 中的已认证用户访问，需要在认证中间件之后执行.
+func adminOnly(admins []string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(admins))
+	for _, name := range admins {
+		allowed[name] = true
+	}
+
+	return func(c *gin.Context) {
+		if !allowed[c.GetString(middleware.UsernameKey)] {
+			core.WriteResponse(c, errors.WithCode(code.ErrPermissionDenied, "administrator permission required"), nil)
+			c.Abort()
+
+			return
+		}
+
+		c.Next()
+	}
+}
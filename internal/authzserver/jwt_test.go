@@ -0,0 +1,35 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package authzserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
+)
+
+func TestAdminOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	g := gin.New()
+	g.PUT("/debug/pprof/rates", func(c *gin.Context) {
+		c.Set(middleware.UsernameKey, c.GetHeader("X-User"))
+	}, adminOnly([]string{"ops"}), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for user, want := range map[string]int{"ops": http.StatusOK, "alice": http.StatusForbidden, "": http.StatusForbidden} {
+		req := httptest.NewRequest(http.MethodPut, "/debug/pprof/rates", nil)
+		req.Header.Set("X-User", user)
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("user %q: status = %d, want %d", user, w.Code, want)
+		}
+	}
+}
@@ -6,7 +6,9 @@
 package load
 
 import (
+	"context"
 	"sync"
+	"time"
 
 	"github.com/dgraph-io/ristretto"
 	"github.com/ory/ladon"
@@ -23,6 +25,10 @@ type Cache struct {
 	s        store.IStore
 	secrets  *ristretto.Cache
 	policies *ristretto.Cache
+
+	// lastReloadAt 为最近一次成功重载的时间，lastReloadErr 为最近一次重载的错误
+	lastReloadAt  time.Time
+	lastReloadErr error
 }
 
 // 需要实现的接口.
@@ -103,11 +109,41 @@ func (c *Cache) GetPolicy(key string) ([]*ladon.DefaultPolicy, error) {
 	return value.([]*ladon.DefaultPolicy), nil
 }
 
+// Check 检查缓存是否可用：从未成功重载或最近一次重载失败时返回错误，并报告距最近一次成功重载的时间.
+func (c *Cache) Check(ctx context.Context) error {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.lastReloadAt.IsZero() {
+		if c.lastReloadErr != nil {
+			return errors.Wrap(c.lastReloadErr, "secrets and policies have never been loaded")
+		}
+		return errors.New("secrets and policies have never been loaded")
+	}
+
+	if c.lastReloadErr != nil {
+		return errors.Wrapf(c.lastReloadErr, "last reload failed, %s since last successful reload",
+			time.Since(c.lastReloadAt).Round(time.Second))
+	}
+
+	return nil
+}
+
 // Reload 实现 Loader 接口的重载方法，用于重载 secrets 和 policies.
 func (c *Cache) Reload() error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
+	c.lastReloadErr = c.reload()
+	if c.lastReloadErr == nil {
+		c.lastReloadAt = time.Now()
+	}
+
+	return c.lastReloadErr
+}
+
+func (c *Cache) reload() error {
+
 	// 重载 secrets
 	// todo: 优化为分页加载方式
 	secrets, err := c.s.Secrets().List()
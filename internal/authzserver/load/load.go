@@ -11,6 +11,8 @@ import (
 	"time"
 
 	"github.com/changaolee/skeleton/internal/authzserver/cache"
+	"github.com/changaolee/skeleton/internal/authzserver/metrics"
+	"github.com/changaolee/skeleton/internal/pkg/notification"
 	"github.com/changaolee/skeleton/pkg/log"
 )
 
@@ -21,17 +23,32 @@ type Loader interface {
 
 // Load 用于重载 secrets 和 policies.
 type Load struct {
-	ctx    context.Context
-	lock   *sync.RWMutex
-	loader Loader
+	ctx      context.Context
+	lock     *sync.RWMutex
+	loader   Loader
+	verifier *notification.Verifier
+	interval time.Duration
+	// intervalCh 用于在运行时修改检查间隔
+	intervalCh chan time.Duration
 }
 
-// NewLoader 创建一个 Load.
-func NewLoader(ctx context.Context, loader Loader) *Load {
+// NewLoader 创建一个 Load，verifier 用于校验收到的变更通知，interval 为检查是否需要重载的间隔.
+func NewLoader(ctx context.Context, loader Loader, verifier *notification.Verifier, interval time.Duration) *Load {
 	return &Load{
-		ctx:    ctx,
-		lock:   new(sync.RWMutex),
-		loader: loader,
+		ctx:        ctx,
+		lock:       new(sync.RWMutex),
+		loader:     loader,
+		verifier:   verifier,
+		interval:   interval,
+		intervalCh: make(chan time.Duration),
+	}
+}
+
+// SetReloadInterval 修改检查是否需要重载的间隔，在下一次检查时生效.
+func (l *Load) SetReloadInterval(interval time.Duration) {
+	select {
+	case l.intervalCh <- interval:
+	case <-l.ctx.Done():
 	}
 }
 
@@ -58,9 +75,14 @@ func (l *Load) DoReload() {
 	l.lock.Lock()
 	defer l.lock.Unlock()
 
+	start := time.Now()
 	if err := l.loader.Reload(); err != nil {
+		metrics.CacheReloadDuration.WithLabelValues("failure").Observe(time.Since(start).Seconds())
 		log.Errorf("Fail to refresh target storage: %s", err.Error())
+
+		return
 	}
+	metrics.CacheReloadDuration.WithLabelValues("success").Observe(time.Since(start).Seconds())
 
 	log.Debugw("Refresh target storage success")
 }
@@ -72,8 +94,8 @@ func (l *Load) startPubSubLoop() {
 		log.Errorf("Connection to redis failed")
 		return
 	}
-	_ = cacheIns.StartPubSubHandler(l.ctx, RedisPubSubChannel, func(v interface{}) {
-		handleRedisEvent(v, nil, nil)
+	_ = cacheIns.StartPubSubHandler(l.ctx, notification.RedisPubSubChannel, func(v interface{}) {
+		handleRedisEvent(v, l.verifier, nil, nil)
 	})
 }
 
@@ -86,19 +108,25 @@ func (l *Load) reloadQueueLoop() {
 		case fn := <-reloadQueue:
 			requeueLock.Lock()
 			requeue = append(requeue, fn)
+			metrics.LoaderQueueDepth.Set(float64(len(requeue)))
 			requeueLock.Unlock()
 			log.Infow("Reload queued")
 		}
 	}
 }
 
-// reloadLoop 每秒检查 requeue 是否为空，不空则重载数据.
+// reloadLoop 定时检查 requeue 是否为空，不空则重载数据.
 func (l *Load) reloadLoop() {
-	ticker := time.NewTicker(1 * time.Second)
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+
 	for {
 		select {
 		case <-l.ctx.Done():
 			return
+		case interval := <-l.intervalCh:
+			ticker.Reset(interval)
+			log.Infof("Reload: check interval changed to %s", interval)
 		case <-ticker.C:
 			callbacks, ok := shouldReload()
 			if !ok {
@@ -125,6 +153,7 @@ func shouldReload() ([]func(), bool) {
 	}
 	callbacks := requeue
 	requeue = []func(){}
+	metrics.LoaderQueueDepth.Set(0)
 
 	return callbacks, true
 }
@@ -6,57 +6,74 @@
 package load
 
 import (
-	"crypto"
-	"crypto/sha256"
-	"encoding/hex"
 	"encoding/json"
+	"errors"
 
 	"github.com/redis/go-redis/v9"
 
+	"github.com/changaolee/skeleton/internal/authzserver/metrics"
+	"github.com/changaolee/skeleton/internal/pkg/notification"
 	"github.com/changaolee/skeleton/pkg/log"
 )
 
-// NotificationCommand 定义一个新的通知类型.
-type NotificationCommand string
-
-// 定义 Redis 订阅相关键和事件.
+// 定义通知被拒绝的原因.
 const (
-	RedisPubSubChannel                      = "skt.notifications"
-	NoticePolicyChanged NotificationCommand = "PolicyChanged"
-	NoticeSecretChanged NotificationCommand = "SecretChanged"
+	RejectReasonMalformed         = "malformed"
+	RejectReasonUnsigned          = "unsigned"
+	RejectReasonUnsupportedAlgo   = "unsupported_algorithm"
+	RejectReasonSignatureMismatch = "signature_mismatch"
+	RejectReasonExpired           = "expired"
+	RejectReasonReplayed          = "replayed"
 )
 
-// Notification 是一个用于发布和订阅消息编码类型.
-type Notification struct {
-	Command       NotificationCommand `json:"command"`
-	Payload       string              `json:"payload"`
-	Signature     string              `json:"signature"`
-	SignatureAlgo crypto.Hash         `json:"algorithm"`
+func rejectNotification(reason string, err error) {
+	metrics.NotificationsRejected.WithLabelValues(reason).Inc()
+
+	log.Warnw("Reject redis notification", "reason", reason, "error", err)
 }
 
-// Sign 使用 SHA256 算法进行签名.
-func (n *Notification) Sign() {
-	n.SignatureAlgo = crypto.SHA256
-	hash := sha256.Sum256([]byte(string(n.Command) + n.Payload))
-	n.Signature = hex.EncodeToString(hash[:])
+func rejectReason(err error) string {
+	switch {
+	case errors.Is(err, notification.ErrUnsigned):
+		return RejectReasonUnsigned
+	case errors.Is(err, notification.ErrUnsupportedAlgo):
+		return RejectReasonUnsupportedAlgo
+	case errors.Is(err, notification.ErrExpired):
+		return RejectReasonExpired
+	case errors.Is(err, notification.ErrReplayed):
+		return RejectReasonReplayed
+	default:
+		return RejectReasonSignatureMismatch
+	}
 }
 
-func handleRedisEvent(v interface{}, handled func(NotificationCommand), reloaded func()) {
+func handleRedisEvent(
+	v interface{},
+	verifier *notification.Verifier,
+	handled func(notification.Command),
+	reloaded func(),
+) {
 	message, ok := v.(*redis.Message)
 	if !ok {
 		return
 	}
 
-	notif := Notification{}
+	notif := notification.Notification{}
 	if err := json.Unmarshal([]byte(message.Payload), &notif); err != nil {
-		log.Errorf("Unmarshalling message body failed, malformed: ", err)
+		rejectNotification(RejectReasonMalformed, err)
+
+		return
+	}
+
+	if err := verifier.Verify(&notif); err != nil {
+		rejectNotification(rejectReason(err), err)
 
 		return
 	}
 	log.Infow("Receive redis message", "command", notif.Command, "payload", message.Payload)
 
 	switch notif.Command {
-	case NoticePolicyChanged, NoticeSecretChanged:
+	case notification.NoticePolicyChanged, notification.NoticeSecretChanged:
 		log.Infow("Reloading secrets and policies")
 		reloadQueue <- reloaded
 	default:
@@ -0,0 +1,53 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+// Package metrics 定义了 skt-authz-server 的业务指标.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// 定义授权结果.
+const (
+	DecisionAllow = "allow"
+	DecisionDeny  = "deny"
+)
+
+var (
+	// AuthzDecisions 按授权结果统计授权请求数.
+	AuthzDecisions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "skt",
+		Subsystem: "authz",
+		Name:      "decisions_total",
+		Help:      "Total number of authorization decisions by result (allow or deny).",
+	}, []string{"decision"})
+
+	// CacheReloadDuration 记录 secrets 和 policies 缓存重载耗时.
+	CacheReloadDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "skt",
+		Subsystem: "authz",
+		Name:      "cache_reload_duration_seconds",
+		Help:      "Duration of secrets and policies cache reloads in seconds by result.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"result"})
+
+	// LoaderQueueDepth 记录等待下一次重载的请求数.
+	LoaderQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "skt",
+		Subsystem: "authz",
+		Name:      "loader_queue_depth",
+		Help:      "Number of queued reload requests waiting for the next reload cycle.",
+	})
+
+	// NotificationsRejected 按原因统计被拒绝的变更通知数.
+	NotificationsRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "skt",
+		Subsystem: "authz",
+		Name:      "notifications_rejected_total",
+		Help:      "Total number of rejected change notifications by reason.",
+	}, []string{"reason"})
+)
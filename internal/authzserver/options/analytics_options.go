@@ -0,0 +1,75 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// AnalyticsOptions 定义了授权审计日志相关的选项.
+type AnalyticsOptions struct {
+	Enable                bool          `json:"enable"                  mapstructure:"enable"`
+	PoolSize              int           `json:"pool-size"               mapstructure:"pool-size"`
+	RecordsBufferSize     int           `json:"records-buffer-size"     mapstructure:"records-buffer-size"`
+	FlushInterval         time.Duration `json:"flush-interval"          mapstructure:"flush-interval"`
+	StorageExpirationTime time.Duration `json:"storage-expiration-time" mapstructure:"storage-expiration-time"`
+	Serializer            string        `json:"serializer"              mapstructure:"serializer"`
+}
+
+// NewAnalyticsOptions 创建一个默认值的授权审计日志选项实例.
+func NewAnalyticsOptions() *AnalyticsOptions {
+	return &AnalyticsOptions{
+		Enable:                true,
+		PoolSize:              50,
+		RecordsBufferSize:     2000,
+		FlushInterval:         200 * time.Millisecond,
+		StorageExpirationTime: 24 * time.Hour,
+		Serializer:            "msgpack",
+	}
+}
+
+// Validate 验证授权审计日志选项.
+func (o *AnalyticsOptions) Validate() []error {
+	var errs []error
+
+	if !o.Enable {
+		return errs
+	}
+
+	if o.PoolSize <= 0 {
+		errs = append(errs, fmt.Errorf("--analytics.pool-size must be greater than 0"))
+	}
+	if o.RecordsBufferSize <= 0 {
+		errs = append(errs, fmt.Errorf("--analytics.records-buffer-size must be greater than 0"))
+	}
+	if o.FlushInterval <= 0 {
+		errs = append(errs, fmt.Errorf("--analytics.flush-interval must be greater than 0"))
+	}
+	if o.Serializer != "msgpack" && o.Serializer != "json" {
+		errs = append(errs, fmt.Errorf("--analytics.serializer must be one of msgpack, json"))
+	}
+
+	return errs
+}
+
+// AddFlags 向指定 FlagSet 中添加授权审计日志选项相关标志.
+func (o *AnalyticsOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&o.Enable, "analytics.enable", o.Enable,
+		"This sets the skt-authz-server to record analytics data.")
+	fs.IntVar(&o.PoolSize, "analytics.pool-size", o.PoolSize,
+		"Specify number of pool workers that write analytics records to redis.")
+	fs.IntVar(&o.RecordsBufferSize, "analytics.records-buffer-size", o.RecordsBufferSize,
+		"Specify size of the buffered channel, records are dropped when the buffer is full.")
+	fs.DurationVar(&o.FlushInterval, "analytics.flush-interval", o.FlushInterval,
+		"Specify the interval at which workers flush buffered records to redis.")
+	fs.DurationVar(&o.StorageExpirationTime, "analytics.storage-expiration-time", o.StorageExpirationTime,
+		"Specify how long an analytics record is kept before it expires, 0 means never expire.")
+	fs.StringVar(&o.Serializer, "analytics.serializer", o.Serializer,
+		"Specify the serializer of analytics records in redis, one of msgpack, json.")
+}
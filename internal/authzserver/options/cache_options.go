@@ -0,0 +1,43 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// CacheOptions 定义了 secrets 和 policies 缓存相关的选项.
+type CacheOptions struct {
+	ReloadInterval time.Duration `json:"reload-interval" mapstructure:"reload-interval"`
+}
+
+// NewCacheOptions 创建一个默认值的缓存选项实例.
+func NewCacheOptions() *CacheOptions {
+	return &CacheOptions{
+		ReloadInterval: 1 * time.Second,
+	}
+}
+
+// Validate 验证缓存选项.
+func (o *CacheOptions) Validate() []error {
+	var errs []error
+
+	if o.ReloadInterval <= 0 {
+		errs = append(errs, fmt.Errorf("--cache.reload-interval must be greater than 0"))
+	}
+
+	return errs
+}
+
+// AddFlags 向指定 FlagSet 中添加缓存选项相关标志.
+func (o *CacheOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&o.ReloadInterval, "cache.reload-interval", o.ReloadInterval, ""+
+		"Specify the interval at which queued change notifications are checked "+
+		"and secrets and policies are reloaded from the rpc server.")
+}
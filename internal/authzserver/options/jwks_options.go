@@ -0,0 +1,64 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// JwksOptions 定义了获取 apiserver 公钥相关的选项，用于验证 apiserver 使用非对称密钥签发的 token.
+type JwksOptions struct {
+	URL             string        `json:"url"              mapstructure:"url"`
+	RefreshInterval time.Duration `json:"refresh-interval" mapstructure:"refresh-interval"`
+	Audience        string        `json:"audience"         mapstructure:"audience"`
+	Issuer          string        `json:"issuer"           mapstructure:"issuer"`
+}
+
+// NewJwksOptions 创建一个默认值的 JWKS 选项实例.
+func NewJwksOptions() *JwksOptions {
+	return &JwksOptions{
+		URL:             "",
+		RefreshInterval: 5 * time.Minute,
+		Audience:        "skt.api.changaolee.com",
+		Issuer:          "skt-apiserver",
+	}
+}
+
+// Validate 验证 JWKS 选项.
+func (o *JwksOptions) Validate() []error {
+	var errs []error
+
+	if o.URL != "" {
+		if u, err := url.Parse(o.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, fmt.Errorf("--jwks.url must be an absolute url, got %q", o.URL))
+		}
+		if o.Audience == "" || o.Issuer == "" {
+			errs = append(errs, fmt.Errorf("--jwks.audience and --jwks.issuer cannot be empty when --jwks.url is set"))
+		}
+	}
+	if o.RefreshInterval <= 0 {
+		errs = append(errs, fmt.Errorf("--jwks.refresh-interval must be greater than 0"))
+	}
+
+	return errs
+}
+
+// AddFlags 向指定 FlagSet 中添加 JWKS 选项相关标志.
+func (o *JwksOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.URL, "jwks.url", o.URL, ""+
+		"The JWKS endpoint of apiserver, e.g. https://127.0.0.1:8443/.well-known/jwks.json. "+
+		"If set, tokens signed by apiserver with asymmetric keys are accepted. Empty means disable.")
+	fs.DurationVar(&o.RefreshInterval, "jwks.refresh-interval", o.RefreshInterval, ""+
+		"The interval at which the public keys are fetched again from jwks.url.")
+	fs.StringVar(&o.Audience, "jwks.audience", o.Audience, ""+
+		"The aud claim that tokens signed by apiserver with asymmetric keys must contain.")
+	fs.StringVar(&o.Issuer, "jwks.issuer", o.Issuer, ""+
+		"The iss claim that tokens signed by apiserver with asymmetric keys must contain.")
+}
@@ -6,9 +6,12 @@
 package options
 
 import (
+	"fmt"
+
 	"github.com/spf13/pflag"
 
 	genoptions "github.com/changaolee/skeleton/internal/pkg/options"
+	genericapiserver "github.com/changaolee/skeleton/internal/pkg/server"
 	"github.com/changaolee/skeleton/pkg/app"
 	"github.com/changaolee/skeleton/pkg/log"
 )
@@ -17,9 +20,17 @@ type Options struct {
 	RPCServer               string                             `json:"rpcserver"      mapstructure:"rpcserver"`
 	ClientCA                string                             `json:"client-ca-file" mapstructure:"client-ca-file"`
 	GenericServerRunOptions *genoptions.ServerRunOptions       `json:"server"         mapstructure:"server"`
+	FeatureOptions          *genoptions.FeatureOptions         `json:"feature"        mapstructure:"feature"`
+	CORSOptions             *genoptions.CORSOptions            `json:"cors"           mapstructure:"cors"`
+	RateLimitOptions        *genoptions.RateLimitOptions       `json:"ratelimit"      mapstructure:"ratelimit"`
 	InsecureServing         *genoptions.InsecureServingOptions `json:"insecure"       mapstructure:"insecure"`
 	SecureServing           *genoptions.SecureServingOptions   `json:"secure"         mapstructure:"secure"`
 	RedisOptions            *genoptions.RedisOptions           `json:"redis"          mapstructure:"redis"`
+	NotificationOptions     *genoptions.NotificationOptions    `json:"notification"   mapstructure:"notification"`
+	AnalyticsOptions        *AnalyticsOptions                  `json:"analytics"      mapstructure:"analytics"`
+	CacheOptions            *CacheOptions                      `json:"cache"          mapstructure:"cache"`
+	JwksOptions             *JwksOptions                       `json:"jwks"           mapstructure:"jwks"`
+	ProfilingOptions        *ProfilingOptions                  `json:"profiling"      mapstructure:"profiling"`
 	Log                     *log.Options                       `json:"log"            mapstructure:"log"`
 }
 
@@ -29,9 +40,17 @@ func NewOptions() *Options {
 		RPCServer:               "127.0.0.1:8081",
 		ClientCA:                "",
 		GenericServerRunOptions: genoptions.NewServerRunOptions(),
+		FeatureOptions:          genoptions.NewFeatureOptions(),
+		CORSOptions:             genoptions.NewCORSOptions(),
+		RateLimitOptions:        genoptions.NewRateLimitOptions(),
 		InsecureServing:         genoptions.NewInsecureServingOptions(),
 		SecureServing:           genoptions.NewSecureServingOptions(),
 		RedisOptions:            genoptions.NewRedisOptions(),
+		NotificationOptions:     genoptions.NewNotificationOptions(),
+		AnalyticsOptions:        NewAnalyticsOptions(),
+		CacheOptions:            NewCacheOptions(),
+		JwksOptions:             NewJwksOptions(),
+		ProfilingOptions:        NewProfilingOptions(),
 		Log:                     log.NewOptions(),
 	}
 	return &o
@@ -39,9 +58,17 @@ func NewOptions() *Options {
 
 func (o *Options) Flags() (fss app.NamedFlagSets) {
 	o.GenericServerRunOptions.AddFlags(fss.FlagSet("generic"))
+	o.FeatureOptions.AddFlags(fss.FlagSet("features"))
+	o.CORSOptions.AddFlags(fss.FlagSet("cors"))
+	o.RateLimitOptions.AddFlags(fss.FlagSet("ratelimit"))
 	o.InsecureServing.AddFlags(fss.FlagSet("insecure serving"))
 	o.SecureServing.AddFlags(fss.FlagSet("secure serving"))
 	o.RedisOptions.AddFlags(fss.FlagSet("redis"))
+	o.NotificationOptions.AddFlags(fss.FlagSet("notification"))
+	o.AnalyticsOptions.AddFlags(fss.FlagSet("analytics"))
+	o.CacheOptions.AddFlags(fss.FlagSet("cache"))
+	o.JwksOptions.AddFlags(fss.FlagSet("jwks"))
+	o.ProfilingOptions.AddFlags(fss.FlagSet("profiling"))
 	o.Log.AddFlags(fss.FlagSet("log"))
 
 	o.addMiscFlags(fss.FlagSet("misc"))
@@ -61,7 +88,20 @@ func (o *Options) addMiscFlags(fs *pflag.FlagSet) {
 func (o *Options) Validate() []error {
 	var errs []error
 
+	errs = append(errs, o.GenericServerRunOptions.Validate()...)
+	errs = append(errs, o.FeatureOptions.Validate()...)
+	errs = append(errs, o.CORSOptions.Validate()...)
+	errs = append(errs, o.RateLimitOptions.Validate()...)
 	errs = append(errs, o.RedisOptions.Validate()...)
+	errs = append(errs, o.NotificationOptions.Validate()...)
+	errs = append(errs, o.AnalyticsOptions.Validate()...)
+	errs = append(errs, o.CacheOptions.Validate()...)
+	errs = append(errs, o.JwksOptions.Validate()...)
+	errs = append(errs, o.ProfilingOptions.Validate()...)
+	if o.FeatureOptions.EnableProfiling && o.FeatureOptions.ProfilingAccess == genericapiserver.ProfilingAccessAdmin &&
+		len(o.ProfilingOptions.Admins) == 0 {
+		errs = append(errs, fmt.Errorf("--profiling.admins must be set when --feature.profiling-access is admin"))
+	}
 	errs = append(errs, o.Log.Validate()...)
 
 	return errs
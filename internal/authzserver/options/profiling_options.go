@@ -0,0 +1,35 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"github.com/spf13/pflag"
+)
+
+// ProfilingOptions 定义了可以访问 /debug/pprof 的管理员，authz-server 没有用户信息，需要通过配置指定.
+type ProfilingOptions struct {
+	Admins []string `json:"admins" mapstructure:"admins"`
+}
+
+// NewProfilingOptions 创建一个默认值的性能分析选项实例，默认没有管理员.
+func NewProfilingOptions() *ProfilingOptions {
+	return &ProfilingOptions{
+		Admins: []string{},
+	}
+}
+
+// Validate 验证性能分析选项.
+func (o *ProfilingOptions) Validate() []error {
+	return nil
+}
+
+// AddFlags 向指定 FlagSet 中添加性能分析选项相关标志.
+func (o *ProfilingOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringSliceVar(&o.Admins, "profiling.admins", o.Admins, ""+
+		"Usernames allowed to access /debug/pprof/ when --feature.profiling-access is admin, "+
+		"and to change the profiling rates in every access mode. Requests are authenticated with the secrets "+
+		"of the users, or tokens issued by skt-apiserver when --jwks.url is set.")
+}
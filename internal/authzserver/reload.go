@@ -0,0 +1,72 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package authzserver
+
+import (
+	"strings"
+
+	"github.com/changaolee/skeleton/internal/authzserver/options"
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
+	genoptions "github.com/changaolee/skeleton/internal/pkg/options"
+	"github.com/changaolee/skeleton/pkg/app"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+	"github.com/changaolee/skeleton/pkg/reload"
+)
+
+// addReloadCallbacks 注册配置热加载回调.
+func (s *authzServer) addReloadCallbacks() {
+	s.gr.SetErrorHandler(reload.ErrorFunc(func(err error) {
+		log.Errorf("Reload config failed, keep running with the current config: %s", err.Error())
+	}))
+	s.gr.AddCallback(reload.CallbackFunc(s.reloadConfig))
+}
+
+// reloadConfig 重新读取并校验配置，只应用可以在运行时修改的配置项，其他配置项的变更需要重启服务才能生效.
+func (s *authzServer) reloadConfig(manager string) error {
+	opts := options.NewOptions()
+	if err := app.ReloadOptions(opts); err != nil {
+		return errors.Wrap(err, "invalid config")
+	}
+
+	running := s.cfg.Options
+	applied, rejected := genoptions.ApplyChangedFields(running, opts, map[string]func(){
+		"log.level": func() {
+			running.Log.Level = opts.Log.Level
+			log.Init(running.Log)
+		},
+		"log.format": func() {
+			running.Log.Format = opts.Log.Format
+			log.Init(running.Log)
+		},
+		"server.middlewares": func() {
+			running.GenericServerRunOptions.Middlewares = opts.GenericServerRunOptions.Middlewares
+			s.genericAPIServer.SetMiddlewares(running.GenericServerRunOptions.Middlewares)
+		},
+		"cors": func() {
+			running.CORSOptions = opts.CORSOptions
+			_ = middleware.SetCorsConfig(running.CORSOptions.CorsConfig())
+		},
+		"cache.reload-interval": func() {
+			running.CacheOptions.ReloadInterval = opts.CacheOptions.ReloadInterval
+			if s.loader != nil {
+				s.loader.SetReloadInterval(running.CacheOptions.ReloadInterval)
+			}
+		},
+	})
+
+	if len(rejected) > 0 {
+		log.Warnf("Reload config (triggered by %s): changes to %s require a restart and are ignored",
+			manager, strings.Join(rejected, ", "))
+	}
+	if len(applied) > 0 {
+		log.Infof("Reload config (triggered by %s): applied changes to %s", manager, strings.Join(applied, ", "))
+	} else {
+		log.Infof("Reload config (triggered by %s): nothing to apply", manager)
+	}
+
+	return nil
+}
@@ -8,24 +8,26 @@ package authzserver
 import (
 	"github.com/gin-gonic/gin"
 
+	"github.com/changaolee/skeleton/internal/authzserver/config"
 	"github.com/changaolee/skeleton/internal/authzserver/controller/v1/authorize"
 	"github.com/changaolee/skeleton/internal/authzserver/load"
 	"github.com/changaolee/skeleton/internal/pkg/code"
 	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
 	"github.com/changaolee/skeleton/pkg/errors"
 	"github.com/changaolee/skeleton/pkg/log"
 )
 
-func initRouter(g *gin.Engine) {
+func initRouter(g *gin.Engine, cfg *config.Config) {
 	installMiddleware(g)
-	installController(g)
+	installController(g, cfg)
 }
 
 func installMiddleware(g *gin.Engine) {
 }
 
-func installController(g *gin.Engine) *gin.Engine {
-	auth := newCacheAuth()
+func installController(g *gin.Engine, cfg *config.Config) *gin.Engine {
+	auth := newCacheAuth(cfg.JwksOptions)
 	g.NoRoute(auth.AuthFunc(), func(c *gin.Context) {
 		core.WriteResponse(c, errors.WithCode(code.ErrPageNotFound, "page not found."), nil)
 	})
@@ -35,7 +37,7 @@ func installController(g *gin.Engine) *gin.Engine {
 		log.Panicf("Get nil cache instance")
 	}
 
-	v1 := g.Group("/v1", auth.AuthFunc())
+	v1 := g.Group("/v1", auth.AuthFunc(), middleware.AuthenticatedRateLimit())
 	{
 		authzController := authorize.NewAuthzController(cacheIns)
 
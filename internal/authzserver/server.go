@@ -7,23 +7,46 @@ package authzserver
 
 import (
 	"context"
+	"time"
 
+	"github.com/gin-gonic/gin"
+	"github.com/spf13/viper"
+
+	"github.com/changaolee/skeleton/internal/authzserver/analytics"
 	"github.com/changaolee/skeleton/internal/authzserver/cache"
 	"github.com/changaolee/skeleton/internal/authzserver/config"
 	"github.com/changaolee/skeleton/internal/authzserver/load"
+	"github.com/changaolee/skeleton/internal/authzserver/options"
 	"github.com/changaolee/skeleton/internal/authzserver/store/apiserver"
+	"github.com/changaolee/skeleton/internal/pkg/notification"
+	genoptions "github.com/changaolee/skeleton/internal/pkg/options"
+	"github.com/changaolee/skeleton/internal/pkg/revocation"
 	genericapiserver "github.com/changaolee/skeleton/internal/pkg/server"
 	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+	"github.com/changaolee/skeleton/pkg/reload"
+	"github.com/changaolee/skeleton/pkg/reload/managers/filewatch"
+	reloadsignal "github.com/changaolee/skeleton/pkg/reload/managers/posixsignal"
 	"github.com/changaolee/skeleton/pkg/shutdown"
 	"github.com/changaolee/skeleton/pkg/shutdown/managers/posixsignal"
 )
 
+// closeTimeout 为停止单个后台组件的最长等待时间.
+const closeTimeout = 5 * time.Second
+
 type authzServer struct {
+	cfg              *config.Config
 	rpcServer        string
 	clientCA         string
 	gs               *shutdown.GracefulShutdown
+	gr               *reload.GracefulReload
+	loader           *load.Load
 	genericAPIServer *genericapiserver.GenericAPIServer
 	redisCancelFunc  context.CancelFunc
+	redisCache       *cache.RedisCache
+	analyticsOptions *options.AnalyticsOptions
+	notifyOptions    *genoptions.NotificationOptions
+	shutdownTimeout  time.Duration
 }
 
 type preparedAuthzServer struct {
@@ -35,12 +58,26 @@ func createAuthzServer(cfg *config.Config) (*authzServer, error) {
 	gs := shutdown.New()
 	gs.AddManager(posixsignal.NewPosixSignalManager())
 
+	// 配置热加载组件，收到 SIGHUP 或配置文件变更时触发
+	gr := reload.New()
+	gr.AddManager(reloadsignal.NewPosixSignalManager())
+	if file := viper.ConfigFileUsed(); file != "" {
+		gr.AddManager(filewatch.NewFileWatchManager(file))
+	}
+
 	// Redis 实例
-	_, err := cache.GetRedisInstance(cfg.RedisOptions)
+	redisCache, err := cache.GetRedisInstance(cfg.RedisOptions)
 	if err != nil {
 		return nil, err
 	}
 
+	// 与 apiserver 共享的 JWT 吊销列表，authz-server 只检查 token 是否已被吊销
+	revoker, err := revocation.NewRedisRevoker(cfg.RedisOptions, 0)
+	if err != nil {
+		return nil, err
+	}
+	revocation.Set(revoker)
+
 	// APIServer
 	genericConfig, err := buildGenericConfig(cfg)
 	if err != nil {
@@ -52,10 +89,16 @@ func createAuthzServer(cfg *config.Config) (*authzServer, error) {
 	}
 
 	server := &authzServer{
+		cfg:              cfg,
 		rpcServer:        cfg.RPCServer,
 		clientCA:         cfg.ClientCA,
 		gs:               gs,
+		gr:               gr,
 		genericAPIServer: genericServer,
+		redisCache:       redisCache,
+		analyticsOptions: cfg.AnalyticsOptions,
+		notifyOptions:    cfg.NotificationOptions,
+		shutdownTimeout:  cfg.GenericServerRunOptions.ShutdownTimeout,
 	}
 
 	return server, nil
@@ -64,16 +107,45 @@ func createAuthzServer(cfg *config.Config) (*authzServer, error) {
 func (s *authzServer) PrepareRun() *preparedAuthzServer {
 	_ = s.initialize()
 
-	initRouter(s.genericAPIServer.Engine)
+	initRouter(s.genericAPIServer.Engine, s.cfg)
 
-	s.gs.AddCallback(shutdown.CallbackFunc(func(string) error {
-		s.genericAPIServer.Shutdown()
-		s.redisCancelFunc()
+	s.addShutdownCallbacks()
+	s.addReloadCallbacks()
 
+	return &preparedAuthzServer{s}
+}
+
+// addShutdownCallbacks 按顺序注册关闭回调：就绪检查失败、等待 HTTP 请求处理完成、
+// 将剩余的审计日志写入 Redis、停止 Redis 订阅和缓存重载.
+func (s *authzServer) addShutdownCallbacks() {
+	s.gs.SetTimeout(s.shutdownTimeout)
+	s.gs.SetErrorHandler(shutdown.ErrorFunc(func(err error) {
+		log.Warnf("Graceful shutdown: %s", err.Error())
+	}))
+
+	s.gs.AddPriorityCallback(shutdown.PriorityStopAccepting, 0,
+		shutdown.ContextCallbackFunc(func(ctx context.Context, _ string) error {
+			return s.genericAPIServer.PrepareShutdown(ctx)
+		}))
+
+	s.gs.AddPriorityCallback(shutdown.PriorityDrain, 0,
+		shutdown.ContextCallbackFunc(func(ctx context.Context, _ string) error {
+			return s.genericAPIServer.Shutdown(ctx)
+		}))
+
+	// HTTP 服务关闭后再停止 analytics，确保所有审计日志都被写入 Redis
+	s.gs.AddPriorityCallback(shutdown.PriorityFlush, 0, shutdown.CallbackFunc(func(string) error {
+		if a := analytics.GetAnalytics(); a != nil {
+			a.Stop()
+		}
 		return nil
 	}))
 
-	return &preparedAuthzServer{s}
+	s.gs.AddPriorityCallback(shutdown.PriorityCloseStores, closeTimeout, shutdown.CallbackFunc(func(string) error {
+		s.redisCancelFunc()
+
+		return nil
+	}))
 }
 
 func (s *authzServer) initialize() error {
@@ -89,15 +161,44 @@ func (s *authzServer) initialize() error {
 	if err != nil {
 		return errors.Wrap(err, "get cache instance failed")
 	}
-	load.NewLoader(ctx, cacheIns).Start()
+	verifier := notification.NewVerifier([]byte(s.notifyOptions.SigningKey), s.notifyOptions.MaxAge)
+	s.loader = load.NewLoader(ctx, cacheIns, verifier, s.cfg.CacheOptions.ReloadInterval)
+	s.loader.Start()
+
+	if err := s.genericAPIServer.AddHealthChecks(
+		genericapiserver.NamedCheck("redis", s.redisCache.Ping),
+		genericapiserver.NamedCheck("authz-cache", cacheIns.Check),
+	); err != nil {
+		return errors.Wrap(err, "add health checks failed")
+	}
 
-	// todo: analytics
+	// 授权审计日志
+	if s.analyticsOptions.Enable {
+		analytics.NewAnalytics(s.analyticsOptions, s.redisCache).Start()
+	}
 
 	return nil
 }
 
 func (s *preparedAuthzServer) Run() error {
-	return s.genericAPIServer.Run()
+	// 启动 shutdown 监听
+	if err := s.gs.Start(); err != nil {
+		log.Fatalf("Start shutdown manager failed: %s", err.Error())
+	}
+
+	// 启动配置热加载监听，监听失败不影响服务运行
+	if err := s.gr.Start(); err != nil {
+		log.Warnf("Start reload manager failed: %s", err.Error())
+	}
+
+	if err := s.genericAPIServer.Run(); err != nil {
+		return err
+	}
+
+	// HTTP 服务因优雅关闭而退出时，等待关闭流程结束
+	<-s.gs.Done()
+
+	return nil
 }
 
 func buildGenericConfig(cfg *config.Config) (genericConfig *genericapiserver.Config, err error) {
@@ -107,6 +208,22 @@ func buildGenericConfig(cfg *config.Config) (genericConfig *genericapiserver.Con
 	if err = cfg.GenericServerRunOptions.ApplyTo(genericConfig); err != nil {
 		return
 	}
+	if err = cfg.FeatureOptions.ApplyTo(genericConfig); err != nil {
+		return
+	}
+	// 配置了管理员时，/debug/pprof 使用 secret 认证并检查是否为管理员，修改采样率在任何访问限制下都需要管理员
+	if len(cfg.ProfilingOptions.Admins) > 0 {
+		genericConfig.ProfilingAuth = []gin.HandlerFunc{
+			newCacheAuth(cfg.JwksOptions).AuthFunc(),
+			adminOnly(cfg.ProfilingOptions.Admins),
+		}
+	}
+	if err = cfg.CORSOptions.ApplyTo(genericConfig); err != nil {
+		return
+	}
+	if err = cfg.RateLimitOptions.ApplyTo(genericConfig, cfg.RedisOptions); err != nil {
+		return
+	}
 	if err = cfg.SecureServing.ApplyTo(genericConfig); err != nil {
 		return
 	}
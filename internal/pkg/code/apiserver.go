@@ -14,6 +14,33 @@ const (
 
 	// ErrUserAlreadyExist - 400: User already exist.
 	ErrUserAlreadyExist
+
+	// ErrUserLocked - 403: User account is locked.
+	ErrUserLocked
+
+	// ErrMFARequired - 401: Multi-factor authentication is required.
+	ErrMFARequired
+
+	// ErrMFACodeInvalid - 401: Invalid multi-factor authentication code.
+	ErrMFACodeInvalid
+
+	// ErrMFAChallengeInvalid - 401: Multi-factor authentication challenge is invalid or expired.
+	ErrMFAChallengeInvalid
+
+	// ErrMFAAlreadyEnabled - 400: Multi-factor authentication is already enabled.
+	ErrMFAAlreadyEnabled
+
+	// ErrMFANotEnrolled - 400: Multi-factor authentication enrollment has not been started.
+	ErrMFANotEnrolled
+
+	// ErrPasswordExpired - 401: Password has expired and must be changed.
+	ErrPasswordExpired
+
+	// ErrVerificationTokenInvalid - 400: Verification token is invalid or expired.
+	ErrVerificationTokenInvalid
+
+	// ErrEmailNotVerified - 401: Email address has not been verified.
+	ErrEmailNotVerified
 )
 
 // skt-apiserver: secret errors.
@@ -23,10 +50,16 @@ const (
 
 	// ErrSecretNotFound - 404: Secret not found.
 	ErrSecretNotFound
+
+	// ErrSecretAlreadyExist - 400: Secret already exist.
+	ErrSecretAlreadyExist
 )
 
 // skt-apiserver: policy errors.
 const (
 	// ErrPolicyNotFound - 404: Policy not found.
 	ErrPolicyNotFound int = iota + 110201
+
+	// ErrPolicyAlreadyExist - 400: Policy already exist.
+	ErrPolicyAlreadyExist
 )
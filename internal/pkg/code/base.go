@@ -28,6 +28,9 @@ const (
 
 	// ErrPageNotFound - 404: Page not found.
 	ErrPageNotFound
+
+	// ErrTooManyRequests - 429: Too many requests.
+	ErrTooManyRequests
 )
 
 // common: database errors.
@@ -58,6 +61,15 @@ const (
 
 	// ErrPermissionDenied - 403: Permission denied.
 	ErrPermissionDenied
+
+	// ErrTokenRevoked - 401: Token has been revoked.
+	ErrTokenRevoked
+
+	// ErrIdentityProviderNotFound - 404: Identity provider not found.
+	ErrIdentityProviderNotFound
+
+	// ErrFederatedLoginFailed - 401: Login with identity provider failed.
+	ErrFederatedLoginFailed
 )
 
 // common: encode/decode errors.
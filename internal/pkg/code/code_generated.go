@@ -11,15 +11,27 @@ package code
 func init() {
 	register(ErrUserNotFound, 404, "User not found")
 	register(ErrUserAlreadyExist, 400, "User already exist")
+	register(ErrUserLocked, 403, "User account is locked")
+	register(ErrMFARequired, 401, "Multi-factor authentication is required")
+	register(ErrMFACodeInvalid, 401, "Invalid multi-factor authentication code")
+	register(ErrMFAChallengeInvalid, 401, "Multi-factor authentication challenge is invalid or expired")
+	register(ErrMFAAlreadyEnabled, 400, "Multi-factor authentication is already enabled")
+	register(ErrMFANotEnrolled, 400, "Multi-factor authentication enrollment has not been started")
+	register(ErrPasswordExpired, 401, "Password has expired and must be changed")
+	register(ErrVerificationTokenInvalid, 400, "Verification token is invalid or expired")
+	register(ErrEmailNotVerified, 401, "Email address has not been verified")
 	register(ErrReachMaxCount, 400, "Secret reach the max count")
 	register(ErrSecretNotFound, 404, "Secret not found")
+	register(ErrSecretAlreadyExist, 400, "Secret already exist")
 	register(ErrPolicyNotFound, 404, "Policy not found")
+	register(ErrPolicyAlreadyExist, 400, "Policy already exist")
 	register(ErrSuccess, 200, "OK")
 	register(ErrUnknown, 500, "Internal server error")
 	register(ErrBind, 400, "Error occurred while binding the request body to the struct")
 	register(ErrValidation, 400, "Validation failed")
 	register(ErrTokenInvalid, 401, "Token invalid")
 	register(ErrPageNotFound, 404, "Page not found")
+	register(ErrTooManyRequests, 429, "Too many requests")
 	register(ErrDatabase, 500, "Database error")
 	register(ErrEncrypt, 401, "Error occurred while encrypting the user password")
 	register(ErrSignatureInvalid, 401, "Signature is invalid")
@@ -28,6 +40,9 @@ func init() {
 	register(ErrMissingHeader, 401, "The `Authorization` header was empty")
 	register(ErrPasswordIncorrect, 401, "Password was incorrect")
 	register(ErrPermissionDenied, 403, "Permission denied")
+	register(ErrTokenRevoked, 401, "Token has been revoked")
+	register(ErrIdentityProviderNotFound, 404, "Identity provider not found")
+	register(ErrFederatedLoginFailed, 401, "Login with identity provider failed")
 	register(ErrEncodingFailed, 500, "Encoding failed due to an error with the data")
 	register(ErrDecodingFailed, 500, "Decoding failed due to an error with the data")
 	register(ErrInvalidJSON, 500, "Data is not valid JSON")
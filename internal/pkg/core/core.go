@@ -21,12 +21,16 @@ type ErrResponse struct {
 	Reference string `json:"reference,omitempty"` // 解决此错误的参考文档
 }
 
+// KeyErrorCode 为 Gin 上下文中保存本次请求业务错误码的键，未设置表示请求没有发生错误.
+const KeyErrorCode = "errorCode"
+
 // WriteResponse 将错误或响应数据写入 HTTP 响应主体
 // WriteResponse 使用 errors.ParseCoder 方法，根据错误类型，尝试从 err 中提取业务错误码和错误信息.
 func WriteResponse(c *gin.Context, err error, data interface{}) {
 	if err != nil {
 		log.Errorf("%#+v %v", err, data)
 		coder := errors.ParseCoder(err)
+		c.Set(KeyErrorCode, coder.Code())
 		c.JSON(coder.HTTPStatus(), ErrResponse{
 			Code:      coder.Code(),
 			Message:   coder.String(),
@@ -0,0 +1,145 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package jwks
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// JSONWebKey 是 RFC 7517 定义的 JSON Web Key，只包含公钥.
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Algorithm string `json:"alg"`
+	Use       string `json:"use"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+}
+
+// JSONWebKeySet 是 RFC 7517 定义的 JSON Web Key Set.
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// JSONWebKey 将密钥的公钥转换为 JSON Web Key.
+func (k *Key) JSONWebKey() JSONWebKey {
+	jwk := JSONWebKey{KeyID: k.ID, Algorithm: k.Algorithm, Use: "sig"}
+
+	switch pub := k.PublicKey.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = encode(pub.N.Bytes())
+		jwk.E = encode(big.NewInt(int64(pub.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (pub.Curve.Params().BitSize + 7) / 8
+		jwk.KeyType = "EC"
+		jwk.Curve = pub.Curve.Params().Name
+		jwk.X = encode(pub.X.FillBytes(make([]byte, size)))
+		jwk.Y = encode(pub.Y.FillBytes(make([]byte, size)))
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = encode(pub)
+	}
+
+	return jwk
+}
+
+// Key 将 JSON Web Key 转换为只能用于验证 token 的密钥.
+func (jwk JSONWebKey) Key() (*Key, error) {
+	var pub interface{}
+	switch jwk.KeyType {
+	case "RSA":
+		n, err := decode(jwk.N)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: invalid n: %w", jwk.KeyID, err)
+		}
+		e, err := decode(jwk.E)
+		if err != nil || len(e) == 0 || len(e) > 4 {
+			return nil, fmt.Errorf("key %s: invalid e", jwk.KeyID)
+		}
+		pub = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	case "EC":
+		curve, ok := curves[jwk.Curve]
+		if !ok {
+			return nil, fmt.Errorf("key %s: unsupported curve %s", jwk.KeyID, jwk.Curve)
+		}
+		x, errX := decode(jwk.X)
+		y, errY := decode(jwk.Y)
+		if errX != nil || errY != nil {
+			return nil, fmt.Errorf("key %s: invalid coordinates", jwk.KeyID)
+		}
+		ec := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(ec.X, ec.Y) {
+			return nil, fmt.Errorf("key %s: point is not on curve %s", jwk.KeyID, jwk.Curve)
+		}
+		pub = ec
+	case "OKP":
+		x, err := decode(jwk.X)
+		if err != nil || jwk.Curve != "Ed25519" || len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("key %s: invalid Ed25519 key", jwk.KeyID)
+		}
+		pub = ed25519.PublicKey(x)
+	default:
+		return nil, fmt.Errorf("key %s: unsupported key type %s", jwk.KeyID, jwk.KeyType)
+	}
+
+	key, err := NewKey(jwk.KeyID, pub)
+	if err != nil {
+		return nil, err
+	}
+	if jwk.Algorithm != "" && jwk.Algorithm != key.Algorithm {
+		return nil, fmt.Errorf("key %s: algorithm %s does not match the key type", jwk.KeyID, jwk.Algorithm)
+	}
+
+	return key, nil
+}
+
+// ParseJSONWebKeySet 解析 JWKS，返回一个只能用于验证 token 的 KeySet，不支持的密钥会被忽略.
+func ParseJSONWebKeySet(data []byte) (*KeySet, error) {
+	var set JSONWebKeySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make([]*Key, 0, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.Key()
+		if err != nil {
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	return NewKeySet("", keys)
+}
+
+var curves = map[string]elliptic.Curve{
+	"P-256": elliptic.P256(),
+	"P-384": elliptic.P384(),
+	"P-521": elliptic.P521(),
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decode(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
@@ -0,0 +1,124 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package jwks
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+)
+
+// minRSAKeyBits 为 RSA 密钥的最小长度.
+const minRSAKeyBits = 2048
+
+// Key 是一个用于签发或验证 JWT 的非对称密钥，签名算法由密钥类型决定：
+// RSA 密钥使用 RS256，ECDSA 密钥根据曲线使用 ES256、ES384 或 ES512，Ed25519 密钥使用 EdDSA.
+type Key struct {
+	// ID 为密钥标识，签发的 token 在 header 的 kid 字段中携带
+	ID        string
+	Algorithm string
+	PublicKey crypto.PublicKey
+
+	privateKey crypto.PrivateKey
+}
+
+// CanSign 判断密钥是否包含私钥，只有包含私钥的密钥可以签发 token.
+func (k *Key) CanSign() bool {
+	return k.privateKey != nil
+}
+
+// ParsePrivateKey 解析 PEM 编码的私钥，支持 PKCS#1、SEC 1 和 PKCS#8 格式.
+func ParsePrivateKey(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: no PEM data found", kid)
+	}
+
+	var priv interface{}
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		priv, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		priv, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		priv, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", kid, err)
+	}
+
+	signer, ok := priv.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("key %s: unsupported private key type %T", kid, priv)
+	}
+	key, err := NewKey(kid, signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	key.privateKey = priv
+
+	return key, nil
+}
+
+// ParsePublicKey 解析 PEM 编码的公钥，支持 PKIX 和 PKCS#1 格式.
+func ParsePublicKey(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("key %s: no PEM data found", kid)
+	}
+
+	var pub interface{}
+	var err error
+	if block.Type == "RSA PUBLIC KEY" {
+		pub, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	} else {
+		pub, err = x509.ParsePKIXPublicKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("key %s: %w", kid, err)
+	}
+
+	return NewKey(kid, pub)
+}
+
+// NewKey 基于公钥创建一个只能用于验证 token 的密钥.
+func NewKey(kid string, pub crypto.PublicKey) (*Key, error) {
+	if kid == "" {
+		return nil, fmt.Errorf("key id must not be empty")
+	}
+
+	key := &Key{ID: kid, PublicKey: pub}
+	switch k := pub.(type) {
+	case *rsa.PublicKey:
+		if k.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("key %s: RSA key must be at least %d bits", kid, minRSAKeyBits)
+		}
+		key.Algorithm = "RS256"
+	case *ecdsa.PublicKey:
+		switch k.Curve {
+		case elliptic.P256():
+			key.Algorithm = "ES256"
+		case elliptic.P384():
+			key.Algorithm = "ES384"
+		case elliptic.P521():
+			key.Algorithm = "ES512"
+		default:
+			return nil, fmt.Errorf("key %s: unsupported elliptic curve %s", kid, k.Curve.Params().Name)
+		}
+	case ed25519.PublicKey:
+		key.Algorithm = "EdDSA"
+	default:
+		return nil, fmt.Errorf("key %s: unsupported public key type %T", kid, pub)
+	}
+
+	return key, nil
+}
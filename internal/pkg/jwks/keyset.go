@@ -0,0 +1,100 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package jwks
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+var (
+	// ErrMissingKID 表示 token 的 header 中没有 kid 字段.
+	ErrMissingKID = errors.New("missing kid in token header")
+	// ErrKeyNotFound 表示 kid 对应的密钥不存在.
+	ErrKeyNotFound = errors.New("key not found")
+)
+
+// KeySet 是一组通过 kid 区分的密钥，使用其中一个密钥签发 token，使用所有密钥验证 token.
+// 轮换密钥时，先加入新密钥并发布到 JWKS，再切换签发密钥，旧密钥在其签发的 token 全部过期后移除.
+type KeySet struct {
+	signing *Key
+	keys    map[string]*Key
+	// ids 保存密钥的加入顺序，用于生成 JWKS
+	ids []string
+}
+
+// NewKeySet 创建一个 KeySet，signingKID 为签发 token 使用的密钥，为空表示只用于验证 token.
+func NewKeySet(signingKID string, keys []*Key) (*KeySet, error) {
+	s := &KeySet{keys: make(map[string]*Key, len(keys))}
+	for _, key := range keys {
+		if _, ok := s.keys[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key id %s", key.ID)
+		}
+		s.keys[key.ID] = key
+		s.ids = append(s.ids, key.ID)
+	}
+
+	if signingKID != "" {
+		key, ok := s.keys[signingKID]
+		if !ok {
+			return nil, fmt.Errorf("signing key %s: %w", signingKID, ErrKeyNotFound)
+		}
+		if !key.CanSign() {
+			return nil, fmt.Errorf("signing key %s has no private key", signingKID)
+		}
+		s.signing = key
+	}
+
+	return s, nil
+}
+
+// SigningKey 返回签发 token 使用的密钥，只用于验证时返回 nil.
+func (s *KeySet) SigningKey() *Key {
+	return s.signing
+}
+
+// Sign 使用签发密钥签发 token，并在 header 中设置 kid.
+func (s *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if s.signing == nil {
+		return "", errors.New("no signing key")
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(s.signing.Algorithm), claims)
+	token.Header["kid"] = s.signing.ID
+
+	return token.SignedString(s.signing.privateKey)
+}
+
+// Keyfunc 根据 token header 中的 kid 返回验证签名使用的公钥，可以作为 jwt.Parse 的 keyFunc 使用.
+// token 的签名算法必须与密钥的算法一致，避免算法混淆攻击.
+func (s *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, ErrMissingKID
+	}
+
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("kid %s: %w", kid, ErrKeyNotFound)
+	}
+	if token.Method.Alg() != key.Algorithm {
+		return nil, fmt.Errorf("kid %s: unexpected signing method %s", kid, token.Method.Alg())
+	}
+
+	return key.PublicKey, nil
+}
+
+// JWKS 返回所有密钥的公钥.
+func (s *KeySet) JWKS() JSONWebKeySet {
+	set := JSONWebKeySet{Keys: make([]JSONWebKey, 0, len(s.ids))}
+	for _, id := range s.ids {
+		set.Keys = append(set.Keys, s.keys[id].JSONWebKey())
+	}
+
+	return set
+}
@@ -0,0 +1,168 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package jwks
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+func newTestKey(t *testing.T, kid string, priv crypto.PrivateKey) *Key {
+	t.Helper()
+
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("MarshalPKCS8PrivateKey() error = %v", err)
+	}
+	key, err := ParsePrivateKey(kid, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("ParsePrivateKey() error = %v", err)
+	}
+
+	return key
+}
+
+func testKeys(t *testing.T) []*Key {
+	t.Helper()
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	return []*Key{
+		newTestKey(t, "rsa", rsaKey),
+		newTestKey(t, "ec", ecKey),
+		newTestKey(t, "ed", edKey),
+	}
+}
+
+func TestKeySet_SignAndVerify(t *testing.T) {
+	keys := testKeys(t)
+	want := map[string]string{"rsa": "RS256", "ec": "ES256", "ed": "EdDSA"}
+
+	// 验证方只需要 JWKS 中的公钥
+	all, err := NewKeySet("", keys)
+	if err != nil {
+		t.Fatalf("NewKeySet() error = %v", err)
+	}
+	data, _ := json.Marshal(all.JWKS())
+	verifier, err := ParseJSONWebKeySet(data)
+	if err != nil {
+		t.Fatalf("ParseJSONWebKeySet() error = %v", err)
+	}
+
+	for _, key := range keys {
+		if key.Algorithm != want[key.ID] {
+			t.Errorf("algorithm of %s = %s, want %s", key.ID, key.Algorithm, want[key.ID])
+		}
+
+		signer, err := NewKeySet(key.ID, keys)
+		if err != nil {
+			t.Fatalf("NewKeySet() error = %v", err)
+		}
+		token, err := signer.Sign(jwt.MapClaims{"sub": "admin"})
+		if err != nil {
+			t.Fatalf("Sign() with %s error = %v", key.ID, err)
+		}
+
+		parsed, err := jwt.Parse(token, verifier.Keyfunc)
+		if err != nil || parsed.Header["kid"] != key.ID || parsed.Method.Alg() != key.Algorithm {
+			t.Errorf("Parse() token signed by %s: header %v, error = %v", key.ID, parsed.Header, err)
+		}
+	}
+}
+
+func TestKeySet_Keyfunc(t *testing.T) {
+	keys := testKeys(t)
+	s, _ := NewKeySet("ec", keys)
+	token, _ := s.Sign(jwt.MapClaims{"sub": "admin"})
+
+	// 使用其他 kid 的公钥验证时，签名算法不一致
+	tests := []struct {
+		name    string
+		kid     interface{}
+		wantErr error
+	}{
+		{name: "missing kid", kid: nil, wantErr: ErrMissingKID},
+		{name: "unknown kid", kid: "unknown", wantErr: ErrKeyNotFound},
+		{name: "algorithm mismatch", kid: "rsa"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
+				token.Header["kid"] = tt.kid
+				return s.Keyfunc(token)
+			})
+			if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := NewKeySet("missing", keys); err == nil {
+		t.Errorf("NewKeySet() with missing signing key should fail")
+	}
+	pub, _ := NewKey("pub", keys[0].PublicKey)
+	if _, err := NewKeySet("pub", []*Key{pub}); err == nil {
+		t.Errorf("NewKeySet() with public signing key should fail")
+	}
+}
+
+func TestRemoteKeySet_Rotation(t *testing.T) {
+	keys := testKeys(t)
+	old, _ := NewKeySet("rsa", keys[:1])
+	rotated, _ := NewKeySet("ec", keys[:2])
+
+	var published atomic.Value
+	published.Store(old)
+	var fetches int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		_ = json.NewEncoder(w).Encode(published.Load().(*KeySet).JWKS())
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	remote := NewRemoteKeySet(srv.URL, time.Hour)
+	remote.now = func() time.Time { return now }
+
+	oldToken, _ := old.Sign(jwt.MapClaims{"sub": "admin"})
+	if _, err := jwt.Parse(oldToken, remote.Keyfunc); err != nil {
+		t.Fatalf("Parse() token signed by the old key error = %v", err)
+	}
+
+	// 新密钥发布后，遇到未知 kid 时重新获取 JWKS，但需要间隔 minFetchInterval
+	published.Store(rotated)
+	newToken, _ := rotated.Sign(jwt.MapClaims{"sub": "admin"})
+	if _, err := jwt.Parse(newToken, remote.Keyfunc); err == nil {
+		t.Errorf("Parse() within minFetchInterval should fail")
+	}
+	now = now.Add(minFetchInterval)
+	if _, err := jwt.Parse(newToken, remote.Keyfunc); err != nil {
+		t.Errorf("Parse() token signed by the new key error = %v", err)
+	}
+	if _, err := jwt.Parse(oldToken, remote.Keyfunc); err != nil {
+		t.Errorf("Parse() token signed by the old key after rotation error = %v", err)
+	}
+	if n := atomic.LoadInt32(&fetches); n != 2 {
+		t.Errorf("JWKS fetched %d times, want 2", n)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package jwks
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+const (
+	// minFetchInterval 为两次请求 JWKS 地址的最小间隔，避免伪造的 kid 导致频繁请求.
+	minFetchInterval = 10 * time.Second
+	// maxJWKSSize 为 JWKS 响应的最大长度.
+	maxJWKSSize = 1 << 20
+)
+
+// RemoteKeySet 从 JWKS 地址获取公钥并缓存，用于验证其他服务签发的 token.
+// 缓存超过刷新间隔或遇到未知的 kid 时重新获取，获取失败时继续使用已缓存的公钥.
+type RemoteKeySet struct {
+	url             string
+	refreshInterval time.Duration
+	client          *http.Client
+	now             func() time.Time
+
+	mu          sync.Mutex
+	keys        *KeySet
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	err         error
+}
+
+// NewRemoteKeySet 创建一个从 url 获取公钥的 RemoteKeySet，refreshInterval 为缓存的刷新间隔.
+func NewRemoteKeySet(url string, refreshInterval time.Duration) *RemoteKeySet {
+	return &RemoteKeySet{
+		url:             url,
+		refreshInterval: refreshInterval,
+		client:          &http.Client{Timeout: 5 * time.Second},
+		now:             time.Now,
+	}
+}
+
+// Keyfunc 根据 token header 中的 kid 返回验证签名使用的公钥，可以作为 jwt.Parse 的 keyFunc 使用.
+func (r *RemoteKeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	keys, err := r.get(false)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := keys.Keyfunc(token)
+	if !errors.Is(err, ErrKeyNotFound) {
+		return key, err
+	}
+
+	// 未知的 kid 可能来自刚轮换的密钥，重新获取 JWKS
+	if keys, err = r.get(true); err != nil {
+		return nil, err
+	}
+	return keys.Keyfunc(token)
+}
+
+func (r *RemoteKeySet) get(force bool) (*KeySet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	if r.keys != nil && !force && now.Sub(r.fetchedAt) < r.refreshInterval {
+		return r.keys, nil
+	}
+	if now.Sub(r.attemptedAt) < minFetchInterval {
+		if r.keys == nil {
+			return nil, r.err
+		}
+		return r.keys, nil
+	}
+	r.attemptedAt = now
+
+	keys, err := r.fetch()
+	if err != nil {
+		r.err = fmt.Errorf("fetch JWKS from %s: %w", r.url, err)
+		if r.keys == nil {
+			return nil, r.err
+		}
+
+		log.Warnf("%s, keep using the cached keys", r.err.Error())
+		return r.keys, nil
+	}
+	r.keys, r.fetchedAt, r.err = keys, now, nil
+
+	return keys, nil
+}
+
+func (r *RemoteKeySet) fetch() (*KeySet, error) {
+	resp, err := r.client.Get(r.url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseJSONWebKeySet(data)
+}
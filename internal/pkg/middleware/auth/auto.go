@@ -35,6 +35,16 @@ func NewAutoStrategy(basic, jwt middleware.AuthStrategy) AutoStrategy {
 	}
 }
 
+// HasCredential 判断请求是否携带了 Authorization 头，或者 JWT 认证策略可以识别的 token.
+func (a AutoStrategy) HasCredential(c *gin.Context) bool {
+	if c.Request.Header.Get("Authorization") != "" {
+		return true
+	}
+	detector, ok := a.jwt.(CredentialDetector)
+
+	return ok && detector.HasCredential(c)
+}
+
 func (a AutoStrategy) AuthFunc() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		operator := middleware.AuthOperator{}
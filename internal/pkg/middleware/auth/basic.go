@@ -17,7 +17,8 @@ import (
 	"github.com/changaolee/skeleton/pkg/errors"
 )
 
-type CompareFunc func(username string, password string) bool
+// CompareFunc 校验用户名和密码，校验失败时返回带有业务错误码的错误.
+type CompareFunc func(c *gin.Context, username string, password string) error
 
 // BasicStrategy 定义 Basic 认证策略.
 type BasicStrategy struct {
@@ -53,7 +54,7 @@ func (b BasicStrategy) AuthFunc() gin.HandlerFunc {
 		// [username, password]
 		pair := strings.SplitN(string(payload), ":", 2)
 
-		if len(pair) != 2 || !b.compare(pair[0], pair[1]) {
+		if len(pair) != 2 {
 			core.WriteResponse(
 				c,
 				errors.WithCode(code.ErrSignatureInvalid, "Authorization header format is wrong"),
@@ -64,6 +65,13 @@ func (b BasicStrategy) AuthFunc() gin.HandlerFunc {
 			return
 		}
 
+		if err := b.compare(c, pair[0], pair[1]); err != nil {
+			core.WriteResponse(c, err, nil)
+			c.Abort()
+
+			return
+		}
+
 		c.Set(middleware.UsernameKey, pair[0])
 
 		c.Next()
@@ -15,6 +15,7 @@ import (
 	"github.com/changaolee/skeleton/internal/pkg/code"
 	"github.com/changaolee/skeleton/internal/pkg/core"
 	"github.com/changaolee/skeleton/internal/pkg/middleware"
+	"github.com/changaolee/skeleton/internal/pkg/revocation"
 	"github.com/changaolee/skeleton/pkg/errors"
 )
 
@@ -32,16 +33,29 @@ type Secret struct {
 
 type getSecretFunc func(kid string) (Secret, error)
 
+// IssuedTokens 定义了验证 skt-apiserver 使用非对称密钥签发的 token 的方式.
+type IssuedTokens struct {
+	// Keyfunc 返回验证签名使用的公钥
+	Keyfunc jwt.Keyfunc
+	// Audience 和 Issuer 为 token 的 aud 和 iss 字段必须匹配的值
+	Audience string
+	Issuer   string
+	// Revoker 为 skt-apiserver 共享的 token 吊销列表
+	Revoker revocation.Revoker
+}
+
 // CacheStrategy 定义 Cache 认证策略（基于缓存实现的 JWT Bearer 认证）.
+// 使用 HMAC 签名的 token 通过缓存中的 secret 验证，其他 token 按照 issued 验证.
 type CacheStrategy struct {
-	get getSecretFunc
+	get    getSecretFunc
+	issued *IssuedTokens
 }
 
 var _ middleware.AuthStrategy = &CacheStrategy{}
 
-// NewCacheStrategy 基于给定的 get 方法创建一个 Cache 认证策略.
-func NewCacheStrategy(get getSecretFunc) CacheStrategy {
-	return CacheStrategy{get}
+// NewCacheStrategy 基于给定的 get 方法创建一个 Cache 认证策略，issued 为 nil 时只接受 HMAC 签名的 token.
+func NewCacheStrategy(get getSecretFunc, issued *IssuedTokens) CacheStrategy {
+	return CacheStrategy{get: get, issued: issued}
 }
 
 func (cache CacheStrategy) AuthFunc() gin.HandlerFunc {
@@ -63,9 +77,13 @@ func (cache CacheStrategy) AuthFunc() gin.HandlerFunc {
 
 		// 验证 token
 		parsedT, err := jwt.ParseWithClaims(rawJWT, claims, func(token *jwt.Token) (interface{}, error) {
-			// 验证 token 的签名算法
+			// 验证 token 的签名算法，非对称签名的 token 使用公钥验证
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+				if cache.issued == nil {
+					return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+				}
+
+				return cache.issued.Keyfunc(token)
 			}
 
 			// 从 token 中获取 secret 的标识 kid
@@ -75,8 +93,8 @@ func (cache CacheStrategy) AuthFunc() gin.HandlerFunc {
 			}
 
 			// 从缓存中读取 secret
-			secret, err := cache.get(kid)
-			if err != nil {
+			var err error
+			if secret, err = cache.get(kid); err != nil {
 				return nil, ErrMissingSecret
 			}
 
@@ -99,11 +117,47 @@ func (cache CacheStrategy) AuthFunc() gin.HandlerFunc {
 			return
 		}
 
-		c.Set(middleware.UsernameKey, secret.Username)
+		username := secret.Username
+		if _, ok := parsedT.Method.(*jwt.SigningMethodHMAC); !ok {
+			if username, err = cache.issued.verify(c, *claims); err != nil {
+				core.WriteResponse(c, err, nil)
+				c.Abort()
+
+				return
+			}
+		}
+
+		c.Set(middleware.UsernameKey, username)
 		c.Next()
 	}
 }
 
+// verify 检查 skt-apiserver 签发的 token 的 aud、iss 和 sub 字段，以及 token 是否已被吊销，返回 token 所属的用户名.
+// 吊销列表不可用时拒绝请求.
+func (i *IssuedTokens) verify(c *gin.Context, claims jwt.MapClaims) (string, error) {
+	if !claims.VerifyAudience(i.Audience, true) {
+		return "", errors.WithCode(code.ErrSignatureInvalid, "token audience is not %s", i.Audience)
+	}
+	if !claims.VerifyIssuer(i.Issuer, true) {
+		return "", errors.WithCode(code.ErrSignatureInvalid, "token issuer is not %s", i.Issuer)
+	}
+	username, _ := claims["sub"].(string)
+	if username == "" {
+		return "", errors.WithCode(code.ErrSignatureInvalid, "token has no subject")
+	}
+
+	jti, _ := claims["jti"].(string)
+	revoked, err := i.Revoker.IsRevoked(c, jti, username, revocation.IssuedAt(claims))
+	if err != nil {
+		return "", errors.WithCode(code.ErrDatabase, "check token revocation: %s", err.Error())
+	}
+	if revoked {
+		return "", errors.WithCode(code.ErrTokenRevoked, "token %s of user %s has been revoked", jti, username)
+	}
+
+	return username, nil
+}
+
 // KeyExpired 检查一个 key 是否过期.
 func KeyExpired(expires int64) bool {
 	if expires >= 1 {
@@ -0,0 +1,114 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
+	"github.com/changaolee/skeleton/internal/pkg/revocation"
+)
+
+// failingRevoker 模拟不可用的吊销列表.
+type failingRevoker struct {
+	revocation.Revoker
+}
+
+func (failingRevoker) IsRevoked(context.Context, string, string, time.Time) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func TestCacheStrategy_IssuedTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revoker := revocation.NewMemoryRevoker()
+	issued := &IssuedTokens{
+		Keyfunc:  func(*jwt.Token) (interface{}, error) { return &key.PublicKey, nil },
+		Audience: "skt.api.changaolee.com",
+		Issuer:   "skt-apiserver",
+		Revoker:  revoker,
+	}
+	newEngine := func(issued *IssuedTokens) *gin.Engine {
+		g := gin.New()
+		g.GET("/", NewCacheStrategy(nil, issued).AuthFunc(), func(c *gin.Context) {
+			c.String(http.StatusOK, c.GetString(middleware.UsernameKey))
+		})
+		return g
+	}
+	sign := func(claims jwt.MapClaims) string {
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+		token, err := jwt.NewWithClaims(jwt.SigningMethodES256, claims).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	valid := func(sub string) jwt.MapClaims {
+		return jwt.MapClaims{
+			"aud":      "skt.api.changaolee.com",
+			"iss":      "skt-apiserver",
+			"sub":      sub,
+			"jti":      "t-" + sub,
+			"orig_iat": time.Now().Add(-time.Minute).Unix(),
+		}
+	}
+	with := func(claims jwt.MapClaims, key string, value interface{}) jwt.MapClaims {
+		claims[key] = value
+		return claims
+	}
+
+	if err := revoker.RevokeUser(context.Background(), "mallory"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		issued *IssuedTokens
+		token  string
+		want   int
+	}{
+		{"valid", issued, sign(valid("alice")), http.StatusOK},
+		{"wrong audience", issued, sign(with(valid("alice"), "aud", "skt.authz.changaolee.com")), http.StatusUnauthorized},
+		{"wrong issuer", issued, sign(with(valid("alice"), "iss", "evil")), http.StatusUnauthorized},
+		{"empty subject", issued, sign(valid("")), http.StatusUnauthorized},
+		{"revoked user", issued, sign(valid("mallory")), http.StatusUnauthorized},
+		{"revocation unavailable", &IssuedTokens{
+			Keyfunc:  issued.Keyfunc,
+			Audience: issued.Audience,
+			Issuer:   issued.Issuer,
+			Revoker:  failingRevoker{},
+		}, sign(valid("alice")), http.StatusInternalServerError},
+		{"asymmetric disabled", nil, sign(valid("alice")), http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Authorization", "Bearer "+tt.token)
+		newEngine(tt.issued).ServeHTTP(w, req)
+
+		if w.Code != tt.want {
+			t.Errorf("%s: status = %d %s, want %d", tt.name, w.Code, w.Body.String(), tt.want)
+		}
+		if tt.want == http.StatusOK && w.Body.String() != "alice" {
+			t.Errorf("%s: username = %q, want alice", tt.name, w.Body.String())
+		}
+	}
+}
@@ -6,6 +6,8 @@
 package auth
 
 import (
+	"strings"
+
 	ginjwt "github.com/appleboy/gin-jwt/v2"
 	"github.com/gin-gonic/gin"
 
@@ -30,3 +32,38 @@ func NewJWTStrategy(gjwt ginjwt.GinJWTMiddleware) JWTStrategy {
 func (j JWTStrategy) AuthFunc() gin.HandlerFunc {
 	return j.MiddlewareFunc()
 }
+
+// HasCredential 判断请求是否在 TokenLookup 配置的任一位置携带了 token.
+func (j JWTStrategy) HasCredential(c *gin.Context) bool {
+	lookup := j.TokenLookup
+	if lookup == "" {
+		lookup = "header:Authorization"
+	}
+
+	for _, method := range strings.Split(lookup, ",") {
+		parts := strings.SplitN(strings.TrimSpace(method), ":", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		var token string
+		key := strings.TrimSpace(parts[1])
+		switch strings.TrimSpace(parts[0]) {
+		case "header":
+			token = c.Request.Header.Get(key)
+		case "query":
+			token = c.Query(key)
+		case "cookie":
+			token, _ = c.Cookie(key)
+		case "param":
+			token = c.Param(key)
+		case "form":
+			token = c.PostForm(key)
+		}
+		if token != "" {
+			return true
+		}
+	}
+
+	return false
+}
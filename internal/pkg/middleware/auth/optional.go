@@ -0,0 +1,38 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package auth
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
+)
+
+// CredentialDetector 定义了可以判断请求是否携带认证凭证的身份认证策略.
+type CredentialDetector interface {
+	HasCredential(c *gin.Context) bool
+}
+
+// Optional 返回一个可选认证的 Gin 中间件. 请求携带认证凭证时使用 strategy 认证，认证失败时拒绝请求；
+// 不携带时作为匿名请求继续处理，此时上下文中没有当前登录用户. strategy 实现了 CredentialDetector 时
+// 由 strategy 判断请求是否携带认证凭证，否则只检查 Authorization 头.
+func Optional(strategy middleware.AuthStrategy) gin.HandlerFunc {
+	authFunc := strategy.AuthFunc()
+	detector, ok := strategy.(CredentialDetector)
+
+	return func(c *gin.Context) {
+		hasCredential := c.Request.Header.Get("Authorization") != ""
+		if ok {
+			hasCredential = detector.HasCredential(c)
+		}
+		if !hasCredential {
+			c.Next()
+			return
+		}
+
+		authFunc(c)
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ginjwt "github.com/appleboy/gin-jwt/v2"
+	"github.com/gin-gonic/gin"
+)
+
+func TestOptional(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtStrategy := NewJWTStrategy(ginjwt.GinJWTMiddleware{
+		TokenLookup: "header: Authorization, query: token, cookie: jwt",
+	})
+	auto := NewAutoStrategy(BasicStrategy{}, jwtStrategy)
+
+	g := gin.New()
+	g.POST("/users", Optional(auto), func(c *gin.Context) {
+		c.String(http.StatusOK, "anonymous")
+	})
+
+	tests := []struct {
+		name      string
+		setup     func(r *http.Request)
+		anonymous bool
+	}{
+		{"none", func(r *http.Request) {}, true},
+		{"header", func(r *http.Request) { r.Header.Set("Authorization", "Token x") }, false},
+		{"query", func(r *http.Request) { r.URL.RawQuery = "token=x" }, false},
+		{"cookie", func(r *http.Request) { r.AddCookie(&http.Cookie{Name: "jwt", Value: "x"}) }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/users", nil)
+			tt.setup(req)
+			w := httptest.NewRecorder()
+			g.ServeHTTP(w, req)
+			if anonymous := w.Body.String() == "anonymous"; anonymous != tt.anonymous {
+				t.Errorf("anonymous = %v, want %v: %d %s", anonymous, tt.anonymous, w.Code, w.Body.String())
+			}
+		})
+	}
+}
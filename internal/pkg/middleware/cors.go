@@ -6,24 +6,123 @@
 package middleware
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
-const (
-	maxAge = 12
-)
+// CorsConfig 定义了 CORS 中间件的配置.
+type CorsConfig struct {
+	// AllowOrigins 为允许跨域访问的源，"*" 表示允许所有源，"https://*.example.com" 表示允许 example.com 的所有子域名
+	AllowOrigins     []string
+	AllowMethods     []string
+	AllowHeaders     []string
+	ExposeHeaders    []string
+	AllowCredentials bool
+	MaxAge           time.Duration
+}
 
-// Cors 是一个 Gin 中间件，用于开启 CORS 支持.
-func Cors() gin.HandlerFunc {
-	return cors.New(cors.Config{
-		AllowOrigins:     []string{"*"},
+// DefaultCorsConfig 返回默认的 CORS 配置，默认不允许任何跨域访问.
+func DefaultCorsConfig() CorsConfig {
+	return CorsConfig{
+		AllowOrigins:     []string{},
 		AllowMethods:     []string{"PUT", "PATCH", "GET", "POST", "OPTIONS", "DELETE"},
 		AllowHeaders:     []string{"Origin", "Authorization", "Content-Type", "Accept"},
 		ExposeHeaders:    []string{"Content-Length"},
-		AllowCredentials: true,
-		MaxAge:           maxAge * time.Hour,
-	})
+		AllowCredentials: false,
+		MaxAge:           12 * time.Hour,
+	}
+}
+
+// Validate 校验 CORS 配置.
+func (c CorsConfig) Validate() error {
+	for _, origin := range c.AllowOrigins {
+		if origin == "*" {
+			if len(c.AllowOrigins) > 1 {
+				return fmt.Errorf("origin * allows all origins and cannot be combined with other origins")
+			}
+			// 浏览器不接受带凭证的请求使用 Access-Control-Allow-Origin: *
+			if c.AllowCredentials {
+				return fmt.Errorf("origin * cannot be used when credentials are allowed, list the origins explicitly")
+			}
+			continue
+		}
+
+		if err := validateOrigin(origin); err != nil {
+			return err
+		}
+	}
+
+	if c.MaxAge < 0 {
+		return fmt.Errorf("max age cannot be negative")
+	}
+
+	return nil
+}
+
+// validateOrigin 校验单个源，源必须是 scheme://host[:port] 的形式，通配符只能出现在最左侧的子域名.
+func validateOrigin(origin string) error {
+	host := origin
+	if strings.Contains(origin, "*") {
+		scheme, rest, ok := strings.Cut(origin, "://*.")
+		if !ok || strings.Contains(rest, "*") {
+			return fmt.Errorf("invalid origin %q, wildcard is only allowed as the leftmost subdomain, "+
+				"e.g. https://*.example.com", origin)
+		}
+		host = scheme + "://wildcard." + rest
+	}
+
+	u, err := url.Parse(host)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+		u.Path != "" || u.RawQuery != "" || u.User != nil {
+		return fmt.Errorf("invalid origin %q, must be in the form of http(s)://host[:port]", origin)
+	}
+
+	return nil
+}
+
+// corsHandler 保存当前生效的 CORS 中间件，类型为 gin.HandlerFunc.
+var corsHandler atomic.Value
+
+func init() {
+	corsHandler.Store(newCors(DefaultCorsConfig()))
+}
+
+// SetCorsConfig 校验并替换 CORS 中间件的配置，对之后到达的请求生效.
+func SetCorsConfig(config CorsConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	corsHandler.Store(newCors(config))
+
+	return nil
+}
+
+func newCors(config CorsConfig) gin.HandlerFunc {
+	c := cors.Config{
+		AllowOrigins:     config.AllowOrigins,
+		AllowMethods:     config.AllowMethods,
+		AllowHeaders:     config.AllowHeaders,
+		ExposeHeaders:    config.ExposeHeaders,
+		AllowCredentials: config.AllowCredentials,
+		MaxAge:           config.MaxAge,
+		AllowWildcard:    true,
+	}
+	if len(c.AllowOrigins) == 0 {
+		c.AllowOriginFunc = func(string) bool { return false }
+	}
+
+	return cors.New(c)
+}
+
+// Cors 是一个 Gin 中间件，用于开启 CORS 支持，使用 SetCorsConfig 设置的配置.
+func Cors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		corsHandler.Load().(gin.HandlerFunc)(c)
+	}
 }
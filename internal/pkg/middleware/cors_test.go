@@ -0,0 +1,91 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCorsConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		origins []string
+		creds   bool
+		wantErr bool
+	}{
+		{name: "no origins", origins: nil},
+		{name: "all origins", origins: []string{"*"}},
+		{name: "all origins with credentials", origins: []string{"*"}, creds: true, wantErr: true},
+		{name: "all origins mixed", origins: []string{"*", "https://a.example.com"}, wantErr: true},
+		{name: "explicit with credentials", origins: []string{"https://dashboard.example.com:8443"}, creds: true},
+		{name: "wildcard subdomain", origins: []string{"https://*.example.com"}, creds: true},
+		{name: "wildcard not leftmost", origins: []string{"https://api.*.example.com"}, wantErr: true},
+		{name: "wildcard suffix", origins: []string{"https://example.*"}, wantErr: true},
+		{name: "missing scheme", origins: []string{"dashboard.example.com"}, wantErr: true},
+		{name: "with path", origins: []string{"https://dashboard.example.com/"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := DefaultCorsConfig()
+			config.AllowOrigins = tt.origins
+			config.AllowCredentials = tt.creds
+
+			if err := config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	defer func() { _ = SetCorsConfig(DefaultCorsConfig()) }()
+
+	g := gin.New()
+	g.Use(Options, Cors())
+	g.GET("/v1/users", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	serve := func(method, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/v1/users", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, req)
+		return w
+	}
+
+	// 默认不允许任何跨域访问
+	if w := serve(http.MethodGet, "https://dashboard.example.com"); w.Code != http.StatusForbidden {
+		t.Errorf("default config status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+
+	config := DefaultCorsConfig()
+	config.AllowOrigins = []string{"https://dashboard.example.com", "https://*.admin.example.com"}
+	config.AllowCredentials = true
+	if err := SetCorsConfig(config); err != nil {
+		t.Fatalf("SetCorsConfig() error = %v", err)
+	}
+
+	for _, origin := range []string{"https://dashboard.example.com", "https://eu.admin.example.com"} {
+		w := serve(http.MethodOptions, origin)
+		if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != origin ||
+			w.Header().Get("Access-Control-Allow-Credentials") != "true" {
+			t.Errorf("preflight from %s = %d %v, want 204 allowing the origin with credentials", origin, w.Code, w.Header())
+		}
+	}
+
+	if w := serve(http.MethodGet, "https://evil.example.com"); w.Code != http.StatusForbidden {
+		t.Errorf("disallowed origin status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+	if w := serve(http.MethodOptions, "https://evil.example.com"); w.Code != http.StatusForbidden {
+		t.Errorf("disallowed preflight status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
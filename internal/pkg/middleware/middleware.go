@@ -18,7 +18,6 @@ var Middlewares = defaultMiddlewares()
 
 // Secure 是一个 Gin 中间件，用来添加一些安全和资源访问相关的 HTTP 头.
 func Secure(c *gin.Context) {
-	c.Header("Access-Control-Allow-Origin", "*")
 	c.Header("X-Frame-Options", "DENY")
 	c.Header("X-Content-Type-Options", "nosniff")
 	c.Header("X-XSS-Protection", "1; mode=block")
@@ -28,17 +27,18 @@ func Secure(c *gin.Context) {
 	}
 }
 
-// Options 是一个 Gin 中间件，用来设置 options 请求的返回头，然后退出中间件链，并结束请求（浏览器跨域设置）.
+// Options 是一个 Gin 中间件，用来设置 options 请求的返回头，然后退出中间件链，并结束请求.
+// 跨域预检请求的 Access-Control-* 头由 CORS 配置决定.
 func Options(c *gin.Context) {
 	if c.Request.Method != "OPTIONS" {
 		c.Next()
 	} else {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET,POST,PUT,PATCH,DELETE,OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "authorization, origin, content-type, accept")
 		c.Header("Allow", "HEAD,GET,POST,PUT,PATCH,DELETE,OPTIONS")
 		c.Header("Content-Type", "application/json")
-		c.AbortWithStatus(http.StatusOK)
+		Cors()(c)
+		if !c.IsAborted() {
+			c.AbortWithStatus(http.StatusOK)
+		}
 	}
 }
 
@@ -58,6 +58,7 @@ func defaultMiddlewares() map[string]gin.HandlerFunc {
 		"nocache":   NoCache,
 		"cors":      Cors(),
 		"requestid": RequestID(),
+		"ratelimit": RateLimit(),
 		"dump":      gindump.Dump(),
 	}
 }
@@ -0,0 +1,201 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/internal/pkg/ratelimit"
+	"github.com/changaolee/skeleton/pkg/errors"
+	"github.com/changaolee/skeleton/pkg/log"
+	"github.com/changaolee/skeleton/pkg/util/iputil"
+)
+
+// 定义限流的维度.
+const (
+	// RateLimitByIP 每个客户端 IP 使用一个令牌桶.
+	RateLimitByIP = "ip"
+	// RateLimitByUser 每个已认证用户使用一个令牌桶，未认证的请求不受该规则限制.
+	RateLimitByUser = "user"
+	// RateLimitByRoute 匹配该规则的所有请求共享一个令牌桶.
+	RateLimitByRoute = "route"
+)
+
+// rateLimitedKey 在 Gin 上下文中记录请求的限流状态：不存在表示 ratelimit 中间件未启用，
+// false 表示还没有被任何规则计数，true 表示已经被计数，避免同一个请求被重复计数.
+const rateLimitedKey = "rateLimited"
+
+// RateLimitRule 定义了一个路由分组的限流规则.
+type RateLimitRule struct {
+	// Path 为路由前缀，例如 /login、/v1/users，请求匹配前缀最长的规则
+	Path string
+	// Method 为匹配的 HTTP 方法，为空时匹配所有方法，前缀相同时优先匹配指定了方法的规则
+	Method string
+	// Key 为限流的维度，可选值为 ip、user 和 route
+	Key   string
+	Limit ratelimit.Limit
+}
+
+// RateLimitConfig 定义了限流中间件的配置.
+type RateLimitConfig struct {
+	Rules []RateLimitRule
+	// Store 为令牌桶的存储，为 nil 时使用进程内存
+	Store ratelimit.Store
+	// FailOpen 为 true 时令牌桶的存储不可用时放行请求，否则拒绝请求
+	FailOpen bool
+}
+
+// Validate 校验限流配置.
+func (c RateLimitConfig) Validate() error {
+	paths := make(map[string]bool)
+	for _, rule := range c.Rules {
+		if !strings.HasPrefix(rule.Path, "/") {
+			return fmt.Errorf("rate limit path %q must start with /", rule.Path)
+		}
+		if rule.Method != strings.ToUpper(rule.Method) {
+			return fmt.Errorf("rate limit method %q of path %s must be upper case", rule.Method, rule.Path)
+		}
+		if paths[rule.Method+" "+rule.Path] {
+			return fmt.Errorf("duplicate rate limit rule for %s", strings.TrimSpace(rule.Method+" "+rule.Path))
+		}
+		paths[rule.Method+" "+rule.Path] = true
+
+		switch rule.Key {
+		case RateLimitByIP, RateLimitByUser, RateLimitByRoute:
+		default:
+			return fmt.Errorf("rate limit key of path %s must be one of: %s, %s, %s",
+				rule.Path, RateLimitByIP, RateLimitByUser, RateLimitByRoute)
+		}
+		if rule.Limit.Rate <= 0 || rule.Limit.Burst <= 0 {
+			return fmt.Errorf("rate limit of path %s must have a positive rate and burst", rule.Path)
+		}
+	}
+
+	return nil
+}
+
+// match 返回匹配请求方法和路径的前缀最长的规则，前缀相同时优先返回指定了方法的规则.
+func (c RateLimitConfig) match(method, path string) (RateLimitRule, bool) {
+	var matched RateLimitRule
+	var ok bool
+	for _, rule := range c.Rules {
+		if rule.Method != "" && rule.Method != method {
+			continue
+		}
+		prefix := strings.TrimSuffix(rule.Path, "/")
+		if path != prefix && !strings.HasPrefix(path, prefix+"/") {
+			continue
+		}
+		if !ok || len(rule.Path) > len(matched.Path) ||
+			(len(rule.Path) == len(matched.Path) && rule.Method != "") {
+			matched, ok = rule, true
+		}
+	}
+
+	return matched, ok
+}
+
+// rateLimitConfig 保存当前生效的限流配置，类型为 RateLimitConfig.
+var rateLimitConfig atomic.Value
+
+func init() {
+	rateLimitConfig.Store(RateLimitConfig{Store: ratelimit.NewMemoryStore(), FailOpen: true})
+}
+
+// SetRateLimitConfig 校验并替换限流中间件的配置，对之后到达的请求生效.
+func SetRateLimitConfig(config RateLimitConfig) error {
+	if err := config.Validate(); err != nil {
+		return err
+	}
+	if config.Store == nil {
+		config.Store = ratelimit.NewMemoryStore()
+	}
+	rateLimitConfig.Store(config)
+
+	return nil
+}
+
+// RateLimit 是一个 Gin 中间件，使用令牌桶对请求限流，使用 SetRateLimitConfig 设置的配置.
+// 按用户限流的规则需要在认证中间件之后安装 AuthenticatedRateLimit 才能生效.
+func RateLimit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get(rateLimitedKey); !ok {
+			c.Set(rateLimitedKey, false)
+		}
+		rateLimit(c)
+	}
+}
+
+// AuthenticatedRateLimit 是一个 Gin 中间件，安装在认证中间件之后，使按用户限流的规则生效.
+// 只有启用了 ratelimit 中间件时才会限流.
+func AuthenticatedRateLimit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := c.Get(rateLimitedKey); !ok {
+			return
+		}
+		rateLimit(c)
+	}
+}
+
+func rateLimit(c *gin.Context) {
+	if c.GetBool(rateLimitedKey) {
+		return
+	}
+
+	config, _ := rateLimitConfig.Load().(RateLimitConfig)
+	rule, ok := config.match(c.Request.Method, c.Request.URL.Path)
+	if !ok {
+		return
+	}
+
+	var key string
+	switch rule.Key {
+	case RateLimitByIP:
+		key = iputil.RemoteIP(c.Request)
+	case RateLimitByUser:
+		key = c.GetString(UsernameKey)
+		if key == "" {
+			return
+		}
+	}
+	c.Set(rateLimitedKey, true)
+
+	result, err := config.Store.Take(c, rule.Method+rule.Path+":"+rule.Key+":"+key, rule.Limit)
+	if err != nil {
+		if config.FailOpen {
+			log.C(c).Warnf("Rate limit store is unavailable, skip rate limiting: %s", err.Error())
+			return
+		}
+		core.WriteResponse(c, errors.WithCode(code.ErrDatabase, "rate limit store is unavailable: %s", err.Error()), nil)
+		c.Abort()
+		return
+	}
+
+	c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.ResetAfter)))
+	if result.Allowed {
+		return
+	}
+
+	c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+	core.WriteResponse(c, errors.WithCode(code.ErrTooManyRequests, "rate limit exceeded for %s", rule.Path), nil)
+	c.Abort()
+}
+
+// ceilSeconds 将时间向上取整为秒，用于 Retry-After 等以秒为单位的响应头.
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,188 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	defer func() { _ = SetRateLimitConfig(RateLimitConfig{}) }()
+
+	err := SetRateLimitConfig(RateLimitConfig{Rules: []RateLimitRule{
+		{Path: "/login", Key: RateLimitByIP, Limit: ratelimit.Limit{Rate: 0.1, Burst: 2}},
+		{Path: "/v1", Key: RateLimitByUser, Limit: ratelimit.Limit{Rate: 0.1, Burst: 1}},
+	}})
+	if err != nil {
+		t.Fatalf("SetRateLimitConfig() error = %v", err)
+	}
+
+	auth := func(c *gin.Context) {
+		if user := c.GetHeader("X-User"); user != "" {
+			c.Set(UsernameKey, user)
+		}
+	}
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+
+	g := gin.New()
+	g.Use(RateLimit())
+	g.POST("/login", ok)
+	g.GET("/v1/secrets", auth, AuthenticatedRateLimit(), ok)
+	g.GET("/v1/loginx", ok)
+
+	serve := func(method, path, ip, user string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("X-Real-IP", ip)
+		req.Header.Set("X-User", user)
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, remaining := range []string{"1", "0"} {
+		w := serve(http.MethodPost, "/login", "10.0.0.1", "")
+		if w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Remaining") != remaining {
+			t.Fatalf("login = %d remaining %q, want 200 remaining %s",
+				w.Code, w.Header().Get("X-RateLimit-Remaining"), remaining)
+		}
+	}
+	w := serve(http.MethodPost, "/login", "10.0.0.1", "")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "10" ||
+		w.Header().Get("X-RateLimit-Limit") != "2" {
+		t.Errorf("login over limit = %d, headers %v, want 429 with Retry-After 10", w.Code, w.Header())
+	}
+	if w := serve(http.MethodPost, "/login", "10.0.0.2", ""); w.Code != http.StatusOK {
+		t.Errorf("login from another ip = %d, want 200", w.Code)
+	}
+
+	// 每个用户只计数一次，未认证的请求不受按用户限流的规则限制
+	if w := serve(http.MethodGet, "/v1/secrets", "10.0.0.1", "alice"); w.Code != http.StatusOK {
+		t.Errorf("first request of alice = %d, want 200", w.Code)
+	}
+	if w := serve(http.MethodGet, "/v1/secrets", "10.0.0.1", "alice"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("second request of alice = %d, want 429", w.Code)
+	}
+	if w := serve(http.MethodGet, "/v1/secrets", "10.0.0.1", "bob"); w.Code != http.StatusOK {
+		t.Errorf("first request of bob = %d, want 200", w.Code)
+	}
+	for i := 0; i < 3; i++ {
+		w := serve(http.MethodGet, "/v1/loginx", "10.0.0.1", "")
+		if w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Limit") != "" {
+			t.Errorf("anonymous request = %d, headers %v, want 200 without rate limit", w.Code, w.Header())
+		}
+	}
+}
+
+func TestAuthenticatedRateLimit_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	defer func() { _ = SetRateLimitConfig(RateLimitConfig{}) }()
+
+	err := SetRateLimitConfig(RateLimitConfig{Rules: []RateLimitRule{
+		{Path: "/v1", Key: RateLimitByRoute, Limit: ratelimit.Limit{Rate: 0.1, Burst: 1}},
+	}})
+	if err != nil {
+		t.Fatalf("SetRateLimitConfig() error = %v", err)
+	}
+
+	// 没有启用 ratelimit 中间件时，认证之后的限流不生效
+	g := gin.New()
+	g.GET("/v1/secrets", AuthenticatedRateLimit(), func(c *gin.Context) { c.Status(http.StatusOK) })
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/secrets", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("request %d = %d, want 200", i, w.Code)
+		}
+	}
+}
+
+func TestRateLimit_AnonymousRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	defer func() { _ = SetRateLimitConfig(RateLimitConfig{}) }()
+
+	err := SetRateLimitConfig(RateLimitConfig{Rules: []RateLimitRule{
+		{Path: "/v1", Key: RateLimitByUser, Limit: ratelimit.Limit{Rate: 0.1, Burst: 5}},
+		{Path: "/v1/users", Method: http.MethodPost, Key: RateLimitByIP, Limit: ratelimit.Limit{Rate: 0.1, Burst: 1}},
+	}})
+	if err != nil {
+		t.Fatalf("SetRateLimitConfig() error = %v", err)
+	}
+
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	g := gin.New()
+	g.Use(RateLimit())
+	g.POST("/v1/users", ok)
+	g.GET("/v1/users", ok)
+
+	serve := func(method string) int {
+		req := httptest.NewRequest(method, "/v1/users", nil)
+		req.Header.Set("X-Real-IP", "10.0.0.1")
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// 匿名注册按客户端 IP 限流，其他方法仍然使用按用户限流的规则
+	if code := serve(http.MethodPost); code != http.StatusOK {
+		t.Errorf("first anonymous signup = %d, want 200", code)
+	}
+	if code := serve(http.MethodPost); code != http.StatusTooManyRequests {
+		t.Errorf("second anonymous signup = %d, want 429", code)
+	}
+	if code := serve(http.MethodGet); code != http.StatusOK {
+		t.Errorf("anonymous list = %d, want 200", code)
+	}
+}
+
+// failingStore 模拟不可用的令牌桶存储.
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("connection refused")
+}
+
+func TestRateLimit_StoreUnavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	defer func() { _ = SetRateLimitConfig(RateLimitConfig{}) }()
+
+	g := gin.New()
+	g.Use(RateLimit())
+	g.POST("/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, tt := range []struct {
+		failOpen bool
+		want     int
+	}{
+		{failOpen: true, want: http.StatusOK},
+		{failOpen: false, want: http.StatusInternalServerError},
+	} {
+		err := SetRateLimitConfig(RateLimitConfig{
+			Rules: []RateLimitRule{
+				{Path: "/login", Key: RateLimitByIP, Limit: ratelimit.Limit{Rate: 0.1, Burst: 1}},
+			},
+			Store:    failingStore{},
+			FailOpen: tt.failOpen,
+		})
+		if err != nil {
+			t.Fatalf("SetRateLimitConfig() error = %v", err)
+		}
+
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", nil))
+		if w.Code != tt.want {
+			t.Errorf("fail-open %v: login = %d, want %d", tt.failOpen, w.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ory/ladon"
+	"gorm.io/gorm"
+
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+	"github.com/changaolee/skeleton/pkg/util/idutil"
+)
+
+// AuthzPolicy 定义了 ladon 授权策略.
+type AuthzPolicy struct {
+	ladon.DefaultPolicy
+}
+
+// String 返回 ladon 授权策略的 JSON 字符串.
+func (ap AuthzPolicy) String() string {
+	data, _ := json.Marshal(ap)
+
+	return string(data)
+}
+
+// Policy 是数据库中 policy 记录 struct 格式的映射.
+type Policy struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Username          string      `json:"username"         gorm:"column:username"     validate:"omitempty"`
+	Policy            AuthzPolicy `json:"policy,omitempty" gorm:"-"                   validate:"omitempty"`
+	PolicyShadow      string      `json:"-"                gorm:"column:policyShadow" validate:"omitempty"`
+}
+
+// PolicyList 是 policy 记录的列表.
+type PolicyList struct {
+	metav1.ListMeta `json:",inline"`
+	Items           []*Policy `json:"items"`
+}
+
+// TableName 用来指定映射的 MySQL 表名.
+func (p *Policy) TableName() string {
+	return "policy"
+}
+
+// BeforeCreate 在创建数据库记录之前序列化授权策略.
+func (p *Policy) BeforeCreate(tx *gorm.DB) error {
+	p.Policy.ID = p.Name
+	p.PolicyShadow = p.Policy.String()
+
+	return nil
+}
+
+// AfterCreate 在创建数据库记录之后更新资源 ID.
+func (p *Policy) AfterCreate(tx *gorm.DB) error {
+	p.InstanceID = idutil.GetInstanceID(p.ID, "policy-")
+
+	return tx.Save(p).Error
+}
+
+// BeforeUpdate 在更新数据库记录之前序列化授权策略.
+func (p *Policy) BeforeUpdate(tx *gorm.DB) error {
+	p.Policy.ID = p.Name
+	p.PolicyShadow = p.Policy.String()
+
+	return nil
+}
+
+// AfterFind 在查询数据库记录之后反序列化授权策略.
+func (p *Policy) AfterFind(tx *gorm.DB) error {
+	if err := json.Unmarshal([]byte(p.PolicyShadow), &p.Policy); err != nil {
+		return fmt.Errorf("failed to unmarshal policyShadow: %w", err)
+	}
+
+	return nil
+}
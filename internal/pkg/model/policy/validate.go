@@ -0,0 +1,53 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package policy
+
+import (
+	"github.com/ory/ladon"
+
+	"github.com/changaolee/skeleton/pkg/validation"
+	"github.com/changaolee/skeleton/pkg/validation/field"
+)
+
+// Validate 检查一个 policy 对象是否合法.
+func (p *Policy) Validate() field.ErrorList {
+	val := validation.NewValidator(p)
+	allErrs := val.Validate()
+
+	return append(allErrs, p.Policy.Validate(field.NewPath("policy"))...)
+}
+
+// Validate 检查 ladon 授权策略是否合法.
+func (ap *AuthzPolicy) Validate(fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+
+	if ap.Effect != ladon.AllowAccess && ap.Effect != ladon.DenyAccess {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("effect"), ap.Effect,
+			[]string{ladon.AllowAccess, ladon.DenyAccess}))
+	}
+	if len(ap.Subjects) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("subjects"), "must not be empty"))
+	}
+	if len(ap.Resources) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("resources"), "must not be empty"))
+	}
+	if len(ap.Actions) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("actions"), "must not be empty"))
+	}
+
+	for key, cond := range ap.Conditions {
+		if cond == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("conditions").Key(key), ""))
+			continue
+		}
+		if _, ok := ladon.ConditionFactories[cond.GetName()]; !ok {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("conditions").Key(key), cond.GetName(),
+				"unknown ladon condition type"))
+		}
+	}
+
+	return allErrs
+}
@@ -0,0 +1,41 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package secret
+
+import (
+	"gorm.io/gorm"
+
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+	"github.com/changaolee/skeleton/pkg/util/idutil"
+)
+
+// Secret 是数据库中 secret 记录 struct 格式的映射.
+type Secret struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Username          string `json:"username"    gorm:"column:username"    validate:"omitempty"`
+	SecretID          string `json:"secretID"    gorm:"column:secretID"    validate:"omitempty"`
+	SecretKey         string `json:"secretKey"   gorm:"column:secretKey"   validate:"omitempty"`
+	Expires           int64  `json:"expires"     gorm:"column:expires"     validate:"omitempty"`
+	Description       string `json:"description" gorm:"column:description" validate:"description"`
+}
+
+// SecretList 是 secret 记录的列表.
+type SecretList struct {
+	metav1.ListMeta `json:",inline"`
+	Items           []*Secret `json:"items"`
+}
+
+// TableName 用来指定映射的 MySQL 表名.
+func (s *Secret) TableName() string {
+	return "secret"
+}
+
+// AfterCreate 在创建数据库记录之后更新资源 ID.
+func (s *Secret) AfterCreate(tx *gorm.DB) error {
+	s.InstanceID = idutil.GetInstanceID(s.ID, "secret-")
+
+	return tx.Save(s).Error
+}
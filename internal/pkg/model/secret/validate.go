@@ -0,0 +1,23 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package secret
+
+import (
+	"github.com/changaolee/skeleton/pkg/validation"
+	"github.com/changaolee/skeleton/pkg/validation/field"
+)
+
+// Validate 检查一个 secret 对象是否合法.
+func (s *Secret) Validate() field.ErrorList {
+	val := validation.NewValidator(s)
+	allErrs := val.Validate()
+
+	if s.Expires < 0 {
+		allErrs = append(allErrs, field.Invalid(field.NewPath("expires"), s.Expires, "must be greater than or equal to 0"))
+	}
+
+	return allErrs
+}
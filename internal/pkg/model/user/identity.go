@@ -0,0 +1,23 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package user
+
+import "time"
+
+// Identity 是数据库中 user_identity 记录 struct 格式的映射，表示用户与外部身份提供方中身份的关联.
+// Provider 和 Subject 唯一确定一个外部身份，一个用户可以关联多个外部身份.
+type Identity struct {
+	ID        uint64    `json:"id,omitempty" gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	Username  string    `json:"username"     gorm:"column:username"`
+	Provider  string    `json:"provider"     gorm:"column:provider"`
+	Subject   string    `json:"subject"      gorm:"column:subject"`
+	CreatedAt time.Time `json:"createdAt"    gorm:"column:createdAt"`
+}
+
+// TableName 用来指定映射的 MySQL 表名.
+func (i *Identity) TableName() string {
+	return "user_identity"
+}
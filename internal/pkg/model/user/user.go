@@ -17,17 +17,94 @@ import (
 	"github.com/changaolee/skeleton/pkg/auth"
 )
 
+// 用户状态.
+const (
+	// StatusDisabled 表示用户不可用.
+	StatusDisabled = 0
+	// StatusActive 表示用户可用.
+	StatusActive = 1
+	// StatusUnverified 表示用户的邮箱尚未验证，开启邮箱验证时无法登录.
+	StatusUnverified = 2
+)
+
 // User 是数据库中 user 记录 struct 格式的映射.
 type User struct {
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	Status            int       `json:"status"             gorm:"column:status"   validate:"omitempty"`
-	Nickname          string    `json:"nickname"           gorm:"column:nickname" validate:"required,min=1,max=30"`
-	Password          string    `json:"password,omitempty" gorm:"column:password" validate:"required"`
-	Email             string    `json:"email"              gorm:"column:email"    validate:"required,email,min=1,max=100"`
-	Phone             string    `json:"phone"              gorm:"column:phone"    validate:"omitempty"`
-	IsAdmin           int       `json:"isAdmin,omitempty"  gorm:"column:isAdmin"  validate:"omitempty"`
-	TotalPolicy       int64     `json:"totalPolicy"        gorm:"-"               validate:"omitempty"`
-	LoginAt           time.Time `json:"loginAt,omitempty"  gorm:"column:loginAt"`
+	Status            int        `json:"status"                gorm:"column:status"           validate:"omitempty"`
+	Nickname          string     `json:"nickname"              gorm:"column:nickname"         validate:"required,min=1,max=30"`
+	Password          string     `json:"password,omitempty"    gorm:"column:password"         validate:"required"`
+	Email             string     `json:"email"                 gorm:"column:email"            validate:"required,email,min=1,max=100"`
+	Phone             string     `json:"phone"                 gorm:"column:phone"            validate:"omitempty"`
+	IsAdmin           int        `json:"isAdmin,omitempty"     gorm:"column:isAdmin"          validate:"omitempty"`
+	TotalPolicy       int64      `json:"totalPolicy"           gorm:"-"                       validate:"omitempty"`
+	LoginAt           time.Time  `json:"loginAt,omitempty"     gorm:"column:loginAt"`
+	LoginIP           string     `json:"loginIP,omitempty"     gorm:"column:loginIP"`
+	FailedLoginCount  int        `json:"failedLoginCount"      gorm:"column:failedLoginCount"`
+	LockedUntil       *time.Time `json:"lockedUntil,omitempty" gorm:"column:lockedUntil"`
+	MFAEnabled        int        `json:"mfaEnabled"            gorm:"column:mfaEnabled"`
+	TOTPSecret        string     `json:"-"                     gorm:"column:totpSecret"`
+	TOTPLastStep      int64      `json:"-"                     gorm:"column:totpLastStep"`
+	RecoveryCodes     []string   `json:"-"                     gorm:"column:recoveryCodes;serializer:json"`
+	PasswordChangedAt *time.Time `json:"passwordChangedAt"     gorm:"column:passwordChangedAt"`
+	PasswordHistory   []string   `json:"-"                     gorm:"column:passwordHistory;serializer:json"`
+	EmailVerifiedAt   *time.Time `json:"emailVerifiedAt"       gorm:"column:emailVerifiedAt"`
+}
+
+// UserList 是 user 记录的列表.
+type UserList struct {
+	metav1.ListMeta `json:",inline"`
+	Items           []*User `json:"items"`
+}
+
+// ChangePasswordRequest 定义了修改密码接口的请求参数.
+type ChangePasswordRequest struct {
+	// OldPassword 为用户当前的密码.
+	OldPassword string `json:"oldPassword" binding:"required"`
+
+	// NewPassword 为用户的新密码.
+	NewPassword string `json:"newPassword" binding:"required"`
+}
+
+// MFAEnrollment 定义了开启两步验证接口的返回值.
+type MFAEnrollment struct {
+	// Secret 为 TOTP 密钥，用于手动添加到身份验证器应用.
+	Secret string `json:"secret"`
+
+	// URI 为 otpauth URI，用于生成二维码.
+	URI string `json:"uri"`
+}
+
+// MFACodeRequest 定义了需要提供两步验证码的接口的请求参数.
+type MFACodeRequest struct {
+	// Code 为身份验证器应用生成的验证码或恢复码.
+	Code string `json:"code" binding:"required"`
+}
+
+// MFARecoveryCodes 定义了确认开启两步验证接口的返回值.
+type MFARecoveryCodes struct {
+	// RecoveryCodes 为恢复码，每个只能使用一次，只在开启两步验证时返回一次.
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// AccountMailRequest 定义了请求发送邮箱验证邮件和重置密码邮件接口的请求参数.
+type AccountMailRequest struct {
+	// Username 为需要验证邮箱或重置密码的用户名.
+	Username string `json:"username" binding:"required,username"`
+}
+
+// VerifyEmailRequest 定义了确认邮箱验证接口的请求参数.
+type VerifyEmailRequest struct {
+	// Token 为验证邮件中的令牌.
+	Token string `json:"token" binding:"required"`
+}
+
+// ResetPasswordRequest 定义了确认重置密码接口的请求参数.
+type ResetPasswordRequest struct {
+	// Token 为重置密码邮件中的令牌.
+	Token string `json:"token" binding:"required"`
+
+	// NewPassword 为用户的新密码.
+	NewPassword string `json:"newPassword" binding:"required"`
 }
 
 // TableName 用来指定映射的 MySQL 表名.
@@ -35,15 +112,6 @@ func (u *User) TableName() string {
 	return "user"
 }
 
-// BeforeCreate 在创建数据库记录之前加密明文密码.
-func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
-	u.Password, err = auth.Encrypt(u.Password)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
 // AfterCreate 在创建数据库记录之后更新资源 ID.
 func (u *User) AfterCreate(tx *gorm.DB) error {
 	u.InstanceID = idutil.GetInstanceID(u.ID, "user-")
@@ -51,6 +119,20 @@ func (u *User) AfterCreate(tx *gorm.DB) error {
 	return tx.Save(u).Error
 }
 
+// IsLocked 判断用户账户在 now 时刻是否处于锁定状态.
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
+// PasswordSetAt 返回用户最近一次设置密码的时间，没有记录时使用用户的创建时间.
+func (u *User) PasswordSetAt() time.Time {
+	if u.PasswordChangedAt != nil {
+		return *u.PasswordChangedAt
+	}
+
+	return u.CreatedAt
+}
+
 // Compare 验证用户密码是否正确.
 func (u *User) Compare(pwd string) error {
 	if err := auth.Compare(u.Password, pwd); err != nil {
@@ -5,14 +5,16 @@ import (
 	"github.com/changaolee/skeleton/pkg/validation/field"
 )
 
-// Validate 检查一个 user 对象是否合法.
+// Validate 检查一个 user 对象是否合法，密码是否符合密码策略由 apiserver 校验.
 func (u *User) Validate() field.ErrorList {
 	val := validation.NewValidator(u)
-	allErrs := val.Validate()
 
-	if err := validation.IsValidPassword(u.Password); err != nil {
-		allErrs = append(allErrs, field.Invalid(field.NewPath("password"), err.Error(), ""))
-	}
+	return val.Validate()
+}
+
+// ValidateUpdate 检查更新后的 user 对象是否合法，此时密码已加密，不再校验密码格式.
+func (u *User) ValidateUpdate() field.ErrorList {
+	val := validation.NewValidator(u)
 
-	return allErrs
+	return val.Validate()
 }
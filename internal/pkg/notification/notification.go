@@ -0,0 +1,94 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+// Package notification 定义了 skt-apiserver 与 skt-authz-server 之间通过 Redis 发布订阅传递的变更通知.
+package notification
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Command 定义一个新的通知类型.
+type Command string
+
+// 定义 Redis 订阅相关键和事件.
+const (
+	RedisPubSubChannel          = "skt.notifications"
+	NoticePolicyChanged Command = "PolicyChanged"
+	NoticeSecretChanged Command = "SecretChanged"
+)
+
+// Notification 是一个用于发布和订阅消息编码类型.
+type Notification struct {
+	Command       Command     `json:"command"`
+	Payload       string      `json:"payload"`
+	Timestamp     int64       `json:"timestamp"`
+	Nonce         string      `json:"nonce"`
+	Signature     string      `json:"signature"`
+	SignatureAlgo crypto.Hash `json:"algorithm"`
+}
+
+// Payload 描述了一次变更涉及的资源，序列化后作为 Notification 的 Payload.
+type Payload struct {
+	Action   string   `json:"action"`
+	Username string   `json:"username,omitempty"`
+	Names    []string `json:"names,omitempty"`
+}
+
+// 定义变更动作.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// New 使用给定的命令和负载创建一个使用 key 签名的 Notification.
+func New(command Command, payload Payload, key []byte) (*Notification, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	n := &Notification{
+		Command:   command,
+		Payload:   string(data),
+		Timestamp: time.Now().Unix(),
+		Nonce:     hex.EncodeToString(nonce),
+	}
+	n.Sign(key)
+
+	return n, nil
+}
+
+// Sign 使用 HMAC-SHA256 算法对通知的命令、负载、时间戳和随机数进行签名.
+func (n *Notification) Sign(key []byte) {
+	n.SignatureAlgo = crypto.SHA256
+	n.Signature = hex.EncodeToString(n.mac(key))
+}
+
+func (n *Notification) mac(key []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(string(n.Command)))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(n.Payload))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(strconv.FormatInt(n.Timestamp, 10)))
+	h.Write([]byte{'\n'})
+	h.Write([]byte(n.Nonce))
+
+	return h.Sum(nil)
+}
@@ -0,0 +1,83 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package notification
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+)
+
+// 定义通知校验失败的原因.
+var (
+	ErrUnsigned          = errors.New("notification is not signed")
+	ErrUnsupportedAlgo   = errors.New("unsupported notification signature algorithm")
+	ErrSignatureMismatch = errors.New("notification signature mismatch")
+	ErrExpired           = errors.New("notification timestamp is outside the accepted window")
+	ErrReplayed          = errors.New("notification nonce has already been seen")
+)
+
+// Verifier 用于校验通知的签名，并拒绝过期或重放的通知.
+type Verifier struct {
+	key    []byte
+	maxAge time.Duration
+	now    func() time.Time
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+}
+
+// NewVerifier 创建一个 Verifier，maxAge 为通知时间戳与当前时间允许的最大偏差.
+func NewVerifier(key []byte, maxAge time.Duration) *Verifier {
+	return &Verifier{
+		key:    key,
+		maxAge: maxAge,
+		now:    time.Now,
+		seen:   make(map[string]time.Time),
+	}
+}
+
+// Verify 校验通知，校验通过时记录其随机数，在 maxAge 窗口内再次出现将被视为重放.
+func (v *Verifier) Verify(n *Notification) error {
+	if n.Signature == "" || n.Nonce == "" {
+		return ErrUnsigned
+	}
+	if n.SignatureAlgo != crypto.SHA256 {
+		return ErrUnsupportedAlgo
+	}
+
+	signature, err := hex.DecodeString(n.Signature)
+	if err != nil || !hmac.Equal(signature, n.mac(v.key)) {
+		return ErrSignatureMismatch
+	}
+
+	now := v.now()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	for nonce, expireAt := range v.seen {
+		if now.After(expireAt) {
+			delete(v.seen, nonce)
+		}
+	}
+
+	ts := time.Unix(n.Timestamp, 0)
+	if ts.Before(now.Add(-v.maxAge)) || ts.After(now.Add(v.maxAge)) {
+		return ErrExpired
+	}
+
+	if _, ok := v.seen[n.Nonce]; ok {
+		return ErrReplayed
+	}
+	// 时间戳超出 maxAge 的通知会被直接拒绝，因此随机数只需保留到那一刻.
+	v.seen[n.Nonce] = ts.Add(v.maxAge)
+
+	return nil
+}
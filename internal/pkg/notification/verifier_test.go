@@ -0,0 +1,86 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package notification
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func newTestNotification(t *testing.T) *Notification {
+	t.Helper()
+
+	n, err := New(NoticePolicyChanged, Payload{Action: ActionUpdate, Username: "admin", Names: []string{"p0"}}, testKey)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return n
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(n *Notification)
+		key    []byte
+		want   error
+	}{
+		{name: "valid", mutate: func(n *Notification) {}, key: testKey},
+		{name: "unsigned", mutate: func(n *Notification) { n.Signature = "" }, key: testKey, want: ErrUnsigned},
+		{name: "no nonce", mutate: func(n *Notification) { n.Nonce = "" }, key: testKey, want: ErrUnsigned},
+		{name: "wrong key", mutate: func(n *Notification) {}, key: []byte("another-key"), want: ErrSignatureMismatch},
+		{
+			name:   "tampered payload",
+			mutate: func(n *Notification) { n.Payload = `{"action":"delete"}` },
+			key:    testKey,
+			want:   ErrSignatureMismatch,
+		},
+		{
+			name: "expired",
+			mutate: func(n *Notification) {
+				n.Timestamp = time.Now().Add(-time.Hour).Unix()
+				n.Sign(testKey)
+			},
+			key:  testKey,
+			want: ErrExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := newTestNotification(t)
+			tt.mutate(n)
+
+			err := NewVerifier(tt.key, time.Minute).Verify(n)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifier_VerifyReplay(t *testing.T) {
+	v := NewVerifier(testKey, time.Minute)
+	n := newTestNotification(t)
+
+	if err := v.Verify(n); err != nil {
+		t.Fatalf("first Verify() error = %v", err)
+	}
+	if err := v.Verify(n); !errors.Is(err, ErrReplayed) {
+		t.Fatalf("replayed Verify() error = %v, want %v", err, ErrReplayed)
+	}
+
+	// 超出窗口后随机数被清理，但通知本身也会因过期而被拒绝.
+	v.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if err := v.Verify(n); !errors.Is(err, ErrExpired) {
+		t.Fatalf("late Verify() error = %v, want %v", err, ErrExpired)
+	}
+	if len(v.seen) != 0 {
+		t.Errorf("seen nonces = %d, want 0", len(v.seen))
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
+	"github.com/changaolee/skeleton/internal/pkg/server"
+)
+
+// CORSOptions 定义了 cors 中间件的跨域访问配置.
+type CORSOptions struct {
+	AllowOrigins     []string      `json:"allow-origins"     mapstructure:"allow-origins"`
+	AllowMethods     []string      `json:"allow-methods"     mapstructure:"allow-methods"`
+	AllowHeaders     []string      `json:"allow-headers"     mapstructure:"allow-headers"`
+	ExposeHeaders    []string      `json:"expose-headers"    mapstructure:"expose-headers"`
+	AllowCredentials bool          `json:"allow-credentials" mapstructure:"allow-credentials"`
+	MaxAge           time.Duration `json:"max-age"           mapstructure:"max-age"`
+}
+
+// NewCORSOptions 创建一个默认值的跨域访问选项，默认不允许任何跨域访问.
+func NewCORSOptions() *CORSOptions {
+	defaults := middleware.DefaultCorsConfig()
+
+	return &CORSOptions{
+		AllowOrigins:     defaults.AllowOrigins,
+		AllowMethods:     defaults.AllowMethods,
+		AllowHeaders:     defaults.AllowHeaders,
+		ExposeHeaders:    defaults.ExposeHeaders,
+		AllowCredentials: defaults.AllowCredentials,
+		MaxAge:           defaults.MaxAge,
+	}
+}
+
+// CorsConfig 将当前选项转换为 cors 中间件的配置.
+func (o *CORSOptions) CorsConfig() middleware.CorsConfig {
+	return middleware.CorsConfig{
+		AllowOrigins:     o.AllowOrigins,
+		AllowMethods:     o.AllowMethods,
+		AllowHeaders:     o.AllowHeaders,
+		ExposeHeaders:    o.ExposeHeaders,
+		AllowCredentials: o.AllowCredentials,
+		MaxAge:           o.MaxAge,
+	}
+}
+
+// ApplyTo 将当前选项绑定到 Config 中.
+func (o *CORSOptions) ApplyTo(c *server.Config) error {
+	c.Cors = o.CorsConfig()
+
+	return nil
+}
+
+// Validate 验证跨域访问选项.
+func (o *CORSOptions) Validate() []error {
+	var errs []error
+
+	if err := o.CorsConfig().Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid --cors options: %w", err))
+	}
+
+	return errs
+}
+
+// AddFlags 向指定 FlagSet 中添加跨域访问相关标志.
+func (o *CORSOptions) AddFlags(fs *pflag.FlagSet) {
+	if fs == nil {
+		return
+	}
+
+	fs.StringSliceVar(&o.AllowOrigins, "cors.allow-origins", o.AllowOrigins, ""+
+		"List of origins allowed to make cross-origin requests, e.g. https://dashboard.example.com. "+
+		"Use https://*.example.com to allow all subdomains, or * to allow all origins "+
+		"(not allowed together with --cors.allow-credentials). Empty means no cross-origin requests are allowed.")
+	fs.StringSliceVar(&o.AllowMethods, "cors.allow-methods", o.AllowMethods,
+		"List of methods allowed in cross-origin requests.")
+	fs.StringSliceVar(&o.AllowHeaders, "cors.allow-headers", o.AllowHeaders,
+		"List of non simple headers allowed in cross-origin requests.")
+	fs.StringSliceVar(&o.ExposeHeaders, "cors.expose-headers", o.ExposeHeaders,
+		"List of response headers exposed to the browser.")
+	fs.BoolVar(&o.AllowCredentials, "cors.allow-credentials", o.AllowCredentials,
+		"Allow cross-origin requests to include credentials like cookies and authorization headers.")
+	fs.DurationVar(&o.MaxAge, "cors.max-age", o.MaxAge,
+		"How long the results of a preflight request can be cached.")
+}
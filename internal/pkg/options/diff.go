@@ -0,0 +1,87 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"reflect"
+	"strings"
+)
+
+// ChangedFields 比较两个相同类型的选项，返回值发生变化的字段，字段名为以 . 连接的 mapstructure 标签，
+// 与配置文件中的 key 一致，例如 log.level.
+func ChangedFields(old, new interface{}) []string {
+	return changedFields("", reflect.ValueOf(old), reflect.ValueOf(new))
+}
+
+func changedFields(prefix string, old, new reflect.Value) []string {
+	for old.Kind() == reflect.Ptr && new.Kind() == reflect.Ptr {
+		if old.IsNil() || new.IsNil() {
+			break
+		}
+		old, new = old.Elem(), new.Elem()
+	}
+
+	if old.Kind() != reflect.Struct || new.Kind() != reflect.Struct {
+		if reflect.DeepEqual(old.Interface(), new.Interface()) {
+			return nil
+		}
+		return []string{prefix}
+	}
+
+	var changed []string
+	for i := 0; i < old.NumField(); i++ {
+		field := old.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("mapstructure"), ",")[0]
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		changed = append(changed, changedFields(name, old.Field(i), new.Field(i))...)
+	}
+
+	return changed
+}
+
+// ApplyChangedFields 比较 old 和 new，对 appliers 中存在的变更字段调用对应的函数应用变更，
+// 返回已应用的字段和需要重启才能生效的字段. appliers 的 key 可以是整个选项组，例如 cors 匹配 cors.max-age，
+// 同一个函数在一次调用中只执行一次.
+func ApplyChangedFields(old, new interface{}, appliers map[string]func()) (applied, rejected []string) {
+	called := make(map[string]bool)
+	for _, field := range ChangedFields(old, new) {
+		key, ok := findApplier(field, appliers)
+		if !ok {
+			rejected = append(rejected, field)
+			continue
+		}
+		if !called[key] {
+			appliers[key]()
+			called[key] = true
+		}
+		applied = append(applied, field)
+	}
+
+	return applied, rejected
+}
+
+func findApplier(field string, appliers map[string]func()) (string, bool) {
+	for {
+		if _, ok := appliers[field]; ok {
+			return field, true
+		}
+		i := strings.LastIndex(field, ".")
+		if i < 0 {
+			return "", false
+		}
+		field = field[:i]
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+func TestChangedFields(t *testing.T) {
+	type testOptions struct {
+		Server *ServerRunOptions `mapstructure:"server"`
+		MySQL  *MySQLOptions     `mapstructure:"mysql"`
+		Log    *log.Options      `mapstructure:"log"`
+	}
+	newOptions := func() *testOptions {
+		return &testOptions{Server: NewServerRunOptions(), MySQL: NewMySQLOptions(), Log: log.NewOptions()}
+	}
+
+	old, new := newOptions(), newOptions()
+	if changed := ChangedFields(old, new); len(changed) != 0 {
+		t.Errorf("ChangedFields() = %v, want none", changed)
+	}
+
+	new.Server.Middlewares = []string{"recovery", "logger"}
+	new.MySQL.MaxIdleConnections++
+	new.Log.Level = "debug"
+	new.Log.OutputPaths = []string{"stdout", "/var/log/skt/skt-apiserver.log"}
+	new.Server.ShutdownTimeout = old.Server.ShutdownTimeout + time.Second
+
+	want := []string{
+		"server.shutdown-timeout",
+		"server.middlewares",
+		"mysql.max-idle-connections",
+		"log.level",
+		"log.output-paths",
+	}
+	if changed := ChangedFields(old, new); !reflect.DeepEqual(changed, want) {
+		t.Errorf("ChangedFields() = %v, want %v", changed, want)
+	}
+}
+
+func TestApplyChangedFields(t *testing.T) {
+	type testOptions struct {
+		Server *ServerRunOptions `mapstructure:"server"`
+		Log    *log.Options      `mapstructure:"log"`
+	}
+	old := &testOptions{Server: NewServerRunOptions(), Log: log.NewOptions()}
+	new := &testOptions{Server: NewServerRunOptions(), Log: log.NewOptions()}
+	new.Server.ShutdownTimeout += time.Second
+	new.Log.Level = "debug"
+	new.Log.Format = "json"
+
+	var calls int
+	applied, rejected := ApplyChangedFields(old, new, map[string]func(){
+		"log":             func() { calls++ },
+		"server.shutdown": func() { t.Error("applier matched a field by string prefix") },
+	})
+
+	if want := []string{"log.level", "log.format"}; calls != 1 || !reflect.DeepEqual(applied, want) {
+		t.Errorf("applied = %v (%d calls), want %v (1 call)", applied, calls, want)
+	}
+	if want := []string{"server.shutdown-timeout"}; !reflect.DeepEqual(rejected, want) {
+		t.Errorf("rejected = %v, want %v", rejected, want)
+	}
+}
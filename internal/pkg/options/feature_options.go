@@ -0,0 +1,72 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"fmt"
+
+	"github.com/spf13/pflag"
+
+	"github.com/changaolee/skeleton/internal/pkg/server"
+)
+
+// FeatureOptions 定义了 API 服务器可选功能的开关.
+type FeatureOptions struct {
+	EnableProfiling bool   `json:"profiling"        mapstructure:"profiling"`
+	ProfilingAccess string `json:"profiling-access" mapstructure:"profiling-access"`
+	EnableMetrics   bool   `json:"enable-metrics"   mapstructure:"enable-metrics"`
+}
+
+// NewFeatureOptions 创建一个默认值的功能开关选项.
+func NewFeatureOptions() *FeatureOptions {
+	defaults := server.NewConfig()
+
+	return &FeatureOptions{
+		EnableMetrics:   defaults.EnableMetrics,
+		EnableProfiling: defaults.EnableProfiling,
+		ProfilingAccess: defaults.ProfilingAccess,
+	}
+}
+
+// ApplyTo 将当前选项绑定到 Config 中.
+func (o *FeatureOptions) ApplyTo(c *server.Config) error {
+	c.EnableProfiling = o.EnableProfiling
+	c.ProfilingAccess = o.ProfilingAccess
+	c.EnableMetrics = o.EnableMetrics
+
+	return nil
+}
+
+// Validate 验证功能开关选项.
+func (o *FeatureOptions) Validate() []error {
+	var errs []error
+
+	switch o.ProfilingAccess {
+	case server.ProfilingAccessAll, server.ProfilingAccessInsecure, server.ProfilingAccessAdmin:
+	default:
+		errs = append(errs, fmt.Errorf("--feature.profiling-access must be one of: %s, %s, %s",
+			server.ProfilingAccessAll, server.ProfilingAccessInsecure, server.ProfilingAccessAdmin))
+	}
+
+	return errs
+}
+
+// AddFlags 向指定 FlagSet 中添加功能开关相关标志.
+func (o *FeatureOptions) AddFlags(fs *pflag.FlagSet) {
+	if fs == nil {
+		return
+	}
+
+	fs.BoolVar(&o.EnableProfiling, "feature.profiling", o.EnableProfiling,
+		"Enable profiling via web interface host:port/debug/pprof/")
+
+	fs.StringVar(&o.ProfilingAccess, "feature.profiling-access", o.ProfilingAccess, ""+
+		"Restrict access to /debug/pprof/. Supported values: all, insecure (only the insecure listener), "+
+		"admin (only authenticated administrators, if supported by the server).")
+
+	fs.BoolVar(&o.EnableMetrics, "feature.enable-metrics", o.EnableMetrics,
+		"Enables metrics on the apiserver at /metrics")
+}
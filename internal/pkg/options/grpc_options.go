@@ -0,0 +1,81 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/spf13/pflag"
+)
+
+// GRPCOptions 定义了 gRPC 服务配置.
+type GRPCOptions struct {
+	BindAddress string  `json:"bind-address" mapstructure:"bind-address"`
+	BindPort    int     `json:"bind-port"    mapstructure:"bind-port"`
+	MaxMsgSize  int     `json:"max-msg-size" mapstructure:"max-msg-size"`
+	ServerCert  CertKey `json:"tls"          mapstructure:"tls"`
+}
+
+// NewGRPCOptions 创建了一个默认 gRPC 服务配置.
+func NewGRPCOptions() *GRPCOptions {
+	return &GRPCOptions{
+		BindAddress: "0.0.0.0",
+		BindPort:    8081,
+		MaxMsgSize:  4 * 1024 * 1024,
+	}
+}
+
+// Address 将 IP 和 port 拼接为一个地址字符串，如：0.0.0.0:8081.
+func (s *GRPCOptions) Address() string {
+	return net.JoinHostPort(s.BindAddress, strconv.Itoa(s.BindPort))
+}
+
+// Validate 验证 gRPC 选项.
+func (s *GRPCOptions) Validate() []error {
+	var errors []error
+
+	if s.BindPort < 1 || s.BindPort > 65535 {
+		errors = append(
+			errors,
+			fmt.Errorf("--grpc.bind-port %v must be between 1 and 65535, inclusive", s.BindPort),
+		)
+	}
+
+	if s.MaxMsgSize <= 0 {
+		errors = append(errors, fmt.Errorf("--grpc.max-msg-size %v must be greater than 0", s.MaxMsgSize))
+	}
+
+	if s.ServerCert.CertFile == "" || s.ServerCert.KeyFile == "" {
+		errors = append(
+			errors,
+			fmt.Errorf("--grpc.tls.cert-file and --grpc.tls.private-key-file are required by the grpc server"),
+		)
+	}
+
+	return errors
+}
+
+// AddFlags 向指定 FlagSet 中添加 gRPC 选项相关标志.
+func (s *GRPCOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.BindAddress, "grpc.bind-address", s.BindAddress, ""+
+		"The IP address on which to serve the --grpc.bind-port "+
+		"(set to 0.0.0.0 for all IPv4 interfaces and :: for all IPv6 interfaces).")
+
+	fs.IntVar(&s.BindPort, "grpc.bind-port", s.BindPort, ""+
+		"The port on which to serve TLS gRPC requests. It is used by skt-authz-server "+
+		"to load secrets and policies.")
+
+	fs.IntVar(&s.MaxMsgSize, "grpc.max-msg-size", s.MaxMsgSize, "gRPC max message size in bytes.")
+
+	fs.StringVar(&s.ServerCert.CertFile, "grpc.tls.cert-file", s.ServerCert.CertFile, ""+
+		"File containing the default x509 Certificate for gRPC. (CA cert, if any, concatenated "+
+		"after server cert).")
+
+	fs.StringVar(&s.ServerCert.KeyFile, "grpc.tls.private-key-file", s.ServerCert.KeyFile, ""+
+		"File containing the default x509 private key matching --grpc.tls.cert-file.")
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/changaolee/skeleton/internal/pkg/jwks"
+	"github.com/changaolee/skeleton/internal/pkg/server"
+)
+
+// JwtOptions 包含与 JWT 认证相关的配置项.
+type JwtOptions struct {
+	Realm      string        `json:"realm"       mapstructure:"realm"`
+	Key        string        `json:"-"           mapstructure:"key"`
+	Timeout    time.Duration `json:"timeout"     mapstructure:"timeout"`
+	MaxRefresh time.Duration `json:"max-refresh" mapstructure:"max-refresh"`
+	// Keys 为签发和验证 token 使用的非对称密钥，设置后不再使用 Key 签发 token
+	Keys      []JwtKeyOptions `json:"keys"       mapstructure:"keys"`
+	ActiveKID string          `json:"active-kid" mapstructure:"active-kid"`
+}
+
+// JwtKeyOptions 定义了一个通过 kid 区分的非对称密钥，只设置公钥的密钥只能用于验证 token.
+type JwtKeyOptions struct {
+	ID             string `json:"kid"              mapstructure:"kid"`
+	PrivateKeyFile string `json:"private-key-file" mapstructure:"private-key-file"`
+	PublicKeyFile  string `json:"public-key-file"  mapstructure:"public-key-file"`
+}
+
+// NewJwtOptions 创建一个默认值的 JWT 选项.
+func NewJwtOptions() *JwtOptions {
+	defaults := server.NewConfig()
+
+	return &JwtOptions{
+		Realm:      defaults.Jwt.Realm,
+		Key:        defaults.Jwt.Key,
+		Timeout:    defaults.Jwt.Timeout,
+		MaxRefresh: defaults.Jwt.MaxRefresh,
+	}
+}
+
+// ApplyTo 将当前选项绑定到 Config 中.
+func (o *JwtOptions) ApplyTo(c *server.Config) error {
+	c.Jwt = &server.JwtInfo{
+		Realm:      o.Realm,
+		Key:        o.Key,
+		Timeout:    o.Timeout,
+		MaxRefresh: o.MaxRefresh,
+	}
+
+	return nil
+}
+
+// Validate 验证 JWT 选项.
+func (o *JwtOptions) Validate() []error {
+	var errs []error
+
+	if len(o.Keys) == 0 && o.Key == "" {
+		errs = append(errs, fmt.Errorf("--jwt.key must be specified when no jwt.keys are configured"))
+	}
+	if len(o.Keys) > 0 && o.ActiveKID == "" {
+		errs = append(errs, fmt.Errorf("--jwt.active-kid must be specified when jwt.keys are configured"))
+	}
+	if _, err := o.KeySet(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid jwt.keys: %w", err))
+	}
+	if o.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("--jwt.timeout must be greater than 0"))
+	}
+	if o.MaxRefresh < 0 {
+		errs = append(errs, fmt.Errorf("--jwt.max-refresh cannot be negative"))
+	}
+
+	return errs
+}
+
+// KeySet 读取非对称密钥，没有配置 Keys 时返回 nil.
+func (o *JwtOptions) KeySet() (*jwks.KeySet, error) {
+	if len(o.Keys) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]*jwks.Key, 0, len(o.Keys))
+	for _, k := range o.Keys {
+		if (k.PrivateKeyFile == "") == (k.PublicKeyFile == "") {
+			return nil, fmt.Errorf("key %s: exactly one of private-key-file and public-key-file must be specified", k.ID)
+		}
+
+		var key *jwks.Key
+		if k.PrivateKeyFile != "" {
+			data, err := os.ReadFile(k.PrivateKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			key, err = jwks.ParsePrivateKey(k.ID, data)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			data, err := os.ReadFile(k.PublicKeyFile)
+			if err != nil {
+				return nil, err
+			}
+			key, err = jwks.ParsePublicKey(k.ID, data)
+			if err != nil {
+				return nil, err
+			}
+		}
+		keys = append(keys, key)
+	}
+
+	return jwks.NewKeySet(o.ActiveKID, keys)
+}
+
+// AddFlags 向指定 FlagSet 中添加 JWT 相关标志.
+func (o *JwtOptions) AddFlags(fs *pflag.FlagSet) {
+	if fs == nil {
+		return
+	}
+
+	fs.StringVar(&o.Realm, "jwt.realm", o.Realm, "Realm name to display to the user.")
+	fs.StringVar(&o.Key, "jwt.key", o.Key, ""+
+		"Secret key used to sign jwt token with HS256 when no jwt.keys are configured. "+
+		"When jwt.keys are configured, HS256 tokens signed by this key are still accepted until they expire.")
+	fs.StringVar(&o.ActiveKID, "jwt.active-kid", o.ActiveKID, ""+
+		"The kid of the key in jwt.keys used to sign new tokens. "+
+		"The keys themselves can only be configured in the config file.")
+	fs.DurationVar(&o.Timeout, "jwt.timeout", o.Timeout, "JWT token timeout.")
+	fs.DurationVar(&o.MaxRefresh, "jwt.max-refresh", o.MaxRefresh, ""+
+		"This field allows clients to refresh their token until MaxRefresh has passed.")
+}
@@ -0,0 +1,56 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// minSigningKeyLength 为 HMAC 签名密钥的最小长度.
+const minSigningKeyLength = 32
+
+// NotificationOptions 定义了 skt-apiserver 与 skt-authz-server 之间变更通知的签名选项.
+type NotificationOptions struct {
+	SigningKey string        `json:"signing-key" mapstructure:"signing-key"`
+	MaxAge     time.Duration `json:"max-age"     mapstructure:"max-age"`
+}
+
+// NewNotificationOptions 创建一个默认值的变更通知选项实例.
+func NewNotificationOptions() *NotificationOptions {
+	return &NotificationOptions{
+		SigningKey: "",
+		MaxAge:     5 * time.Minute,
+	}
+}
+
+// Validate 验证变更通知选项.
+func (o *NotificationOptions) Validate() []error {
+	var errs []error
+
+	if len(o.SigningKey) < minSigningKeyLength {
+		errs = append(errs, fmt.Errorf("--notification.signing-key must be at least %d characters", minSigningKeyLength))
+	}
+
+	if o.MaxAge <= 0 {
+		errs = append(errs, fmt.Errorf("--notification.max-age must be greater than 0"))
+	}
+
+	return errs
+}
+
+// AddFlags 向指定 FlagSet 中添加变更通知选项相关标志.
+func (o *NotificationOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&o.SigningKey, "notification.signing-key", o.SigningKey, ""+
+		"Shared HMAC-SHA256 key used to sign and verify secret and policy change notifications. "+
+		"skt-apiserver and skt-authz-server must use the same key.")
+
+	fs.DurationVar(&o.MaxAge, "notification.max-age", o.MaxAge, ""+
+		"Maximum age of a change notification. Older notifications, or notifications "+
+		"whose nonce has already been seen within this window, are rejected as replays.")
+}
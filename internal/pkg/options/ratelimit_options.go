@@ -0,0 +1,140 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
+	"github.com/changaolee/skeleton/internal/pkg/ratelimit"
+	"github.com/changaolee/skeleton/internal/pkg/server"
+	"github.com/changaolee/skeleton/pkg/db"
+)
+
+// 定义令牌桶的存储后端.
+const (
+	RateLimitBackendMemory = "memory"
+	RateLimitBackendRedis  = "redis"
+)
+
+// rateLimitKeyPrefix 为保存在 Redis 中的令牌桶 key 的前缀.
+const rateLimitKeyPrefix = "skt:ratelimit:"
+
+// RateLimitRuleOptions 定义了一个路由分组的限流规则.
+type RateLimitRuleOptions struct {
+	Path     string        `json:"path"     mapstructure:"path"`
+	Method   string        `json:"method"   mapstructure:"method"`
+	Key      string        `json:"key"      mapstructure:"key"`
+	Requests int           `json:"requests" mapstructure:"requests"`
+	Period   time.Duration `json:"period"   mapstructure:"period"`
+	// Burst 为允许的最大突发请求数，为 0 时等于 Requests
+	Burst int `json:"burst" mapstructure:"burst"`
+}
+
+// RateLimitOptions 定义了 ratelimit 中间件的限流配置.
+type RateLimitOptions struct {
+	Backend  string                 `json:"backend"   mapstructure:"backend"`
+	FailOpen bool                   `json:"fail-open" mapstructure:"fail-open"`
+	Rules    []RateLimitRuleOptions `json:"rules"     mapstructure:"rules"`
+}
+
+// NewRateLimitOptions 创建一个默认值的限流选项，默认没有任何限流规则.
+func NewRateLimitOptions() *RateLimitOptions {
+	return &RateLimitOptions{
+		Backend:  RateLimitBackendMemory,
+		FailOpen: true,
+		Rules:    []RateLimitRuleOptions{},
+	}
+}
+
+// RateLimitConfig 将当前选项转换为 ratelimit 中间件的配置，不包含令牌桶的存储.
+func (o *RateLimitOptions) RateLimitConfig() middleware.RateLimitConfig {
+	rules := make([]middleware.RateLimitRule, 0, len(o.Rules))
+	for _, r := range o.Rules {
+		burst := r.Burst
+		if burst == 0 {
+			burst = r.Requests
+		}
+
+		var rate float64
+		if r.Period > 0 {
+			rate = float64(r.Requests) / r.Period.Seconds()
+		}
+
+		rules = append(rules, middleware.RateLimitRule{
+			Path:   r.Path,
+			Method: strings.ToUpper(r.Method),
+			Key:    r.Key,
+			Limit:  ratelimit.Limit{Rate: rate, Burst: burst},
+		})
+	}
+
+	return middleware.RateLimitConfig{Rules: rules, FailOpen: o.FailOpen}
+}
+
+// ApplyTo 将当前选项绑定到 Config 中，使用 redis 后端时基于 redisOpts 创建 Redis 客户端.
+func (o *RateLimitOptions) ApplyTo(c *server.Config, redisOpts *RedisOptions) error {
+	c.RateLimit = o.RateLimitConfig()
+	if o.Backend != RateLimitBackendRedis {
+		return nil
+	}
+
+	client, err := db.NewRedis(&db.RedisOptions{
+		Host:     redisOpts.Host,
+		Port:     redisOpts.Port,
+		Username: redisOpts.Username,
+		Password: redisOpts.Password,
+		Database: redisOpts.Database,
+	})
+	if err != nil {
+		return err
+	}
+	c.RateLimit.Store = ratelimit.NewRedisStore(client, rateLimitKeyPrefix)
+
+	return nil
+}
+
+// Validate 验证限流选项.
+func (o *RateLimitOptions) Validate() []error {
+	var errs []error
+
+	if o.Backend != RateLimitBackendMemory && o.Backend != RateLimitBackendRedis {
+		errs = append(errs, fmt.Errorf("--ratelimit.backend must be one of: %s, %s",
+			RateLimitBackendMemory, RateLimitBackendRedis))
+	}
+	for _, r := range o.Rules {
+		if r.Requests <= 0 || r.Period <= 0 {
+			errs = append(errs, fmt.Errorf("rate limit rule of path %s must have positive requests and period", r.Path))
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	if err := o.RateLimitConfig().Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid ratelimit options: %w", err))
+	}
+
+	return errs
+}
+
+// AddFlags 向指定 FlagSet 中添加限流相关标志，限流规则只能在配置文件中设置.
+func (o *RateLimitOptions) AddFlags(fs *pflag.FlagSet) {
+	if fs == nil {
+		return
+	}
+
+	fs.StringVar(&o.Backend, "ratelimit.backend", o.Backend, ""+
+		"Storage of token buckets, one of: memory, redis. "+
+		"Use redis to share rate limits across replicas, the redis.* options are used to connect to it.")
+	fs.BoolVar(&o.FailOpen, "ratelimit.fail-open", o.FailOpen, ""+
+		"Let requests through without rate limiting when the storage of token buckets is unavailable, "+
+		"otherwise reject them.")
+}
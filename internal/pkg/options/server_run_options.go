@@ -6,6 +6,9 @@
 package options
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/spf13/pflag"
 
 	"github.com/changaolee/skeleton/internal/pkg/server"
@@ -13,9 +16,13 @@ import (
 
 // ServerRunOptions 定义了一个通用 API 服务器配置.
 type ServerRunOptions struct {
-	Mode        string   `json:"mode"        mapstructure:"mode"`
-	Healthz     bool     `json:"healthz"     mapstructure:"healthz"`
-	Middlewares []string `json:"middlewares" mapstructure:"middlewares"`
+	Mode                string        `json:"mode"                   mapstructure:"mode"`
+	Healthz             bool          `json:"healthz"                mapstructure:"healthz"`
+	HealthCheckTimeout  time.Duration `json:"health-check-timeout"   mapstructure:"health-check-timeout"`
+	HealthCheckCacheTTL time.Duration `json:"health-check-cache-ttl" mapstructure:"health-check-cache-ttl"`
+	ShutdownTimeout     time.Duration `json:"shutdown-timeout"       mapstructure:"shutdown-timeout"`
+	ShutdownDelay       time.Duration `json:"shutdown-delay"         mapstructure:"shutdown-delay"`
+	Middlewares         []string      `json:"middlewares"            mapstructure:"middlewares"`
 }
 
 // NewServerRunOptions 创建了一个默认服务器配置.
@@ -23,9 +30,13 @@ func NewServerRunOptions() *ServerRunOptions {
 	defaults := server.NewConfig()
 
 	return &ServerRunOptions{
-		Mode:        defaults.Mode,
-		Healthz:     defaults.Healthz,
-		Middlewares: defaults.Middlewares,
+		Mode:                defaults.Mode,
+		Healthz:             defaults.Healthz,
+		HealthCheckTimeout:  defaults.HealthCheckTimeout,
+		HealthCheckCacheTTL: defaults.HealthCheckCacheTTL,
+		ShutdownTimeout:     defaults.ShutdownTimeout,
+		ShutdownDelay:       defaults.ShutdownDelay,
+		Middlewares:         defaults.Middlewares,
 	}
 }
 
@@ -33,6 +44,10 @@ func NewServerRunOptions() *ServerRunOptions {
 func (s *ServerRunOptions) ApplyTo(c *server.Config) error {
 	c.Mode = s.Mode
 	c.Healthz = s.Healthz
+	c.HealthCheckTimeout = s.HealthCheckTimeout
+	c.HealthCheckCacheTTL = s.HealthCheckCacheTTL
+	c.ShutdownTimeout = s.ShutdownTimeout
+	c.ShutdownDelay = s.ShutdownDelay
 	c.Middlewares = s.Middlewares
 
 	return nil
@@ -42,6 +57,19 @@ func (s *ServerRunOptions) ApplyTo(c *server.Config) error {
 func (s *ServerRunOptions) Validate() []error {
 	var errors []error
 
+	if s.HealthCheckTimeout <= 0 {
+		errors = append(errors, fmt.Errorf("--server.health-check-timeout must be greater than 0"))
+	}
+	if s.HealthCheckCacheTTL < 0 {
+		errors = append(errors, fmt.Errorf("--server.health-check-cache-ttl must not be negative"))
+	}
+	if s.ShutdownTimeout <= 0 {
+		errors = append(errors, fmt.Errorf("--server.shutdown-timeout must be greater than 0"))
+	}
+	if s.ShutdownDelay < 0 || s.ShutdownDelay >= s.ShutdownTimeout {
+		errors = append(errors, fmt.Errorf("--server.shutdown-delay must be non-negative and less than --server.shutdown-timeout"))
+	}
+
 	return errors
 }
 
@@ -51,7 +79,21 @@ func (s *ServerRunOptions) AddFlags(fs *pflag.FlagSet) {
 		"Start the server in a specified server mode. Supported server mode: debug, test, release.")
 
 	fs.BoolVar(&s.Healthz, "server.healthz", s.Healthz, ""+
-		"Add self readiness check and install /healthz router.")
+		"Add self readiness check and install /healthz, /livez and /readyz router.")
+
+	fs.DurationVar(&s.HealthCheckTimeout, "server.health-check-timeout", s.HealthCheckTimeout, ""+
+		"Timeout of each dependency check performed by /readyz.")
+
+	fs.DurationVar(&s.HealthCheckCacheTTL, "server.health-check-cache-ttl", s.HealthCheckCacheTTL, ""+
+		"How long /readyz caches the result of each dependency check.")
+
+	fs.DurationVar(&s.ShutdownTimeout, "server.shutdown-timeout", s.ShutdownTimeout, ""+
+		"Overall deadline of graceful shutdown, including draining in-flight requests, "+
+		"flushing buffered data and closing stores.")
+
+	fs.DurationVar(&s.ShutdownDelay, "server.shutdown-delay", s.ShutdownDelay, ""+
+		"How long to keep serving after /readyz starts failing on shutdown, "+
+		"so that load balancers can stop sending new requests.")
 
 	fs.StringSliceVar(&s.Middlewares, "server.middlewares", s.Middlewares, ""+
 		"List of allowed middlewares for server, comma separated. If this list is empty default middlewares will be used.")
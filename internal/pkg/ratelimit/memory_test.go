@@ -0,0 +1,59 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStore_Take(t *testing.T) {
+	now := time.Now()
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	// 每 2 秒生成一个令牌，最多突发 3 个请求
+	limit := Limit{Rate: 0.5, Burst: 3}
+	take := func(key string) Result {
+		t.Helper()
+		result, err := s.Take(context.Background(), key, limit)
+		if err != nil {
+			t.Fatalf("Take() error = %v", err)
+		}
+		return result
+	}
+
+	for i := 2; i >= 0; i-- {
+		if r := take("ip:10.0.0.1"); !r.Allowed || r.Remaining != i || r.Limit != 3 {
+			t.Fatalf("Take() = %+v, want allowed with %d remaining", r, i)
+		}
+	}
+
+	r := take("ip:10.0.0.1")
+	if r.Allowed || r.RetryAfter != 2*time.Second || r.ResetAfter != 6*time.Second {
+		t.Errorf("Take() on empty bucket = %+v, want denied, retry after 2s, reset after 6s", r)
+	}
+	if r := take("ip:10.0.0.2"); !r.Allowed {
+		t.Errorf("Take() for another key = %+v, want allowed", r)
+	}
+
+	now = now.Add(time.Second)
+	if r := take("ip:10.0.0.1"); r.Allowed || r.RetryAfter != time.Second {
+		t.Errorf("Take() after 1s = %+v, want denied, retry after 1s", r)
+	}
+	now = now.Add(time.Second)
+	if r := take("ip:10.0.0.1"); !r.Allowed || r.Remaining != 0 {
+		t.Errorf("Take() after 2s = %+v, want allowed", r)
+	}
+
+	// 恢复为满的令牌桶会被清理
+	now = now.Add(sweepInterval)
+	take("ip:10.0.0.3")
+	if len(s.buckets) != 1 {
+		t.Errorf("buckets = %d after sweep, want 1", len(s.buckets))
+	}
+}
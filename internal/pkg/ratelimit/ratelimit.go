@@ -0,0 +1,62 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit 定义了令牌桶的容量和令牌生成速率.
+type Limit struct {
+	// Rate 为每秒生成的令牌数
+	Rate float64
+	// Burst 为令牌桶的容量，即允许的最大突发请求数
+	Burst int
+}
+
+// Result 为一次取令牌的结果.
+type Result struct {
+	// Allowed 表示是否成功取到令牌
+	Allowed bool
+	// Limit 为令牌桶的容量
+	Limit int
+	// Remaining 为取令牌后桶中剩余的完整令牌数
+	Remaining int
+	// RetryAfter 为未取到令牌时，需要等待多久才会有新的令牌
+	RetryAfter time.Duration
+	// ResetAfter 为令牌桶恢复为满的时间
+	ResetAfter time.Duration
+}
+
+// Store 定义了令牌桶的存储.
+type Store interface {
+	// Take 从 key 对应的令牌桶中取出一个令牌，令牌桶不存在时创建一个满的令牌桶.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// newResult 根据取令牌后桶中的令牌数计算结果.
+func newResult(allowed bool, tokens float64, limit Limit) Result {
+	result := Result{
+		Allowed:    allowed,
+		Limit:      limit.Burst,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: seconds((float64(limit.Burst) - tokens) / limit.Rate),
+	}
+	if !allowed {
+		result.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+
+	return result
+}
+
+func seconds(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
@@ -116,6 +116,21 @@ func (r *Request) SetHeader(key string, values ...string) *Request {
 	return r
 }
 
+// Param 为请求添加一个查询参数.
+func (r *Request) Param(paramName, value string) *Request {
+	if r.err != nil {
+		return r
+	}
+
+	if r.params == nil {
+		r.params = make(url.Values)
+	}
+
+	r.params[paramName] = append(r.params[paramName], value)
+
+	return r
+}
+
 // AbsPath 用给定的 segments 重写现有路径.
 func (r *Request) AbsPath(segments ...string) *Request {
 	if r.err != nil {
@@ -0,0 +1,74 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package revocation
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryRevoker 将吊销列表保存在内存中，只适用于单副本部署和测试.
+type MemoryRevoker struct {
+	mu sync.Mutex
+	// tokens 保存被吊销的 token 及其过期时间
+	tokens map[string]time.Time
+	// users 保存被吊销全部 token 的用户及吊销时间的 Unix 毫秒时间戳
+	users map[string]int64
+	now   func() time.Time
+}
+
+var _ Revoker = (*MemoryRevoker)(nil)
+
+// NewMemoryRevoker 创建一个保存在内存中的吊销列表.
+func NewMemoryRevoker() *MemoryRevoker {
+	return &MemoryRevoker{
+		tokens: make(map[string]time.Time),
+		users:  make(map[string]int64),
+		now:    time.Now,
+	}
+}
+
+// Revoke 吊销 jti 对应的 token.
+func (m *MemoryRevoker) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	for id, exp := range m.tokens {
+		if !exp.After(now) {
+			delete(m.tokens, id)
+		}
+	}
+	if expiresAt.After(now) {
+		m.tokens[jti] = expiresAt
+	}
+
+	return nil
+}
+
+// RevokeUser 吊销用户在当前时间之前签发的所有 token.
+func (m *MemoryRevoker) RevokeUser(ctx context.Context, username string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.users[username] = m.now().UnixMilli()
+
+	return nil
+}
+
+// IsRevoked 判断 token 是否已被吊销.
+func (m *MemoryRevoker) IsRevoked(ctx context.Context, jti string, username string, issuedAt time.Time) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if exp, ok := m.tokens[jti]; ok && exp.After(m.now()) {
+		return true, nil
+	}
+	revokedAt, ok := m.users[username]
+
+	return ok && issuedBefore(issuedAt, revokedAt), nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package revocation
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	genoptions "github.com/changaolee/skeleton/internal/pkg/options"
+	"github.com/changaolee/skeleton/pkg/db"
+)
+
+const (
+	// tokenKeyPrefix 为被吊销的 token 在 Redis 中的 key 前缀，值为 1.
+	tokenKeyPrefix = "skt:revoked:token:"
+	// userKeyPrefix 为被吊销全部 token 的用户在 Redis 中的 key 前缀，值为吊销时间的 Unix 毫秒时间戳.
+	userKeyPrefix = "skt:revoked:user:"
+	// secondsThreshold 以下的吊销时间是以秒为单位保存的，以毫秒计相当于 2001 年之前.
+	secondsThreshold = 1e12
+)
+
+// redisClient 定义了 RedisRevoker 使用的 Redis 命令.
+type redisClient interface {
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd
+	MGet(ctx context.Context, keys ...string) *redis.SliceCmd
+}
+
+// RedisRevoker 将吊销列表保存在 Redis 中，所有 skt-apiserver 副本共享.
+type RedisRevoker struct {
+	client redisClient
+	// lifetime 为 token 的最长可用时间，单位为纳秒
+	lifetime int64
+	now      func() time.Time
+}
+
+var _ Revoker = (*RedisRevoker)(nil)
+
+// NewRedisRevoker 创建一个基于 Redis 的吊销列表，lifetime 为 token 签发后最长可以使用（包括刷新）的时间.
+func NewRedisRevoker(opts *genoptions.RedisOptions, lifetime time.Duration) (*RedisRevoker, error) {
+	client, err := db.NewRedis(&db.RedisOptions{
+		Host:     opts.Host,
+		Port:     opts.Port,
+		Username: opts.Username,
+		Password: opts.Password,
+		Database: opts.Database,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newRedisRevoker(client, lifetime), nil
+}
+
+func newRedisRevoker(client redisClient, lifetime time.Duration) *RedisRevoker {
+	return &RedisRevoker{
+		client:   client,
+		lifetime: int64(lifetime),
+		now:      time.Now,
+	}
+}
+
+// SetTokenLifetime 修改 token 的最长可用时间，对之后吊销的用户生效.
+func (r *RedisRevoker) SetTokenLifetime(lifetime time.Duration) {
+	atomic.StoreInt64(&r.lifetime, int64(lifetime))
+}
+
+// Revoke 吊销 jti 对应的 token.
+func (r *RedisRevoker) Revoke(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := expiresAt.Sub(r.now())
+	if ttl <= 0 {
+		return nil
+	}
+
+	return r.client.Set(ctx, tokenKeyPrefix+jti, 1, ttl).Err()
+}
+
+// RevokeUser 吊销用户在当前时间之前签发的所有 token，吊销记录在这些 token 全部失效后自动删除.
+func (r *RedisRevoker) RevokeUser(ctx context.Context, username string) error {
+	ttl := time.Duration(atomic.LoadInt64(&r.lifetime))
+
+	return r.client.Set(ctx, userKeyPrefix+username, r.now().UnixMilli(), ttl).Err()
+}
+
+// IsRevoked 判断 token 是否已被吊销.
+func (r *RedisRevoker) IsRevoked(ctx context.Context, jti string, username string, issuedAt time.Time) (bool, error) {
+	values, err := r.client.MGet(ctx, tokenKeyPrefix+jti, userKeyPrefix+username).Result()
+	if err != nil {
+		return false, err
+	}
+	if values[0] != nil {
+		return true, nil
+	}
+	if values[1] == nil {
+		return false, nil
+	}
+
+	s, _ := values[1].(string)
+	revokedAt, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	// 兼容升级前以秒为单位保存的吊销时间
+	if revokedAt < secondsThreshold {
+		revokedAt *= 1000
+	}
+
+	return issuedBefore(issuedAt, revokedAt), nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package revocation
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type fakeRedisClient struct {
+	values map[string]string
+	ttls   map[string]time.Duration
+}
+
+func newFakeRedisClient() *fakeRedisClient {
+	return &fakeRedisClient{values: map[string]string{}, ttls: map[string]time.Duration{}}
+}
+
+func (c *fakeRedisClient) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
+	c.values[key] = fmt.Sprint(value)
+	c.ttls[key] = expiration
+
+	return redis.NewStatusResult("OK", nil)
+}
+
+func (c *fakeRedisClient) MGet(ctx context.Context, keys ...string) *redis.SliceCmd {
+	values := make([]interface{}, len(keys))
+	for i, key := range keys {
+		if v, ok := c.values[key]; ok {
+			values[i] = v
+		}
+	}
+
+	return redis.NewSliceResult(values, nil)
+}
+
+func TestRedisRevoker(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	client := newFakeRedisClient()
+	r := newRedisRevoker(client, 48*time.Hour)
+	r.now = func() time.Time { return now }
+
+	isRevoked := func(jti, username string, issuedAt time.Time) bool {
+		t.Helper()
+		revoked, err := r.IsRevoked(ctx, jti, username, issuedAt)
+		if err != nil {
+			t.Fatalf("IsRevoked() error = %v", err)
+		}
+		return revoked
+	}
+
+	if isRevoked("t1", "alice", now) {
+		t.Errorf("token t1 is revoked before Revoke()")
+	}
+
+	if err := r.Revoke(ctx, "t1", now.Add(time.Hour)); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if client.ttls[tokenKeyPrefix+"t1"] != time.Hour {
+		t.Errorf("ttl of revoked token = %v, want 1h", client.ttls[tokenKeyPrefix+"t1"])
+	}
+	if !isRevoked("t1", "alice", now) || isRevoked("t2", "alice", now) {
+		t.Errorf("only token t1 should be revoked")
+	}
+
+	// 已经过期的 token 不需要记录
+	if err := r.Revoke(ctx, "t3", now.Add(-time.Second)); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if _, ok := client.values[tokenKeyPrefix+"t3"]; ok {
+		t.Errorf("expired token t3 is recorded")
+	}
+
+	if err := r.RevokeUser(ctx, "bob"); err != nil {
+		t.Fatalf("RevokeUser() error = %v", err)
+	}
+	if client.ttls[userKeyPrefix+"bob"] != 48*time.Hour {
+		t.Errorf("ttl of revoked user = %v, want 48h", client.ttls[userKeyPrefix+"bob"])
+	}
+	if !isRevoked("t4", "bob", now.Add(-time.Minute)) || !isRevoked("t5", "bob", now.Add(-time.Second)) {
+		t.Errorf("tokens of bob issued before revocation should be revoked")
+	}
+	// 与吊销在同一秒内，吊销之前签发的 token 需要被吊销，吊销之后签发的 token 不能被吊销
+	if !isRevoked("t6", "bob", now.Add(-time.Millisecond)) {
+		t.Errorf("token of bob issued in the same second before revocation should be revoked")
+	}
+	if isRevoked("t7", "bob", now) || isRevoked("t8", "bob", now.Add(time.Millisecond)) {
+		t.Errorf("tokens of bob issued after revocation should not be revoked")
+	}
+	if isRevoked("t9", "alice", now.Add(-time.Minute)) {
+		t.Errorf("tokens of other users should not be revoked")
+	}
+
+	// 升级前以秒为单位保存的吊销时间
+	client.values[userKeyPrefix+"carol"] = fmt.Sprint(now.Unix())
+	if !isRevoked("t10", "carol", now.Add(-time.Millisecond)) || isRevoked("t11", "carol", now) {
+		t.Errorf("revocation time saved in seconds is not compared correctly")
+	}
+}
+
+func TestIssuedAt(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims map[string]interface{}
+		want   time.Time
+	}{
+		{"milliseconds", map[string]interface{}{"orig_iat": float64(1700000000), "iat_ms": float64(1700000000123)}, time.UnixMilli(1700000000123)},
+		{"seconds", map[string]interface{}{"orig_iat": float64(1700000000)}, time.Unix(1700000000, 0)},
+		{"none", map[string]interface{}{}, time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IssuedAt(tt.claims); !got.Equal(tt.want) {
+				t.Errorf("IssuedAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@
 package server
 
 import (
+	"fmt"
 	"net"
 	"os"
 	"path/filepath"
@@ -16,6 +17,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/viper"
 
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
 	"github.com/changaolee/skeleton/pkg/log"
 )
 
@@ -34,9 +36,26 @@ type Config struct {
 	Jwt             *JwtInfo
 	Mode            string
 	Middlewares     []string
-	Healthz         bool
+	// Cors 为 cors 中间件使用的配置
+	Cors middleware.CorsConfig
+	// RateLimit 为 ratelimit 中间件使用的配置
+	RateLimit middleware.RateLimitConfig
+	Healthz   bool
+	// HealthCheckTimeout 为 /readyz 单个检查项的超时时间
+	HealthCheckTimeout time.Duration
+	// HealthCheckCacheTTL 为 /readyz 检查结果的缓存时间
+	HealthCheckCacheTTL time.Duration
+	// ShutdownTimeout 为优雅关闭的整体截止时间
+	ShutdownTimeout time.Duration
+	// ShutdownDelay 为就绪检查失败后到开始关闭服务之间的等待时间
+	ShutdownDelay   time.Duration
 	EnableProfiling bool
 	EnableMetrics   bool
+	// ProfilingAccess 为 /debug/pprof 路由的访问限制，可选值为 all、insecure 和 admin
+	ProfilingAccess string
+	// ProfilingAuth 为 ProfilingAccess 是 admin 时 /debug/pprof 路由使用的认证和鉴权中间件，
+	// 任何访问限制下修改采样率的接口都需要通过它认证和鉴权
+	ProfilingAuth []gin.HandlerFunc
 }
 
 // CertKey 包含与证书相关的配置项.
@@ -80,11 +99,17 @@ func NewConfig() *Config {
 			Timeout:    1 * time.Hour,
 			MaxRefresh: 1 * time.Hour,
 		},
-		Mode:            gin.ReleaseMode,
-		Middlewares:     []string{},
-		Healthz:         true,
-		EnableProfiling: true,
-		EnableMetrics:   true,
+		Mode:                gin.ReleaseMode,
+		Middlewares:         []string{},
+		Cors:                middleware.DefaultCorsConfig(),
+		Healthz:             true,
+		HealthCheckTimeout:  3 * time.Second,
+		HealthCheckCacheTTL: 1 * time.Second,
+		ShutdownTimeout:     30 * time.Second,
+		ShutdownDelay:       0,
+		EnableProfiling:     true,
+		EnableMetrics:       true,
+		ProfilingAccess:     ProfilingAccessInsecure,
 	}
 }
 
@@ -99,16 +124,32 @@ func (c *Config) Complete() *CompletedConfig {
 }
 
 func (c *CompletedConfig) New() (*GenericAPIServer, error) {
+	if c.EnableProfiling && c.ProfilingAccess == ProfilingAccessAdmin && len(c.ProfilingAuth) == 0 {
+		return nil, fmt.Errorf("profiling access %q is not supported by this server", c.ProfilingAccess)
+	}
+
+	if err := middleware.SetCorsConfig(c.Cors); err != nil {
+		return nil, fmt.Errorf("invalid cors config: %w", err)
+	}
+	if err := middleware.SetRateLimitConfig(c.RateLimit); err != nil {
+		return nil, fmt.Errorf("invalid ratelimit config: %w", err)
+	}
+
 	gin.SetMode(c.Mode)
 
 	s := &GenericAPIServer{
 		middlewares:         c.Middlewares,
 		SecureServingInfo:   c.SecureServing,
 		InsecureServingInfo: c.InsecureServing,
+		ShutdownTimeout:     c.ShutdownTimeout,
+		ShutdownDelay:       c.ShutdownDelay,
 		Engine:              gin.New(),
 		healthz:             c.Healthz,
+		health:              newHealthRegistry(c.HealthCheckTimeout, c.HealthCheckCacheTTL),
 		enableMetrics:       c.EnableMetrics,
 		enableProfiling:     c.EnableProfiling,
+		profilingAccess:     c.ProfilingAccess,
+		profilingAuth:       c.ProfilingAuth,
 	}
 
 	initGenericAPIServer(s)
@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -25,7 +26,10 @@ import (
 // GenericAPIServer 包含一个 skeleton api 服务器的配置.
 // type GenericAPIServer gin.Engine.
 type GenericAPIServer struct {
+	// middlewares 为启动时配置的自定义中间件，热加载后当前生效的中间件使用 Middlewares 获取
 	middlewares []string
+	// middlewareChain 保存当前生效的自定义中间件，类型为 middlewareChain
+	middlewareChain atomic.Value
 
 	// SecureServingInfo 保存 HTTPS 服务配置
 	SecureServingInfo *SecureServingInfo
@@ -36,10 +40,16 @@ type GenericAPIServer struct {
 	// ShutdownTimeout 表示优雅关闭的超时时间
 	ShutdownTimeout time.Duration
 
+	// ShutdownDelay 表示就绪检查失败后，等待负载均衡摘除流量的时间
+	ShutdownDelay time.Duration
+
 	*gin.Engine
 	healthz         bool
+	health          *healthRegistry
 	enableMetrics   bool
 	enableProfiling bool
+	profilingAccess string
+	profilingAuth   []gin.HandlerFunc
 
 	insecureServer, secureServer *http.Server
 }
@@ -47,7 +57,7 @@ type GenericAPIServer struct {
 func (s *GenericAPIServer) Run() error {
 	s.insecureServer = &http.Server{
 		Addr:    s.InsecureServingInfo.Address,
-		Handler: s,
+		Handler: markInsecure(s),
 	}
 
 	s.secureServer = &http.Server{
@@ -106,18 +116,50 @@ func (s *GenericAPIServer) Run() error {
 	return nil
 }
 
-// Shutdown 优雅关闭 API 服务.
-func (s *GenericAPIServer) Shutdown() {
-	// 创建 ctx 用于通知服务器 goroutine, 它有 10 秒时间完成当前正在处理的请求
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+// PrepareShutdown 使 /readyz 返回失败，并等待 ShutdownDelay，让负载均衡在关闭服务前摘除流量.
+func (s *GenericAPIServer) PrepareShutdown(ctx context.Context) error {
+	s.health.setShuttingDown()
+	log.Infof("Readiness check is now failing, wait %s before shutting down", s.ShutdownDelay)
+
+	if s.ShutdownDelay <= 0 {
+		return nil
+	}
 
-	if err := s.secureServer.Shutdown(ctx); err != nil {
-		log.Warnf("Shutdown secure server failed: %s", err.Error())
+	select {
+	case <-time.After(s.ShutdownDelay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	if err := s.insecureServer.Shutdown(ctx); err != nil {
-		log.Warnf("Shutdown insecure server failed: %s", err.Error())
+}
+
+// Shutdown 优雅关闭 API 服务，等待正在处理的请求完成.
+// ctx 没有截止时间时，使用 ShutdownTimeout 作为等待的最长时间.
+func (s *GenericAPIServer) Shutdown(ctx context.Context) error {
+	if _, ok := ctx.Deadline(); !ok && s.ShutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.ShutdownTimeout)
+		defer cancel()
 	}
+
+	// 同时关闭 HTTP 和 HTTPS 服务，两者共享同一个截止时间
+	var eg errgroup.Group
+	eg.Go(func() error {
+		if err := s.secureServer.Shutdown(ctx); err != nil {
+			log.Warnf("Shutdown secure server failed: %s", err.Error())
+			return err
+		}
+		return nil
+	})
+	eg.Go(func() error {
+		if err := s.insecureServer.Shutdown(ctx); err != nil {
+			log.Warnf("Shutdown insecure server failed: %s", err.Error())
+			return err
+		}
+		return nil
+	})
+
+	return eg.Wait()
 }
 
 // ping 对服务进行健康检查.
@@ -172,15 +214,73 @@ func (s *GenericAPIServer) installMiddlewares() {
 	s.Use(middleware.RequestID())
 	s.Use(middleware.Context())
 
-	// 自定义中间件
-	for _, m := range s.middlewares {
+	// 指标采集需要在业务路由注册前安装，才能覆盖所有路由
+	if s.enableMetrics {
+		s.Use(metrics())
+	}
+
+	// 自定义中间件，预留与支持的中间件数量相同的位置，配置热加载时只替换每个位置上执行的中间件
+	s.SetMiddlewares(s.middlewares)
+	for i := 0; i < len(middleware.Middlewares); i++ {
+		s.Use(s.middlewareSlot(i))
+	}
+}
+
+// middlewareChain 保存当前生效的自定义中间件及其名称，整体替换，避免热加载时读到不一致的状态.
+type middlewareChain struct {
+	names    []string
+	handlers []gin.HandlerFunc
+}
+
+// SetMiddlewares 替换自定义中间件列表，对之后到达的请求生效，可以与处理中的请求并发调用.
+func (s *GenericAPIServer) SetMiddlewares(names []string) {
+	chain := middlewareChain{
+		names:    make([]string, 0, len(names)),
+		handlers: make([]gin.HandlerFunc, 0, len(names)),
+	}
+	for _, m := range names {
 		mw, ok := middleware.Middlewares[m]
 		if !ok {
 			log.Warnf("Can not find middleware: %s", m)
 			continue
 		}
+		if len(chain.handlers) == len(middleware.Middlewares) {
+			log.Warnf("Too many middlewares, ignore middleware: %s", m)
+			continue
+		}
 		log.Infof("Install middleware: %s", m)
-		s.Use(mw)
+		chain.names = append(chain.names, m)
+		chain.handlers = append(chain.handlers, mw)
+	}
+
+	s.middlewareChain.Store(chain)
+}
+
+// Middlewares 返回当前生效的自定义中间件名称.
+func (s *GenericAPIServer) Middlewares() []string {
+	chain, _ := s.middlewareChain.Load().(middlewareChain)
+
+	return append([]string(nil), chain.names...)
+}
+
+// middlewareChainKey 在 Gin 上下文中保存请求开始时生效的自定义中间件.
+const middlewareChainKey = "middlewareChain"
+
+// middlewareSlot 返回执行第 i 个自定义中间件的处理函数，第 i 个中间件不存在时直接执行后续处理函数.
+// 第 0 个位置读取当前生效的中间件并保存到 Gin 上下文中，之后的位置使用同一份中间件，
+// 请求处理过程中热加载不会导致同一个请求重复执行或跳过中间件.
+func (s *GenericAPIServer) middlewareSlot(i int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var chain middlewareChain
+		if v, ok := c.Get(middlewareChainKey); ok && i > 0 {
+			chain, _ = v.(middlewareChain)
+		} else {
+			chain, _ = s.middlewareChain.Load().(middlewareChain)
+			c.Set(middlewareChainKey, chain)
+		}
+		if i < len(chain.handlers) {
+			chain.handlers[i](c)
+		}
 	}
 }
 
@@ -190,6 +290,15 @@ func (s *GenericAPIServer) installAPIs() {
 		s.GET("/healthz", func(c *gin.Context) {
 			core.WriteResponse(c, nil, map[string]string{"status": "ok"})
 		})
+		s.installHealthChecks()
+	}
+
+	if s.enableMetrics {
+		s.installMetrics()
+	}
+
+	if s.enableProfiling {
+		s.installProfiling()
 	}
 
 	s.GET("/version", func(c *gin.Context) {
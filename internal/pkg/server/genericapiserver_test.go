@@ -0,0 +1,115 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/middleware"
+)
+
+func TestSetMiddlewares(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := &GenericAPIServer{
+		Engine:      gin.New(),
+		middlewares: []string{"nocache"},
+	}
+	s.installMiddlewares()
+
+	var handled int
+	s.GET("/version", func(c *gin.Context) {
+		handled++
+		c.Status(http.StatusOK)
+	})
+
+	serve := func(method string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, httptest.NewRequest(method, "/version", nil))
+		return w
+	}
+
+	if w := serve(http.MethodGet); w.Header().Get("Cache-Control") == "" {
+		t.Errorf("nocache middleware was not installed")
+	}
+
+	// options 中间件会终止 OPTIONS 请求，之后的中间件和路由都不再执行
+	s.SetMiddlewares([]string{"options", "unknown", "nocache"})
+	if w := serve(http.MethodOptions); w.Header().Get("Allow") == "" || w.Header().Get("Cache-Control") != "" {
+		t.Errorf("OPTIONS headers = %v, want handled by options middleware only", w.Header())
+	}
+	if w := serve(http.MethodGet); w.Header().Get("Cache-Control") == "" {
+		t.Errorf("nocache middleware was not kept after reload")
+	}
+
+	s.SetMiddlewares(nil)
+	if w := serve(http.MethodGet); w.Header().Get("Cache-Control") != "" {
+		t.Errorf("nocache middleware was not removed after reload")
+	}
+
+	if handled != 3 {
+		t.Errorf("route handled %d requests, want 3", handled)
+	}
+}
+
+func TestSetMiddlewares_DuringRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := &GenericAPIServer{
+		Engine:      gin.New(),
+		middlewares: []string{"reload", "nocache"},
+	}
+	// 请求处理过程中热加载，移除 reload 中间件
+	middleware.Middlewares["reload"] = func(c *gin.Context) { s.SetMiddlewares([]string{"nocache"}) }
+	defer delete(middleware.Middlewares, "reload")
+
+	s.installMiddlewares()
+	s.GET("/version", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// 正在处理的请求仍然使用请求开始时的中间件，不会跳过 nocache
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+	if w.Header().Get("Cache-Control") == "" {
+		t.Errorf("nocache middleware was skipped by a reload during the request")
+	}
+	if got := s.Middlewares(); len(got) != 1 || got[0] != "nocache" {
+		t.Errorf("Middlewares() = %v, want [nocache]", got)
+	}
+}
+
+func TestSetMiddlewares_Concurrent(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s := &GenericAPIServer{
+		Engine:      gin.New(),
+		middlewares: []string{"nocache"},
+	}
+	s.installMiddlewares()
+	s.GET("/version", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// 使用 -race 运行时，热加载与处理请求、读取中间件列表之间不能有数据竞争
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			s.SetMiddlewares([]string{"nocache", "secure"})
+			s.SetMiddlewares([]string{"nocache"})
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		s.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/version", nil))
+		_ = s.Middlewares()
+	}
+	<-done
+
+	if got := s.Middlewares(); len(got) != 1 || got[0] != "nocache" {
+		t.Errorf("Middlewares() = %v, want [nocache]", got)
+	}
+}
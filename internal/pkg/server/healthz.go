@@ -0,0 +1,204 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthChecker 定义了一个命名的就绪检查项.
+type HealthChecker interface {
+	// Name 返回检查项名称，在同一个服务中必须唯一.
+	Name() string
+	// Check 执行检查，返回 nil 表示检查通过.
+	Check(ctx context.Context) error
+}
+
+type healthCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+func (h *healthCheck) Name() string {
+	return h.name
+}
+
+func (h *healthCheck) Check(ctx context.Context) error {
+	return h.check(ctx)
+}
+
+// NamedCheck 使用指定的名称和检查函数创建一个 HealthChecker.
+func NamedCheck(name string, check func(ctx context.Context) error) HealthChecker {
+	return &healthCheck{name: name, check: check}
+}
+
+// HealthCheckResult 是单个检查项的检查结果.
+type HealthCheckResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// HealthStatus 是 /livez 和 /readyz 的响应.
+type HealthStatus struct {
+	Status string              `json:"status"`
+	Checks []HealthCheckResult `json:"checks,omitempty"`
+}
+
+// 定义检查状态.
+const (
+	healthStatusOK     = "ok"
+	healthStatusFailed = "failed"
+)
+
+type cachedHealthResult struct {
+	err       error
+	checkedAt time.Time
+}
+
+// healthRegistry 保存所有就绪检查项，并缓存每一项的检查结果.
+type healthRegistry struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mu      sync.Mutex
+	checks  []HealthChecker
+	results map[string]cachedHealthResult
+
+	// shuttingDown 为 1 时表示服务正在关闭，就绪检查直接失败
+	shuttingDown int32
+}
+
+func newHealthRegistry(timeout, cacheTTL time.Duration) *healthRegistry {
+	return &healthRegistry{
+		timeout:  timeout,
+		cacheTTL: cacheTTL,
+		results:  make(map[string]cachedHealthResult),
+	}
+}
+
+func (r *healthRegistry) add(checks ...HealthChecker) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, check := range checks {
+		for _, existing := range r.checks {
+			if existing.Name() == check.Name() {
+				return fmt.Errorf("health check %q is already registered", check.Name())
+			}
+		}
+		r.checks = append(r.checks, check)
+	}
+
+	return nil
+}
+
+func (r *healthRegistry) setShuttingDown() {
+	atomic.StoreInt32(&r.shuttingDown, 1)
+}
+
+// run 并发执行所有检查项，缓存未过期的检查项直接使用缓存结果.
+func (r *healthRegistry) run(ctx context.Context) []HealthCheckResult {
+	if atomic.LoadInt32(&r.shuttingDown) == 1 {
+		return []HealthCheckResult{{Name: "shutdown", Status: healthStatusFailed, Error: "server is shutting down"}}
+	}
+
+	r.mu.Lock()
+	checks := make([]HealthChecker, len(r.checks))
+	copy(checks, r.checks)
+	r.mu.Unlock()
+
+	results := make([]HealthCheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check HealthChecker) {
+			defer wg.Done()
+
+			results[i] = HealthCheckResult{Name: check.Name(), Status: healthStatusOK}
+			if err := r.check(ctx, check); err != nil {
+				results[i].Status = healthStatusFailed
+				results[i].Error = err.Error()
+			}
+		}(i, check)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (r *healthRegistry) check(ctx context.Context, check HealthChecker) error {
+	now := time.Now()
+
+	r.mu.Lock()
+	cached, ok := r.results[check.Name()]
+	r.mu.Unlock()
+	if ok && now.Sub(cached.checkedAt) < r.cacheTTL {
+		return cached.err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- check.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-errCh:
+	case <-ctx.Done():
+		err = fmt.Errorf("check timed out after %s", r.timeout)
+	}
+
+	r.mu.Lock()
+	r.results[check.Name()] = cachedHealthResult{err: err, checkedAt: now}
+	r.mu.Unlock()
+
+	return err
+}
+
+// AddHealthChecks 向 /readyz 注册就绪检查项.
+func (s *GenericAPIServer) AddHealthChecks(checks ...HealthChecker) error {
+	return s.health.add(checks...)
+}
+
+// installHealthChecks 安装 /livez 和 /readyz 路由.
+func (s *GenericAPIServer) installHealthChecks() {
+	// 存活检查只反映进程能否处理请求，不检查外部依赖，避免依赖故障导致服务被反复重启
+	s.GET("/livez", func(c *gin.Context) {
+		c.JSON(http.StatusOK, HealthStatus{Status: healthStatusOK})
+	})
+
+	s.GET("/readyz", func(c *gin.Context) {
+		results := s.health.run(c.Request.Context())
+
+		status := HealthStatus{Status: healthStatusOK}
+		for _, result := range results {
+			if result.Status != healthStatusOK {
+				status.Status = healthStatusFailed
+				break
+			}
+		}
+		if _, verbose := c.GetQuery("verbose"); verbose {
+			status.Checks = results
+		}
+
+		httpStatus := http.StatusOK
+		if status.Status != healthStatusOK {
+			httpStatus = http.StatusServiceUnavailable
+		}
+		c.JSON(httpStatus, status)
+	})
+}
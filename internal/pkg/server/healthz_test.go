@@ -0,0 +1,122 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newHealthServer(timeout, ttl time.Duration) *GenericAPIServer {
+	gin.SetMode(gin.TestMode)
+
+	s := &GenericAPIServer{
+		Engine: gin.New(),
+		health: newHealthRegistry(timeout, ttl),
+	}
+	s.installHealthChecks()
+
+	return s
+}
+
+func getHealth(t *testing.T, s *GenericAPIServer, path string) (int, HealthStatus) {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var status HealthStatus
+	if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+		t.Fatalf("unmarshal %s response: %v", path, err)
+	}
+
+	return w.Code, status
+}
+
+func TestReadyz(t *testing.T) {
+	s := newHealthServer(50*time.Millisecond, 0)
+
+	var redisErr atomic.Value
+	redisErr.Store(errors.New("connection refused"))
+
+	if err := s.AddHealthChecks(
+		NamedCheck("mysql", func(ctx context.Context) error { return nil }),
+		NamedCheck("redis", func(ctx context.Context) error { return redisErr.Load().(error) }),
+		NamedCheck("slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		}),
+	); err != nil {
+		t.Fatalf("AddHealthChecks() error = %v", err)
+	}
+	if err := s.AddHealthChecks(NamedCheck("mysql", nil)); err == nil {
+		t.Errorf("AddHealthChecks() with duplicate name succeeded")
+	}
+
+	code, status := getHealth(t, s, "/readyz")
+	if code != http.StatusServiceUnavailable || status.Status != healthStatusFailed || status.Checks != nil {
+		t.Errorf("/readyz = %d %+v, want 503 failed without checks", code, status)
+	}
+
+	_, status = getHealth(t, s, "/readyz?verbose")
+	want := map[string]string{"mysql": healthStatusOK, "redis": healthStatusFailed, "slow": healthStatusFailed}
+	for _, result := range status.Checks {
+		if want[result.Name] != result.Status {
+			t.Errorf("check %s status = %s, want %s (%s)", result.Name, result.Status, want[result.Name], result.Error)
+		}
+	}
+
+	if code, status := getHealth(t, s, "/livez"); code != http.StatusOK || status.Status != healthStatusOK {
+		t.Errorf("/livez = %d %+v, want 200 ok", code, status)
+	}
+}
+
+func TestReadyz_Cache(t *testing.T) {
+	s := newHealthServer(time.Second, time.Hour)
+
+	var calls int32
+	_ = s.AddHealthChecks(NamedCheck("mysql", func(ctx context.Context) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	}))
+
+	for i := 0; i < 3; i++ {
+		if code, _ := getHealth(t, s, "/readyz"); code != http.StatusOK {
+			t.Fatalf("/readyz status = %d, want 200", code)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("check called %d times, want 1", calls)
+	}
+}
+
+func TestReadyz_PrepareShutdown(t *testing.T) {
+	s := newHealthServer(time.Second, 0)
+	_ = s.AddHealthChecks(NamedCheck("mysql", func(ctx context.Context) error { return nil }))
+
+	if code, _ := getHealth(t, s, "/readyz"); code != http.StatusOK {
+		t.Fatalf("/readyz status = %d, want 200", code)
+	}
+
+	if err := s.PrepareShutdown(context.Background()); err != nil {
+		t.Fatalf("PrepareShutdown() error = %v", err)
+	}
+
+	if code, _ := getHealth(t, s, "/readyz"); code != http.StatusServiceUnavailable {
+		t.Errorf("/readyz status after PrepareShutdown = %d, want 503", code)
+	}
+	if code, _ := getHealth(t, s, "/livez"); code != http.StatusOK {
+		t.Errorf("/livez status after PrepareShutdown = %d, want 200", code)
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/pkg/errors"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	g := gin.New()
+	g.Use(metrics())
+	g.GET("/v1/users/:name", func(c *gin.Context) {
+		if c.Param("name") == "missing" {
+			core.WriteResponse(c, errors.WithCode(code.ErrUserNotFound, "user not found"), nil)
+			return
+		}
+		core.WriteResponse(c, nil, map[string]string{"name": c.Param("name")})
+	})
+
+	for _, path := range []string{"/v1/users/admin", "/v1/users/colin", "/v1/users/missing", "/unknown"} {
+		g.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	tests := []struct {
+		route, status, code string
+		want                float64
+	}{
+		{route: "/v1/users/:name", status: "200", code: "0", want: 2},
+		{route: "/v1/users/:name", status: "404", code: strconv.Itoa(code.ErrUserNotFound), want: 1},
+		{route: unmatchedRoute, status: "404", code: "0", want: 1},
+	}
+	for _, tt := range tests {
+		got := testutil.ToFloat64(httpRequestsTotal.WithLabelValues(http.MethodGet, tt.route, tt.status, tt.code))
+		if got != tt.want {
+			t.Errorf("requests_total{route=%q,status=%q,code=%q} = %v, want %v", tt.route, tt.status, tt.code, got, tt.want)
+		}
+	}
+
+	if got := testutil.ToFloat64(httpRequestsInFlight); got != 0 {
+		t.Errorf("requests_in_flight = %v, want 0", got)
+	}
+}
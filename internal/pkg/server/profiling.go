@@ -0,0 +1,141 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/changaolee/skeleton/internal/pkg/code"
+	"github.com/changaolee/skeleton/internal/pkg/core"
+	"github.com/changaolee/skeleton/pkg/errors"
+)
+
+// 定义 /debug/pprof 路由的访问限制.
+const (
+	// ProfilingAccessAll 表示 HTTP 和 HTTPS 监听地址都可以访问.
+	ProfilingAccessAll = "all"
+	// ProfilingAccessInsecure 表示只能通过 HTTP（非安全）监听地址访问，通常只绑定在本机，
+	// 不要让反向代理将外部请求转发到该地址.
+	ProfilingAccessInsecure = "insecure"
+	// ProfilingAccessAdmin 表示需要通过 Config.ProfilingAuth 认证和鉴权.
+	ProfilingAccessAdmin = "admin"
+)
+
+// blockProfileRate 记录当前的 block profile 采样率，runtime 没有提供读取方法.
+var blockProfileRate int64
+
+// ProfilingRates 定义了 block 和 mutex profile 的采样率.
+type ProfilingRates struct {
+	// BlockProfileRate 参考 runtime.SetBlockProfileRate，0 表示关闭.
+	BlockProfileRate *int `json:"blockProfileRate,omitempty"`
+	// MutexProfileFraction 参考 runtime.SetMutexProfileFraction，0 表示关闭.
+	MutexProfileFraction *int `json:"mutexProfileFraction,omitempty"`
+}
+
+// installProfiling 安装 /debug/pprof 路由.
+func (s *GenericAPIServer) installProfiling() {
+	handlers := []gin.HandlerFunc{}
+	switch s.profilingAccess {
+	case ProfilingAccessInsecure:
+		handlers = append(handlers, insecureOnly)
+	case ProfilingAccessAdmin:
+		handlers = append(handlers, s.profilingAuth...)
+	}
+
+	g := s.Group("/debug/pprof", handlers...)
+	{
+		g.GET("/", gin.WrapF(pprof.Index))
+		g.GET("/cmdline", gin.WrapF(pprof.Cmdline))
+		g.GET("/profile", gin.WrapF(pprof.Profile))
+		g.POST("/symbol", gin.WrapF(pprof.Symbol))
+		g.GET("/symbol", gin.WrapF(pprof.Symbol))
+		g.GET("/trace", gin.WrapF(pprof.Trace))
+		g.GET("/allocs", gin.WrapH(pprof.Handler("allocs")))
+		g.GET("/block", gin.WrapH(pprof.Handler("block")))
+		g.GET("/goroutine", gin.WrapH(pprof.Handler("goroutine")))
+		g.GET("/heap", gin.WrapH(pprof.Handler("heap")))
+		g.GET("/mutex", gin.WrapH(pprof.Handler("mutex")))
+		g.GET("/threadcreate", gin.WrapH(pprof.Handler("threadcreate")))
+
+		// 运行时调整 block 和 mutex profile 的采样率，修改采样率会影响服务性能，
+		// 任何访问限制下都需要通过 Config.ProfilingAuth 认证和鉴权，没有提供时不安装修改接口
+		g.GET("/rates", getProfilingRates)
+		switch {
+		case s.profilingAccess == ProfilingAccessAdmin:
+			g.PUT("/rates", setProfilingRates)
+		case len(s.profilingAuth) > 0:
+			auth := append([]gin.HandlerFunc{}, s.profilingAuth...)
+			g.PUT("/rates", append(auth, setProfilingRates)...)
+		}
+	}
+}
+
+// insecureListenerKey 是标记请求来自 HTTP（非安全）监听地址的 context key.
+type insecureListenerKey struct{}
+
+// markInsecure 标记经过 h 处理的请求来自 HTTP（非安全）监听地址.
+// 在代理上终止 TLS 时 HTTPS 请求的 Request.TLS 也为空，因此按监听地址区分请求.
+func markInsecure(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), insecureListenerKey{}, true)))
+	})
+}
+
+// insecureOnly 拒绝不是来自 HTTP（非安全）监听地址的请求.
+func insecureOnly(c *gin.Context) {
+	if insecure, _ := c.Request.Context().Value(insecureListenerKey{}).(bool); !insecure {
+		core.WriteResponse(c, errors.WithCode(code.ErrPageNotFound, "page not found."), nil)
+		c.Abort()
+
+		return
+	}
+
+	c.Next()
+}
+
+func currentProfilingRates() ProfilingRates {
+	block := int(atomic.LoadInt64(&blockProfileRate))
+	// 传入负数时只返回当前值，不做修改
+	mutex := runtime.SetMutexProfileFraction(-1)
+
+	return ProfilingRates{BlockProfileRate: &block, MutexProfileFraction: &mutex}
+}
+
+func getProfilingRates(c *gin.Context) {
+	core.WriteResponse(c, nil, currentProfilingRates())
+}
+
+func setProfilingRates(c *gin.Context) {
+	var r ProfilingRates
+	if err := c.ShouldBindJSON(&r); err != nil {
+		core.WriteResponse(c, errors.WithCode(code.ErrBind, err.Error()), nil)
+
+		return
+	}
+
+	if (r.BlockProfileRate != nil && *r.BlockProfileRate < 0) ||
+		(r.MutexProfileFraction != nil && *r.MutexProfileFraction < 0) {
+		core.WriteResponse(c, errors.WithCode(code.ErrValidation, "profiling rates must not be negative"), nil)
+
+		return
+	}
+
+	if r.BlockProfileRate != nil {
+		runtime.SetBlockProfileRate(*r.BlockProfileRate)
+		atomic.StoreInt64(&blockProfileRate, int64(*r.BlockProfileRate))
+	}
+	if r.MutexProfileFraction != nil {
+		runtime.SetMutexProfileFraction(*r.MutexProfileFraction)
+	}
+
+	core.WriteResponse(c, nil, currentProfilingRates())
+}
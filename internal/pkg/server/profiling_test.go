@@ -0,0 +1,110 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newProfilingServer(access string, auth ...gin.HandlerFunc) *GenericAPIServer {
+	gin.SetMode(gin.TestMode)
+
+	s := &GenericAPIServer{
+		Engine:          gin.New(),
+		enableProfiling: true,
+		profilingAccess: access,
+		profilingAuth:   auth,
+	}
+	s.installProfiling()
+
+	return s
+}
+
+func TestInstallProfiling_Access(t *testing.T) {
+	deny := func(c *gin.Context) { c.AbortWithStatus(http.StatusForbidden) }
+
+	tests := []struct {
+		name     string
+		server   *GenericAPIServer
+		method   string
+		path     string
+		insecure bool
+		want     int
+	}{
+		{name: "all over https", server: newProfilingServer(ProfilingAccessAll), want: http.StatusOK},
+		{name: "insecure over http", server: newProfilingServer(ProfilingAccessInsecure), insecure: true, want: http.StatusOK},
+		// TLS 在代理上终止时，来自 HTTPS 监听地址的请求也没有 TLS 信息
+		{name: "insecure over https", server: newProfilingServer(ProfilingAccessInsecure), want: http.StatusNotFound},
+		{name: "admin denied", server: newProfilingServer(ProfilingAccessAdmin, deny), want: http.StatusForbidden},
+		{
+			name: "set rates without auth", server: newProfilingServer(ProfilingAccessAll),
+			method: http.MethodPut, path: "/debug/pprof/rates", want: http.StatusNotFound,
+		},
+		{
+			name: "set rates denied", server: newProfilingServer(ProfilingAccessInsecure, deny),
+			method: http.MethodPut, path: "/debug/pprof/rates", insecure: true, want: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method, path := http.MethodGet, "/debug/pprof/cmdline"
+			if tt.path != "" {
+				method, path = tt.method, tt.path
+			}
+			var h http.Handler = tt.server
+			if tt.insecure {
+				h = markInsecure(tt.server)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader("{}")))
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestProfilingRates(t *testing.T) {
+	s := newProfilingServer(ProfilingAccessAll, func(c *gin.Context) { c.Next() })
+	defer func() {
+		runtime.SetBlockProfileRate(0)
+		runtime.SetMutexProfileFraction(0)
+	}()
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/debug/pprof/rates",
+		strings.NewReader(`{"blockProfileRate": 100, "mutexProfileFraction": 5}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("PUT status = %d, body = %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/debug/pprof/rates", nil))
+
+	var got ProfilingRates
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("unmarshal rates: %v", err)
+	}
+	if *got.BlockProfileRate != 100 || *got.MutexProfileFraction != 5 {
+		t.Errorf("rates = {%d, %d}, want {100, 5}", *got.BlockProfileRate, *got.MutexProfileFraction)
+	}
+
+	w = httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/debug/pprof/rates",
+		strings.NewReader(`{"blockProfileRate": -1}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("negative rate status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// rfcSecret 为 RFC 6238 附录 B 中 SHA1 测试使用的密钥.
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// RFC 6238 附录 B 的测试向量，取 8 位结果的后 6 位
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("Code() error = %v", err)
+		}
+		if got != tt.want {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+
+	now := time.Unix(1700000000, 0)
+	for _, offset := range []time.Duration{-Period, 0, Period} {
+		code, _ := Code(secret, now.Add(offset))
+		step, ok := Validate(secret, code, now)
+		if !ok || step != Step(now.Add(offset)) {
+			t.Errorf("Validate() with offset %v = (%d, %v), want (%d, true)", offset, step, ok, Step(now.Add(offset)))
+		}
+	}
+
+	for _, offset := range []time.Duration{-2 * Period, 2 * Period} {
+		code, _ := Code(secret, now.Add(offset))
+		if _, ok := Validate(secret, code, now); ok {
+			t.Errorf("Validate() with offset %v = true, want false", offset)
+		}
+	}
+
+	if _, ok := Validate(secret, "12345", now); ok {
+		t.Errorf("Validate() with short code = true, want false")
+	}
+}
+
+func TestURI(t *testing.T) {
+	u, err := url.Parse(URI("skt", "alice", "JBSWY3DPEHPK3PXP"))
+	if err != nil {
+		t.Fatalf("parse uri: %v", err)
+	}
+
+	if u.Scheme != "otpauth" || u.Host != "totp" || u.Path != "/skt:alice" {
+		t.Errorf("URI() = %s, want otpauth://totp/skt:alice", u)
+	}
+	if q := u.Query(); q.Get("secret") != "JBSWY3DPEHPK3PXP" || q.Get("issuer") != "skt" {
+		t.Errorf("URI() query = %v", q)
+	}
+}
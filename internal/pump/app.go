@@ -0,0 +1,42 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package pump
+
+import (
+	"github.com/changaolee/skeleton/internal/pump/config"
+	"github.com/changaolee/skeleton/internal/pump/options"
+	"github.com/changaolee/skeleton/pkg/app"
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+const commandDesc = `SKT Pump is a pluggable analytics purger to move authorization audit records
+generated by skt-authz-server to other sinks, such as stdout, file, webhook and mysql.`
+
+func NewApp(basename string) *app.App {
+	opts := options.NewOptions()
+	application := app.NewApp("SKT Analytics Pump",
+		basename,
+		app.WithOptions(opts),
+		app.WithDescription(commandDesc),
+		app.WithDefaultValidArgs(),
+		app.WithRunFunc(run(opts)),
+	)
+	return application
+}
+
+func run(opts *options.Options) app.RunFunc {
+	return func(basename string) error {
+		log.Init(opts.Log)
+		defer log.Sync()
+
+		cfg, err := config.CreateConfigFromOptions(opts)
+		if err != nil {
+			return err
+		}
+
+		return Run(cfg)
+	}
+}
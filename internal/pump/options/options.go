@@ -0,0 +1,100 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	genoptions "github.com/changaolee/skeleton/internal/pkg/options"
+	"github.com/changaolee/skeleton/internal/pump/pumps"
+	"github.com/changaolee/skeleton/pkg/app"
+	"github.com/changaolee/skeleton/pkg/log"
+)
+
+// PumpConfig 定义了一个审计日志输出端的配置.
+type PumpConfig struct {
+	Type    string                 `json:"type"    mapstructure:"type"`
+	Filters pumps.Filters          `json:"filters" mapstructure:"filters"`
+	Timeout time.Duration          `json:"timeout" mapstructure:"timeout"`
+	Meta    map[string]interface{} `json:"meta"    mapstructure:"meta"`
+}
+
+type Options struct {
+	PurgeDelay   time.Duration            `json:"purge-delay"  mapstructure:"purge-delay"`
+	BatchSize    int64                    `json:"batch-size"   mapstructure:"batch-size"`
+	LockTimeout  time.Duration            `json:"lock-timeout" mapstructure:"lock-timeout"`
+	Serializer   string                   `json:"serializer"   mapstructure:"serializer"`
+	Pumps        map[string]*PumpConfig   `json:"pumps"        mapstructure:"pumps"`
+	RedisOptions *genoptions.RedisOptions `json:"redis"        mapstructure:"redis"`
+	Log          *log.Options             `json:"log"          mapstructure:"log"`
+}
+
+// NewOptions 使用默认参数创建一个 options 对象.
+func NewOptions() *Options {
+	o := Options{
+		PurgeDelay:   10 * time.Second,
+		BatchSize:    1000,
+		LockTimeout:  60 * time.Second,
+		Serializer:   "msgpack",
+		Pumps:        map[string]*PumpConfig{},
+		RedisOptions: genoptions.NewRedisOptions(),
+		Log:          log.NewOptions(),
+	}
+	return &o
+}
+
+func (o *Options) Flags() (fss app.NamedFlagSets) {
+	o.RedisOptions.AddFlags(fss.FlagSet("redis"))
+	o.Log.AddFlags(fss.FlagSet("log"))
+
+	o.addMiscFlags(fss.FlagSet("misc"))
+
+	return fss
+}
+
+func (o *Options) addMiscFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&o.PurgeDelay, "purge-delay", o.PurgeDelay,
+		"The interval at which records are popped from redis and sent to pumps.")
+	fs.Int64Var(&o.BatchSize, "batch-size", o.BatchSize,
+		"The maximum number of records popped from redis at a time.")
+	fs.DurationVar(&o.LockTimeout, "lock-timeout", o.LockTimeout,
+		"The expiration of the distributed lock which prevents multiple pump replicas from processing the same records.")
+	fs.StringVar(&o.Serializer, "serializer", o.Serializer,
+		"The serializer of records in redis, must be same as skt-authz-server, one of msgpack, json.")
+}
+
+func (o *Options) Validate() []error {
+	var errs []error
+
+	if o.PurgeDelay <= 0 {
+		errs = append(errs, fmt.Errorf("--purge-delay must be greater than 0"))
+	}
+	if o.BatchSize <= 0 {
+		errs = append(errs, fmt.Errorf("--batch-size must be greater than 0"))
+	}
+	if o.LockTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("--lock-timeout must be greater than 0"))
+	}
+	if o.Serializer != "msgpack" && o.Serializer != "json" {
+		errs = append(errs, fmt.Errorf("--serializer must be one of msgpack, json"))
+	}
+	if len(o.Pumps) == 0 {
+		errs = append(errs, fmt.Errorf("at least one pump must be configured"))
+	}
+	for name, pump := range o.Pumps {
+		if pump == nil || !pumps.IsSupported(pump.Type) {
+			errs = append(errs, fmt.Errorf("pumps.%s: unsupported pump type", name))
+		}
+	}
+
+	errs = append(errs, o.RedisOptions.Validate()...)
+	errs = append(errs, o.Log.Validate()...)
+
+	return errs
+}
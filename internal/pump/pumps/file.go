@@ -0,0 +1,75 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package pumps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/changaolee/skeleton/internal/authzserver/analytics"
+)
+
+// FileConf 定义了本地文件输出端的配置.
+type FileConf struct {
+	Filename   string `mapstructure:"filename"`
+	MaxSize    int    `mapstructure:"max-size"`
+	MaxBackups int    `mapstructure:"max-backups"`
+	MaxAge     int    `mapstructure:"max-age"`
+	Compress   bool   `mapstructure:"compress"`
+}
+
+// FilePump 将审计日志以 JSON 行的形式写入本地文件，并按大小自动轮转.
+type FilePump struct {
+	writer *lumberjack.Logger
+}
+
+var _ Pump = (*FilePump)(nil)
+
+func (p *FilePump) Name() string {
+	return "file"
+}
+
+func (p *FilePump) Init(meta map[string]interface{}) error {
+	conf := &FileConf{
+		MaxSize:    100,
+		MaxBackups: 10,
+		MaxAge:     30,
+	}
+	if err := decodeMeta(meta, conf); err != nil {
+		return fmt.Errorf("failed to decode file pump meta: %w", err)
+	}
+	if conf.Filename == "" {
+		return fmt.Errorf("file pump requires meta.filename")
+	}
+
+	p.writer = &lumberjack.Logger{
+		Filename:   conf.Filename,
+		MaxSize:    conf.MaxSize,
+		MaxBackups: conf.MaxBackups,
+		MaxAge:     conf.MaxAge,
+		Compress:   conf.Compress,
+	}
+
+	return nil
+}
+
+func (p *FilePump) WriteData(ctx context.Context, records []*analytics.Record) error {
+	enc := json.NewEncoder(p.writer)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *FilePump) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package pumps
+
+import (
+	"github.com/changaolee/skeleton/internal/authzserver/analytics"
+)
+
+// Filters 定义了输出端的审计日志过滤条件，空条件表示不过滤.
+type Filters struct {
+	// Effects 仅输出指定授权结果（allow/deny）的审计日志.
+	Effects []string `json:"effects"           mapstructure:"effects"`
+	// Usernames 仅输出指定用户的审计日志.
+	Usernames []string `json:"usernames"         mapstructure:"usernames"`
+	// SkippedUsernames 不输出指定用户的审计日志.
+	SkippedUsernames []string `json:"skipped-usernames" mapstructure:"skipped-usernames"`
+}
+
+// ShouldFilter 判断审计日志是否应该被过滤掉.
+func (f Filters) ShouldFilter(record *analytics.Record) bool {
+	if len(f.Effects) > 0 && !contains(f.Effects, record.Effect) {
+		return true
+	}
+	if len(f.Usernames) > 0 && !contains(f.Usernames, record.Username) {
+		return true
+	}
+	if contains(f.SkippedUsernames, record.Username) {
+		return true
+	}
+
+	return false
+}
+
+// Filter 返回未被过滤掉的审计日志.
+func (f Filters) Filter(records []*analytics.Record) []*analytics.Record {
+	if len(f.Effects) == 0 && len(f.Usernames) == 0 && len(f.SkippedUsernames) == 0 {
+		return records
+	}
+
+	filtered := make([]*analytics.Record, 0, len(records))
+	for _, record := range records {
+		if !f.ShouldFilter(record) {
+			filtered = append(filtered, record)
+		}
+	}
+
+	return filtered
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
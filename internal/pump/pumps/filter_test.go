@@ -0,0 +1,32 @@
+package pumps
+
+import (
+	"testing"
+
+	"github.com/changaolee/skeleton/internal/authzserver/analytics"
+)
+
+func TestFiltersShouldFilter(t *testing.T) {
+	allowFoo := &analytics.Record{Username: "foo", Effect: "allow"}
+	denyBar := &analytics.Record{Username: "bar", Effect: "deny"}
+
+	testCases := []struct {
+		name    string
+		filters Filters
+		record  *analytics.Record
+		want    bool
+	}{
+		{name: "empty filters", filters: Filters{}, record: allowFoo, want: false},
+		{name: "only denies drops allow", filters: Filters{Effects: []string{"deny"}}, record: allowFoo, want: true},
+		{name: "only denies keeps deny", filters: Filters{Effects: []string{"deny"}}, record: denyBar, want: false},
+		{name: "only users keeps user", filters: Filters{Usernames: []string{"foo"}}, record: allowFoo, want: false},
+		{name: "only users drops others", filters: Filters{Usernames: []string{"foo"}}, record: denyBar, want: true},
+		{name: "skipped users", filters: Filters{SkippedUsernames: []string{"bar"}}, record: denyBar, want: true},
+	}
+
+	for _, tc := range testCases {
+		if got := tc.filters.ShouldFilter(tc.record); got != tc.want {
+			t.Errorf("%s: ShouldFilter() = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package pumps
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/changaolee/skeleton/internal/authzserver/analytics"
+	genoptions "github.com/changaolee/skeleton/internal/pkg/options"
+	"github.com/changaolee/skeleton/pkg/db"
+)
+
+// MySQLConf 定义了 MySQL 输出端的配置.
+type MySQLConf struct {
+	genoptions.MySQLOptions `mapstructure:",squash"`
+	TableName               string `mapstructure:"table-name"`
+	BatchSize               int    `mapstructure:"batch-size"`
+}
+
+// AuditRecord 是数据库中 audit 记录 struct 格式的映射.
+type AuditRecord struct {
+	ID         uint64     `gorm:"primary_key;AUTO_INCREMENT;column:id"`
+	TimeStamp  int64      `gorm:"column:timestamp"`
+	Username   string     `gorm:"column:username"`
+	Effect     string     `gorm:"column:effect"`
+	Conclusion string     `gorm:"column:conclusion"`
+	Request    string     `gorm:"column:request"`
+	Policies   string     `gorm:"column:policies"`
+	Deciders   string     `gorm:"column:deciders"`
+	ExpireAt   *time.Time `gorm:"column:expireAt"`
+}
+
+// MySQLPump 将审计日志写入 MySQL 审计表.
+type MySQLPump struct {
+	conf *MySQLConf
+	db   *gorm.DB
+}
+
+var _ Pump = (*MySQLPump)(nil)
+
+func (p *MySQLPump) Name() string {
+	return "mysql"
+}
+
+func (p *MySQLPump) Init(meta map[string]interface{}) error {
+	conf := &MySQLConf{
+		MySQLOptions: *genoptions.NewMySQLOptions(),
+		TableName:    "audit",
+		BatchSize:    100,
+	}
+	if err := decodeMeta(meta, conf); err != nil {
+		return fmt.Errorf("failed to decode mysql pump meta: %w", err)
+	}
+
+	ins, err := db.NewMySQL(&db.MySQLOptions{
+		Host:                  conf.Host,
+		Username:              conf.Username,
+		Password:              conf.Password,
+		Database:              conf.Database,
+		MaxIdleConnections:    conf.MaxIdleConnections,
+		MaxOpenConnections:    conf.MaxOpenConnections,
+		MaxConnectionLifeTime: conf.MaxConnectionLifeTime,
+		LogLevel:              conf.LogLevel,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect mysql: %w", err)
+	}
+
+	p.conf = conf
+	p.db = ins
+
+	return nil
+}
+
+func (p *MySQLPump) WriteData(ctx context.Context, records []*analytics.Record) error {
+	rows := make([]*AuditRecord, 0, len(records))
+	for _, record := range records {
+		row := &AuditRecord{
+			TimeStamp:  record.TimeStamp,
+			Username:   record.Username,
+			Effect:     record.Effect,
+			Conclusion: record.Conclusion,
+			Request:    record.Request,
+			Policies:   record.Policies,
+			Deciders:   record.Deciders,
+		}
+		if !record.ExpireAt.IsZero() {
+			expireAt := record.ExpireAt
+			row.ExpireAt = &expireAt
+		}
+		rows = append(rows, row)
+	}
+
+	return p.db.WithContext(ctx).Table(p.conf.TableName).CreateInBatches(rows, p.conf.BatchSize).Error
+}
+
+func (p *MySQLPump) Close() error {
+	conn, err := p.db.DB()
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
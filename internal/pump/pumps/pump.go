@@ -0,0 +1,66 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package pumps
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/changaolee/skeleton/internal/authzserver/analytics"
+)
+
+// Pump 定义了授权审计日志的输出端.
+type Pump interface {
+	// Name 返回输出端的名称.
+	Name() string
+	// Init 使用配置文件中的 meta 信息初始化输出端.
+	Init(meta map[string]interface{}) error
+	// WriteData 将一批审计日志写入输出端.
+	WriteData(ctx context.Context, records []*analytics.Record) error
+	// Close 释放输出端持有的资源.
+	Close() error
+}
+
+// availablePumps 记录了所有支持的输出端类型.
+var availablePumps = map[string]func() Pump{
+	"stdout":  func() Pump { return &StdoutPump{} },
+	"file":    func() Pump { return &FilePump{} },
+	"webhook": func() Pump { return &WebhookPump{} },
+	"mysql":   func() Pump { return &MySQLPump{} },
+}
+
+// New 根据类型创建一个未初始化的输出端.
+func New(typ string) (Pump, error) {
+	newFunc, ok := availablePumps[typ]
+	if !ok {
+		return nil, fmt.Errorf("unsupported pump type: %s", typ)
+	}
+
+	return newFunc(), nil
+}
+
+// IsSupported 判断是否支持指定类型的输出端.
+func IsSupported(typ string) bool {
+	_, ok := availablePumps[typ]
+
+	return ok
+}
+
+// decodeMeta 将 meta 信息解码到输出端的配置结构体中.
+func decodeMeta(meta map[string]interface{}, conf interface{}) error {
+	decoder, err := mapstructure.NewDecoder(&mapstructure.DecoderConfig{
+		DecodeHook:       mapstructure.StringToTimeDurationHookFunc(),
+		WeaklyTypedInput: true,
+		Result:           conf,
+	})
+	if err != nil {
+		return err
+	}
+
+	return decoder.Decode(meta)
+}
@@ -0,0 +1,47 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package pumps
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/changaolee/skeleton/internal/authzserver/analytics"
+)
+
+// StdoutPump 将审计日志以 JSON 行的形式输出到标准输出.
+type StdoutPump struct {
+	out io.Writer
+}
+
+var _ Pump = (*StdoutPump)(nil)
+
+func (p *StdoutPump) Name() string {
+	return "stdout"
+}
+
+func (p *StdoutPump) Init(meta map[string]interface{}) error {
+	p.out = os.Stdout
+
+	return nil
+}
+
+func (p *StdoutPump) WriteData(ctx context.Context, records []*analytics.Record) error {
+	enc := json.NewEncoder(p.out)
+	for _, record := range records {
+		if err := enc.Encode(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *StdoutPump) Close() error {
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package pumps
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/changaolee/skeleton/internal/authzserver/analytics"
+)
+
+// WebhookConf 定义了 HTTP Webhook 输出端的配置.
+type WebhookConf struct {
+	URL     string            `mapstructure:"url"`
+	Method  string            `mapstructure:"method"`
+	Headers map[string]string `mapstructure:"headers"`
+}
+
+// WebhookPump 将一批审计日志以 JSON 数组的形式发送到 HTTP Webhook.
+type WebhookPump struct {
+	conf   *WebhookConf
+	client *http.Client
+}
+
+var _ Pump = (*WebhookPump)(nil)
+
+func (p *WebhookPump) Name() string {
+	return "webhook"
+}
+
+func (p *WebhookPump) Init(meta map[string]interface{}) error {
+	conf := &WebhookConf{
+		Method: http.MethodPost,
+	}
+	if err := decodeMeta(meta, conf); err != nil {
+		return fmt.Errorf("failed to decode webhook pump meta: %w", err)
+	}
+	if conf.URL == "" {
+		return fmt.Errorf("webhook pump requires meta.url")
+	}
+
+	p.conf = conf
+	p.client = &http.Client{}
+
+	return nil
+}
+
+func (p *WebhookPump) WriteData(ctx context.Context, records []*analytics.Record) error {
+	data, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, p.conf.Method, p.conf.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range p.conf.Headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+func (p *WebhookPump) Close() error {
+	p.client.CloseIdleConnections()
+
+	return nil
+}
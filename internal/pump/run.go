@@ -0,0 +1,18 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package pump
+
+import (
+	"github.com/changaolee/skeleton/internal/pump/config"
+)
+
+func Run(cfg *config.Config) error {
+	server, err := createPumpServer(cfg)
+	if err != nil {
+		return err
+	}
+	return server.PrepareRun().Run()
+}
@@ -0,0 +1,360 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package pump
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/changaolee/skeleton/internal/authzserver/analytics"
+	"github.com/changaolee/skeleton/internal/pump/config"
+	"github.com/changaolee/skeleton/internal/pump/pumps"
+	"github.com/changaolee/skeleton/internal/pump/storage"
+	"github.com/changaolee/skeleton/pkg/log"
+	"github.com/changaolee/skeleton/pkg/shutdown"
+	"github.com/changaolee/skeleton/pkg/shutdown/managers/posixsignal"
+	"github.com/changaolee/skeleton/pkg/util/idutil"
+)
+
+// pumpLockKey 是多个 skt-pump 实例之间互斥处理审计日志的分布式锁.
+const pumpLockKey = "skt-pump-lock"
+
+// deadLetterKeyPrefix 是保存写入输出端失败的审计日志的 Redis 列表名称前缀，每个输出端一个列表.
+const deadLetterKeyPrefix = analytics.AnalyticsKeyName + ":dead-letter:"
+
+// recordStorage 定义了保存审计日志和分布式锁的存储.
+type recordStorage interface {
+	ReadList(ctx context.Context, key string, count int64) ([][]byte, error)
+	TrimList(ctx context.Context, key string, count int64) error
+	PushList(ctx context.Context, key string, values [][]byte) error
+	AcquireLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error)
+	RenewLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error)
+	ReleaseLock(ctx context.Context, key, token string) error
+	Close() error
+}
+
+type pumpEntry struct {
+	pumps.Pump
+	name    string
+	filters pumps.Filters
+	timeout time.Duration
+}
+
+// deadLetterKey 返回输出端的死信列表名称.
+func (p *pumpEntry) deadLetterKey() string {
+	return deadLetterKeyPrefix + p.name
+}
+
+type pumpServer struct {
+	gs          *shutdown.GracefulShutdown
+	cfg         *config.Config
+	storage     recordStorage
+	serializer  analytics.Serializer
+	pumps       []*pumpEntry
+	lockToken   string
+	cancelFunc  context.CancelFunc
+	ctx         context.Context
+	stoppedChan chan struct{}
+}
+
+type preparedPumpServer struct {
+	*pumpServer
+}
+
+func createPumpServer(cfg *config.Config) (*pumpServer, error) {
+	// 优雅关闭组件
+	gs := shutdown.New()
+	gs.AddManager(posixsignal.NewPosixSignalManager())
+
+	// Redis 实例
+	redisStorage, err := storage.NewRedisStorage(cfg.RedisOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	// 审计日志输出端
+	entries := make([]*pumpEntry, 0, len(cfg.Pumps))
+	for name, pc := range cfg.Pumps {
+		p, err := pumps.New(pc.Type)
+		if err != nil {
+			return nil, fmt.Errorf("pumps.%s: %w", name, err)
+		}
+		if err := p.Init(pc.Meta); err != nil {
+			return nil, fmt.Errorf("pumps.%s: init failed: %w", name, err)
+		}
+		log.Infof("Init pump %s(%s) success", name, p.Name())
+
+		entries = append(entries, &pumpEntry{Pump: p, name: name, filters: pc.Filters, timeout: pc.Timeout})
+	}
+
+	hostname, _ := os.Hostname()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	server := &pumpServer{
+		gs:          gs,
+		cfg:         cfg,
+		storage:     redisStorage,
+		serializer:  analytics.NewSerializer(cfg.Serializer),
+		pumps:       entries,
+		lockToken:   idutil.GetUUID36(hostname + "-"),
+		ctx:         ctx,
+		cancelFunc:  cancel,
+		stoppedChan: make(chan struct{}),
+	}
+
+	return server, nil
+}
+
+func (s *pumpServer) PrepareRun() *preparedPumpServer {
+	s.gs.AddCallback(shutdown.CallbackFunc(func(string) error {
+		// 通知主循环退出，并等待剩余的审计日志处理完成
+		s.cancelFunc()
+		<-s.stoppedChan
+
+		return nil
+	}))
+
+	return &preparedPumpServer{s}
+}
+
+func (s *preparedPumpServer) Run() error {
+	// 启动 shutdown 监听
+	if err := s.gs.Start(); err != nil {
+		log.Fatalf("Start shutdown manager failed: %s", err.Error())
+	}
+
+	defer close(s.stoppedChan)
+
+	log.Infof("Starting purge loop @%s, batch size: %d", s.cfg.PurgeDelay, s.cfg.BatchSize)
+
+	ticker := time.NewTicker(s.cfg.PurgeDelay)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// 不使用会被取消的 s.ctx，避免写入输出端的过程被中途打断
+			s.purge(context.Background())
+		case <-s.ctx.Done():
+			// 退出前最后处理一次 Redis 中的审计日志
+			ctx, cancel := context.WithTimeout(context.Background(), s.cfg.LockTimeout)
+			s.purge(ctx)
+			cancel()
+			s.close()
+
+			log.Infof("Pump server stopped")
+
+			return nil
+		}
+	}
+}
+
+// purge 获取分布式锁后，从 Redis 中读取审计日志并发送到所有输出端.
+// 审计日志写入所有输出端，或者写入失败的部分保存到死信列表后，才会从 Redis 中删除.
+// 写入输出端的时间可能超过锁的过期时间，每次删除前都续期锁，续期失败说明其他实例可能已经读取了
+// 同一批审计日志，此时不删除并结束本轮处理，由持有锁的实例处理，审计日志可能重复但不会丢失.
+func (s *pumpServer) purge(ctx context.Context) {
+	acquired, err := s.storage.AcquireLock(ctx, pumpLockKey, s.lockToken, s.cfg.LockTimeout)
+	if err != nil {
+		log.Errorf("Acquire pump lock failed: %s", err.Error())
+		return
+	}
+	if !acquired {
+		log.Debugf("Pump lock is held by another instance, skip this round")
+		return
+	}
+	defer func() {
+		if err := s.storage.ReleaseLock(context.Background(), pumpLockKey, s.lockToken); err != nil {
+			log.Errorf("Release pump lock failed: %s", err.Error())
+		}
+	}()
+
+	// 先重试之前写入失败的审计日志
+	for _, p := range s.pumps {
+		if !s.retryDeadLetters(ctx, p) {
+			return
+		}
+	}
+
+	for {
+		values, err := s.storage.ReadList(ctx, analytics.AnalyticsKeyName, s.cfg.BatchSize)
+		if err != nil {
+			log.Errorf("Read analytics records failed: %s", err.Error())
+			return
+		}
+		if len(values) == 0 {
+			return
+		}
+
+		if err := s.writeToPumps(ctx, s.decode(values)); err != nil {
+			log.Errorf("Keep %d analytics records in redis for next round: %s", len(values), err.Error())
+			return
+		}
+		if !s.renewLock(ctx) {
+			return
+		}
+		if err := s.storage.TrimList(ctx, analytics.AnalyticsKeyName, int64(len(values))); err != nil {
+			log.Errorf("Remove written analytics records failed: %s", err.Error())
+			return
+		}
+
+		if int64(len(values)) < s.cfg.BatchSize {
+			return
+		}
+	}
+}
+
+// retryDeadLetters 将输出端死信列表中的审计日志重新写入该输出端，写入成功后从死信列表中删除.
+// 失去分布式锁时返回 false.
+func (s *pumpServer) retryDeadLetters(ctx context.Context, p *pumpEntry) bool {
+	key := p.deadLetterKey()
+	for {
+		values, err := s.storage.ReadList(ctx, key, s.cfg.BatchSize)
+		if err != nil {
+			log.Errorf("Read dead letters of pump %s failed: %s", p.name, err.Error())
+			return true
+		}
+		if len(values) == 0 {
+			return true
+		}
+
+		records := s.decode(values)
+		if err := s.write(ctx, p, records); err != nil {
+			log.Errorf("Pump %s retry %d records failed: %s", p.name, len(records), err.Error())
+			return true
+		}
+		if !s.renewLock(ctx) {
+			return false
+		}
+		if err := s.storage.TrimList(ctx, key, int64(len(values))); err != nil {
+			log.Errorf("Remove dead letters of pump %s failed: %s", p.name, err.Error())
+			return true
+		}
+
+		if int64(len(values)) < s.cfg.BatchSize {
+			return true
+		}
+	}
+}
+
+// renewLock 续期分布式锁，返回当前实例是否仍然持有锁.
+func (s *pumpServer) renewLock(ctx context.Context) bool {
+	renewed, err := s.storage.RenewLock(ctx, pumpLockKey, s.lockToken, s.cfg.LockTimeout)
+	if err != nil {
+		log.Errorf("Renew pump lock failed: %s", err.Error())
+		return false
+	}
+	if !renewed {
+		log.Warnf("Pump lock expired while processing records, leave them to the current lock holder")
+	}
+
+	return renewed
+}
+
+// decode 反序列化审计日志，并丢弃已经过期的记录.
+func (s *pumpServer) decode(values [][]byte) []*analytics.Record {
+	now := time.Now()
+	records := make([]*analytics.Record, 0, len(values))
+	for _, v := range values {
+		record := &analytics.Record{}
+		if err := s.serializer.Decode(v, record); err != nil {
+			log.Errorf("Decode analytics record failed: %s", err.Error())
+			continue
+		}
+		if record.Expired(now) {
+			continue
+		}
+		records = append(records, record)
+	}
+
+	return records
+}
+
+// writeToPumps 将审计日志写入所有输出端，写入失败的审计日志保存到对应输出端的死信列表.
+// 只有保存到死信列表也失败时才返回错误，此时这批审计日志需要保留在 Redis 中重新处理.
+func (s *pumpServer) writeToPumps(ctx context.Context, records []*analytics.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var failed []string
+	for _, p := range s.pumps {
+		wg.Add(1)
+		go func(p *pumpEntry) {
+			defer wg.Done()
+
+			filtered := p.filters.Filter(records)
+			err := s.write(ctx, p, filtered)
+			if err == nil {
+				return
+			}
+			log.Errorf("Pump %s write %d records failed: %s", p.name, len(filtered), err.Error())
+
+			if err := s.pushDeadLetters(ctx, p, filtered); err != nil {
+				log.Errorf("Save dead letters of pump %s failed: %s", p.name, err.Error())
+				mu.Lock()
+				failed = append(failed, p.name)
+				mu.Unlock()
+			}
+		}(p)
+	}
+	wg.Wait()
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to write records to pumps: %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// write 将审计日志写入一个输出端.
+func (s *pumpServer) write(ctx context.Context, p *pumpEntry, records []*analytics.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	if p.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.timeout)
+		defer cancel()
+	}
+
+	if err := p.WriteData(ctx, records); err != nil {
+		return err
+	}
+	log.Debugf("Pump %s wrote %d records", p.name, len(records))
+
+	return nil
+}
+
+// pushDeadLetters 将写入输出端失败的审计日志保存到该输出端的死信列表，下次处理时重试.
+func (s *pumpServer) pushDeadLetters(ctx context.Context, p *pumpEntry, records []*analytics.Record) error {
+	values := make([][]byte, 0, len(records))
+	for _, record := range records {
+		v, err := s.serializer.Encode(record)
+		if err != nil {
+			return err
+		}
+		values = append(values, v)
+	}
+
+	return s.storage.PushList(ctx, p.deadLetterKey(), values)
+}
+
+func (s *pumpServer) close() {
+	for _, p := range s.pumps {
+		if err := p.Close(); err != nil {
+			log.Warnf("Close pump %s failed: %s", p.Name(), err.Error())
+		}
+	}
+	_ = s.storage.Close()
+}
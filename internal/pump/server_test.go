@@ -0,0 +1,130 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package pump
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/changaolee/skeleton/internal/authzserver/analytics"
+	"github.com/changaolee/skeleton/internal/pump/config"
+	"github.com/changaolee/skeleton/internal/pump/options"
+)
+
+type fakeStorage struct {
+	lists    map[string][][]byte
+	pushErr  error
+	lockLost bool
+}
+
+func (s *fakeStorage) ReadList(_ context.Context, key string, count int64) ([][]byte, error) {
+	values := s.lists[key]
+	if int64(len(values)) > count {
+		values = values[:count]
+	}
+	return values, nil
+}
+
+func (s *fakeStorage) TrimList(_ context.Context, key string, count int64) error {
+	s.lists[key] = s.lists[key][count:]
+	return nil
+}
+
+func (s *fakeStorage) PushList(_ context.Context, key string, values [][]byte) error {
+	if s.pushErr != nil {
+		return s.pushErr
+	}
+	s.lists[key] = append(s.lists[key], values...)
+	return nil
+}
+
+func (s *fakeStorage) AcquireLock(context.Context, string, string, time.Duration) (bool, error) {
+	return true, nil
+}
+
+func (s *fakeStorage) RenewLock(context.Context, string, string, time.Duration) (bool, error) {
+	return !s.lockLost, nil
+}
+
+func (s *fakeStorage) ReleaseLock(context.Context, string, string) error { return nil }
+
+func (s *fakeStorage) Close() error { return nil }
+
+type fakePump struct {
+	err     error
+	written []*analytics.Record
+}
+
+func (p *fakePump) Name() string                      { return "fake" }
+func (p *fakePump) Init(map[string]interface{}) error { return nil }
+func (p *fakePump) Close() error                      { return nil }
+
+func (p *fakePump) WriteData(_ context.Context, records []*analytics.Record) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.written = append(p.written, records...)
+	return nil
+}
+
+func TestPurge_KeepsFailedRecords(t *testing.T) {
+	opts := options.NewOptions()
+	opts.Serializer = "json"
+	opts.BatchSize = 10
+
+	serializer := analytics.NewSerializer(opts.Serializer)
+	var values [][]byte
+	for _, username := range []string{"alice", "bob"} {
+		v, _ := serializer.Encode(&analytics.Record{TimeStamp: time.Now().Unix(), Username: username})
+		values = append(values, v)
+	}
+
+	st := &fakeStorage{lists: map[string][][]byte{analytics.AnalyticsKeyName: values}}
+	good, bad := &fakePump{}, &fakePump{err: errors.New("connection refused")}
+	s := &pumpServer{
+		cfg:        &config.Config{Options: opts},
+		storage:    st,
+		serializer: serializer,
+		pumps:      []*pumpEntry{{Pump: good, name: "good"}, {Pump: bad, name: "bad"}},
+	}
+	deadLetters := func() int { return len(st.lists[deadLetterKeyPrefix+"bad"]) }
+
+	// 死信列表不可用时，审计日志保留在 Redis 中
+	st.pushErr = errors.New("connection refused")
+	s.purge(context.Background())
+	if len(st.lists[analytics.AnalyticsKeyName]) != 2 {
+		t.Fatalf("records left = %d, want 2", len(st.lists[analytics.AnalyticsKeyName]))
+	}
+
+	// 处理过程中锁过期时，不删除其他实例可能正在处理的审计日志
+	st.pushErr = nil
+	st.lockLost = true
+	s.purge(context.Background())
+	if len(st.lists[analytics.AnalyticsKeyName]) != 2 {
+		t.Fatalf("records left after losing the lock = %d, want 2", len(st.lists[analytics.AnalyticsKeyName]))
+	}
+	st.lockLost = false
+	st.lists[deadLetterKeyPrefix+"bad"] = nil
+
+	// 写入失败的审计日志保存到输出端的死信列表
+	st.pushErr = nil
+	good.written = nil
+	s.purge(context.Background())
+	if len(st.lists[analytics.AnalyticsKeyName]) != 0 || deadLetters() != 2 || len(good.written) != 2 {
+		t.Fatalf("records left = %d, dead letters = %d, written = %d, want 0, 2, 2",
+			len(st.lists[analytics.AnalyticsKeyName]), deadLetters(), len(good.written))
+	}
+
+	// 输出端恢复后只重试该输出端
+	bad.err = nil
+	s.purge(context.Background())
+	if deadLetters() != 0 || len(bad.written) != 2 || len(good.written) != 2 {
+		t.Errorf("dead letters = %d, retried = %d, written = %d, want 0, 2, 2",
+			deadLetters(), len(bad.written), len(good.written))
+	}
+}
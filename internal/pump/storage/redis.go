@@ -0,0 +1,133 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	genoptions "github.com/changaolee/skeleton/internal/pkg/options"
+	"github.com/changaolee/skeleton/pkg/db"
+)
+
+// releaseLockScript 仅当锁的持有者为当前实例时才释放锁.
+var releaseLockScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+else
+	return 0
+end
+`)
+
+// renewLockScript 仅当锁的持有者为当前实例时才延长锁的过期时间.
+var renewLockScript = redis.NewScript(`
+if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+else
+	return 0
+end
+`)
+
+// RedisStorage 实现了从 Redis 中读取审计日志和分布式锁.
+type RedisStorage struct {
+	rd *redis.Client
+}
+
+// NewRedisStorage 创建一个 RedisStorage 实例.
+func NewRedisStorage(opts *genoptions.RedisOptions) (*RedisStorage, error) {
+	rd, err := db.NewRedis(&db.RedisOptions{
+		Host:     opts.Host,
+		Port:     opts.Port,
+		Username: opts.Username,
+		Password: opts.Password,
+		Database: opts.Database,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create redis client: %w", err)
+	}
+
+	return &RedisStorage{rd: rd}, nil
+}
+
+// ReadList 读取 Redis 列表头部的最多 count 个元素，不会删除这些元素.
+func (r *RedisStorage) ReadList(ctx context.Context, key string, count int64) ([][]byte, error) {
+	vals, err := r.rd.LRange(ctx, key, 0, count-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read redis list %s: %w", key, err)
+	}
+
+	result := make([][]byte, 0, len(vals))
+	for _, v := range vals {
+		result = append(result, []byte(v))
+	}
+
+	return result, nil
+}
+
+// TrimList 删除 Redis 列表头部的 count 个元素，用于确认 ReadList 读取的元素已经处理完成.
+func (r *RedisStorage) TrimList(ctx context.Context, key string, count int64) error {
+	if err := r.rd.LTrim(ctx, key, count, -1).Err(); err != nil {
+		return fmt.Errorf("failed to trim redis list %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// PushList 将元素追加到 Redis 列表的尾部.
+func (r *RedisStorage) PushList(ctx context.Context, key string, values [][]byte) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(values))
+	for _, v := range values {
+		args = append(args, v)
+	}
+	if err := r.rd.RPush(ctx, key, args...).Err(); err != nil {
+		return fmt.Errorf("failed to push redis list %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// AcquireLock 尝试获取分布式锁，token 用于标识锁的持有者.
+func (r *RedisStorage) AcquireLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	ok, err := r.rd.SetNX(ctx, key, token, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lock %s: %w", key, err)
+	}
+
+	return ok, nil
+}
+
+// RenewLock 延长当前实例持有的分布式锁的过期时间，锁已过期或被其他实例持有时返回 false.
+func (r *RedisStorage) RenewLock(ctx context.Context, key, token string, ttl time.Duration) (bool, error) {
+	n, err := renewLockScript.Run(ctx, r.rd, []string{key}, token, ttl.Milliseconds()).Int()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return false, fmt.Errorf("failed to renew lock %s: %w", key, err)
+	}
+
+	return n == 1, nil
+}
+
+// ReleaseLock 释放当前实例持有的分布式锁.
+func (r *RedisStorage) ReleaseLock(ctx context.Context, key, token string) error {
+	err := releaseLockScript.Run(ctx, r.rd, []string{key}, token).Err()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to release lock %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// Close 关闭 Redis 连接.
+func (r *RedisStorage) Close() error {
+	return r.rd.Close()
+}
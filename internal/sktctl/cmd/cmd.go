@@ -10,6 +10,8 @@ import (
 	"io"
 	"os"
 
+	"github.com/changaolee/skeleton/internal/sktctl/cmd/policy"
+	"github.com/changaolee/skeleton/internal/sktctl/cmd/secret"
 	"github.com/changaolee/skeleton/internal/sktctl/cmd/user"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -81,6 +83,8 @@ func NewSKTCtlCommand(in io.Reader, out, err io.Writer) *cobra.Command {
 			Message: "Identity and Access Management Commands:",
 			Commands: []*cobra.Command{
 				user.NewCmdUser(f, ioStreams),
+				secret.NewCmdSecret(f, ioStreams),
+				policy.NewCmdPolicy(f, ioStreams),
 			},
 		},
 	}
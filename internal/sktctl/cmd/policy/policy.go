@@ -0,0 +1,144 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/changaolee/skeleton/internal/pkg/clioptions"
+	mp "github.com/changaolee/skeleton/internal/pkg/model/policy"
+	"github.com/changaolee/skeleton/internal/sktctl/util"
+	"github.com/changaolee/skeleton/internal/sktctl/util/templates"
+	"github.com/olekukonko/tablewriter"
+	"github.com/ory/ladon"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var policyLong = templates.LongDesc(`
+	Authorization policy management commands.
+
+This commands allow you to manage your authorization policy on skt platform.`)
+
+func NewCmdPolicy(f util.Factory, ioStreams clioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "policy SUBCOMMAND",
+		DisableFlagsInUseLine: true,
+		Short:                 "Manage authorization policies on skt platform",
+		Long:                  policyLong,
+		Run:                   util.DefaultSubCommandRun(ioStreams.ErrOut),
+	}
+
+	cmd.AddCommand(NewCmdCreate(f, ioStreams))
+	cmd.AddCommand(NewCmdGet(f, ioStreams))
+	cmd.AddCommand(NewCmdList(f, ioStreams))
+	cmd.AddCommand(NewCmdDelete(f, ioStreams))
+	cmd.AddCommand(NewCmdUpdate(f, ioStreams))
+
+	return cmd
+}
+
+// setHeader set headers for policy commands.
+func setHeader(table *tablewriter.Table) *tablewriter.Table {
+	table.SetHeader([]string{"Name", "Effect", "Subjects", "Resources", "Actions", "Created"})
+	table.SetHeaderColor(tablewriter.Colors{tablewriter.FgGreenColor},
+		tablewriter.Colors{tablewriter.FgRedColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgMagentaColor},
+		tablewriter.Colors{tablewriter.FgGreenColor},
+		tablewriter.Colors{tablewriter.FgWhiteColor})
+
+	return table
+}
+
+// toRow converts a policy to a table row.
+func toRow(pol *mp.Policy) []string {
+	return []string{
+		pol.Name,
+		pol.Policy.Effect,
+		strings.Join(pol.Policy.Subjects, ","),
+		strings.Join(pol.Policy.Resources, ","),
+		strings.Join(pol.Policy.Actions, ","),
+		pol.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
+
+// policyFlags 定义了通过文件或者命令行参数指定 ladon 授权策略的选项.
+type policyFlags struct {
+	File        string
+	Description string
+	Effect      string
+	Subjects    []string
+	Resources   []string
+	Actions     []string
+	Conditions  string
+}
+
+func (p *policyFlags) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&p.File, "filename", "f", p.File, "The file that contains the ladon policy in JSON format, use '-' to read from stdin.")
+	fs.StringVar(&p.Description, "description", p.Description, "The description of the policy.")
+	fs.StringVar(&p.Effect, "effect", p.Effect, "The effect of the policy, allow or deny.")
+	fs.StringSliceVar(&p.Subjects, "subjects", p.Subjects, "The subjects of the policy.")
+	fs.StringSliceVar(&p.Resources, "resources", p.Resources, "The resources of the policy.")
+	fs.StringSliceVar(&p.Actions, "actions", p.Actions, "The actions of the policy.")
+	fs.StringVar(&p.Conditions, "conditions", p.Conditions, "The conditions of the policy in JSON format.")
+}
+
+// apply 将文件或者命令行参数中的授权策略应用到 ap 上.
+func (p *policyFlags) apply(fs *pflag.FlagSet, in io.Reader, ap *mp.AuthzPolicy) error {
+	if p.File != "" {
+		for _, name := range []string{"description", "effect", "subjects", "resources", "actions", "conditions"} {
+			if fs.Changed(name) {
+				return fmt.Errorf("--%s can not be used together with --filename", name)
+			}
+		}
+
+		data, err := readFile(p.File, in)
+		if err != nil {
+			return err
+		}
+
+		var policy mp.AuthzPolicy
+		if err := json.Unmarshal(data, &policy); err != nil {
+			return fmt.Errorf("failed to parse policy file %s: %w", p.File, err)
+		}
+		*ap = policy
+
+		return nil
+	}
+
+	if fs.Changed("description") {
+		ap.Description = p.Description
+	}
+	if fs.Changed("effect") {
+		ap.Effect = p.Effect
+	}
+	if fs.Changed("subjects") {
+		ap.Subjects = p.Subjects
+	}
+	if fs.Changed("resources") {
+		ap.Resources = p.Resources
+	}
+	if fs.Changed("actions") {
+		ap.Actions = p.Actions
+	}
+	if fs.Changed("conditions") {
+		conditions := ladon.Conditions{}
+		if err := json.Unmarshal([]byte(p.Conditions), &conditions); err != nil {
+			return fmt.Errorf("failed to parse conditions: %w", err)
+		}
+		ap.Conditions = conditions
+	}
+
+	return nil
+}
+
+func readFile(name string, in io.Reader) ([]byte, error) {
+	if name == "-" {
+		return io.ReadAll(in)
+	}
+
+	return os.ReadFile(name)
+}
@@ -0,0 +1,131 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/changaolee/skeleton/internal/pkg/clioptions"
+	mp "github.com/changaolee/skeleton/internal/pkg/model/policy"
+	"github.com/changaolee/skeleton/internal/sktctl/util"
+	"github.com/changaolee/skeleton/internal/sktctl/util/templates"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+	apiclientv1 "github.com/changaolee/skeleton/pkg/sdk/apiserver/v1"
+	"github.com/ory/ladon"
+	"github.com/spf13/cobra"
+)
+
+const (
+	createUsageStr = "create POLICY_NAME"
+)
+
+type CreateOptions struct {
+	policyFlags
+
+	Policy *mp.Policy
+
+	Client apiclientv1.APIV1Interface
+	clioptions.IOStreams
+}
+
+var (
+	createLong = templates.LongDesc(`Create an authorization policy resource.
+
+The ladon policy can be read from a JSON file by --filename, or be specified by flags.`)
+
+	createExample = templates.Examples(`
+		# Create an authorization policy from a file
+		sktctl policy create foo -f policy.json
+
+		# Create an authorization policy from flags
+		sktctl policy create foo --effect=allow --subjects=users:foo --resources=resources:articles:<.*> --actions=delete,update
+
+		# Create an authorization policy with conditions
+		sktctl policy create foo --subjects=users:foo --resources=resources:articles:<.*> --actions=delete \
+		  --conditions='{"owner":{"type":"EqualsSubjectCondition"}}'`)
+
+	createUsageErrStr = fmt.Sprintf("expected '%s'.\nPOLICY_NAME is required arguments for the create command", createUsageStr)
+)
+
+func NewCreateOptions(ioStreams clioptions.IOStreams) *CreateOptions {
+	return &CreateOptions{
+		policyFlags: policyFlags{
+			Effect: ladon.AllowAccess,
+		},
+		IOStreams: ioStreams,
+	}
+}
+
+func NewCmdCreate(f util.Factory, ioStreams clioptions.IOStreams) *cobra.Command {
+	o := NewCreateOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   createUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Create an authorization policy resource",
+		TraverseChildren:      true,
+		Long:                  createLong,
+		Example:               createExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Complete(f, cmd, args))
+			util.CheckErr(o.Validate(cmd, args))
+			util.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	o.policyFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *CreateOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return util.UsageErrorf(cmd, createUsageErrStr)
+	}
+
+	o.Policy = &mp.Policy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: args[0],
+		},
+		Policy: mp.AuthzPolicy{
+			DefaultPolicy: ladon.DefaultPolicy{
+				Effect: o.Effect,
+			},
+		},
+	}
+	if err := o.policyFlags.apply(cmd.Flags(), o.In, &o.Policy.Policy); err != nil {
+		return err
+	}
+
+	clientConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.Client, err = apiclientv1.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *CreateOptions) Validate(cmd *cobra.Command, args []string) error {
+	if errs := o.Policy.Validate(); len(errs) != 0 {
+		return errs.ToAggregate()
+	}
+
+	return nil
+}
+
+func (o *CreateOptions) Run(args []string) error {
+	ret, err := o.Client.Policies().Create(context.TODO(), o.Policy)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(o.Out, "policy/%s created\n", ret.Name)
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package policy
+
+import (
+	"context"
+
+	"github.com/AlekSi/pointer"
+	"github.com/changaolee/skeleton/internal/pkg/clioptions"
+	"github.com/changaolee/skeleton/internal/sktctl/util"
+	"github.com/changaolee/skeleton/internal/sktctl/util/templates"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+	apiclientv1 "github.com/changaolee/skeleton/pkg/sdk/apiserver/v1"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultLimit = 1000
+)
+
+type ListOptions struct {
+	Offset int64
+	Limit  int64
+
+	Client apiclientv1.APIV1Interface
+	clioptions.IOStreams
+}
+
+var listExample = templates.Examples(`
+		# List all policies
+		sktctl policy list
+
+		# List policies with limit and offset
+		sktctl policy list --offset=0 --limit=5`)
+
+func NewListOptions(ioStreams clioptions.IOStreams) *ListOptions {
+	return &ListOptions{
+		IOStreams: ioStreams,
+		Offset:    0,
+		Limit:     defaultLimit,
+	}
+}
+
+func NewCmdList(f util.Factory, ioStreams clioptions.IOStreams) *cobra.Command {
+	o := NewListOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   "list",
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Display all policy resources.",
+		TraverseChildren:      true,
+		Long:                  `Display all policy resources.`,
+		Example:               listExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Complete(f, cmd, args))
+			util.CheckErr(o.Validate(cmd, args))
+			util.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	cmd.Flags().Int64VarP(&o.Offset, "offset", "o", o.Offset, "Specify the offset of the first row to be returned.")
+	cmd.Flags().Int64VarP(&o.Limit, "limit", "l", o.Limit, "Specify the amount records to be returned.")
+
+	return cmd
+}
+
+func (o *ListOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	clientConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.Client, err = apiclientv1.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *ListOptions) Validate(cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+func (o *ListOptions) Run(args []string) error {
+	policies, err := o.Client.Policies().List(context.TODO(), metav1.ListOptions{
+		Offset: pointer.ToInt64(o.Offset),
+		Limit:  pointer.ToInt64(o.Limit),
+	})
+	if err != nil {
+		return err
+	}
+
+	data := make([][]string, 0, len(policies.Items))
+	for _, pol := range policies.Items {
+		data = append(data, toRow(pol))
+	}
+
+	table := tablewriter.NewWriter(o.Out)
+	table = setHeader(table)
+	table = util.TableWriterDefaultConfig(table)
+	table.AppendBulk(data)
+	table.Render()
+
+	return nil
+}
@@ -0,0 +1,117 @@
+package policy
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/changaolee/skeleton/internal/pkg/clioptions"
+	"github.com/changaolee/skeleton/internal/sktctl/util"
+	"github.com/changaolee/skeleton/internal/sktctl/util/templates"
+	apiclientv1 "github.com/changaolee/skeleton/pkg/sdk/apiserver/v1"
+	"github.com/spf13/cobra"
+)
+
+const (
+	updateUsageStr = "update POLICY_NAME"
+)
+
+type UpdateOptions struct {
+	policyFlags
+
+	Name string
+
+	Client apiclientv1.APIV1Interface
+	clioptions.IOStreams
+}
+
+var (
+	updateLong = templates.LongDesc(`Update an authorization policy resource.
+
+With --filename the whole ladon policy is replaced, otherwise only the fields specified by flags are updated.`)
+
+	updateExample = templates.Examples(`
+		# Update an authorization policy from a file
+		sktctl policy update foo -f policy.json
+
+		# Update the actions of an authorization policy
+		sktctl policy update foo --actions=get,list`)
+
+	updateUsageErrStr = fmt.Sprintf("expected '%s'.\nPOLICY_NAME is required arguments for the update command", updateUsageStr)
+)
+
+func NewUpdateOptions(ioStreams clioptions.IOStreams) *UpdateOptions {
+	return &UpdateOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+func NewCmdUpdate(f util.Factory, ioStreams clioptions.IOStreams) *cobra.Command {
+	o := NewUpdateOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   updateUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Update an authorization policy resource",
+		TraverseChildren:      true,
+		Long:                  updateLong,
+		Example:               updateExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Complete(f, cmd, args))
+			util.CheckErr(o.Validate(cmd, args))
+			util.CheckErr(o.Run(cmd, args))
+		},
+		SuggestFor: []string{},
+	}
+
+	o.policyFlags.AddFlags(cmd.Flags())
+
+	return cmd
+}
+
+func (o *UpdateOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return util.UsageErrorf(cmd, updateUsageErrStr)
+	}
+
+	o.Name = args[0]
+
+	clientConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.Client, err = apiclientv1.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *UpdateOptions) Validate(cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+func (o *UpdateOptions) Run(cmd *cobra.Command, args []string) error {
+	pol, err := o.Client.Policies().Get(context.TODO(), o.Name)
+	if err != nil {
+		return err
+	}
+
+	if err := o.policyFlags.apply(cmd.Flags(), o.In, &pol.Policy); err != nil {
+		return err
+	}
+	if errs := pol.Validate(); len(errs) != 0 {
+		return errs.ToAggregate()
+	}
+
+	ret, err := o.Client.Policies().Update(context.TODO(), pol)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(o.Out, "policy/%s updated\n", ret.Name)
+
+	return nil
+}
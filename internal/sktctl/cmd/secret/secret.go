@@ -0,0 +1,63 @@
+package secret
+
+import (
+	"time"
+
+	"github.com/changaolee/skeleton/internal/pkg/clioptions"
+	ms "github.com/changaolee/skeleton/internal/pkg/model/secret"
+	"github.com/changaolee/skeleton/internal/sktctl/util"
+	"github.com/changaolee/skeleton/internal/sktctl/util/templates"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var secretLong = templates.LongDesc(`
+	Secret management commands.
+
+This commands allow you to manage your secret on skt platform.`)
+
+func NewCmdSecret(f util.Factory, ioStreams clioptions.IOStreams) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:                   "secret SUBCOMMAND",
+		DisableFlagsInUseLine: true,
+		Short:                 "Manage secrets on skt platform",
+		Long:                  secretLong,
+		Run:                   util.DefaultSubCommandRun(ioStreams.ErrOut),
+	}
+
+	cmd.AddCommand(NewCmdCreate(f, ioStreams))
+	cmd.AddCommand(NewCmdGet(f, ioStreams))
+	cmd.AddCommand(NewCmdList(f, ioStreams))
+	cmd.AddCommand(NewCmdDelete(f, ioStreams))
+	cmd.AddCommand(NewCmdUpdate(f, ioStreams))
+
+	return cmd
+}
+
+// setHeader set headers for secret commands.
+func setHeader(table *tablewriter.Table) *tablewriter.Table {
+	table.SetHeader([]string{"Name", "SecretID", "SecretKey", "Expires", "Created"})
+	table.SetHeaderColor(tablewriter.Colors{tablewriter.FgGreenColor},
+		tablewriter.Colors{tablewriter.FgRedColor},
+		tablewriter.Colors{tablewriter.FgCyanColor},
+		tablewriter.Colors{tablewriter.FgMagentaColor},
+		tablewriter.Colors{tablewriter.FgGreenColor})
+
+	return table
+}
+
+// toRow converts a secret to a table row.
+func toRow(secret *ms.Secret) []string {
+	expires := "Never"
+	if secret.Expires != 0 {
+		expires = time.Unix(secret.Expires, 0).Format("2006-01-02 15:04:05")
+	}
+
+	return []string{
+		secret.Name,
+		secret.SecretID,
+		secret.SecretKey,
+		expires,
+		secret.CreatedAt.Format("2006-01-02 15:04:05"),
+	}
+}
@@ -0,0 +1,122 @@
+package secret
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/changaolee/skeleton/internal/pkg/clioptions"
+	ms "github.com/changaolee/skeleton/internal/pkg/model/secret"
+	"github.com/changaolee/skeleton/internal/sktctl/util"
+	"github.com/changaolee/skeleton/internal/sktctl/util/templates"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+	apiclientv1 "github.com/changaolee/skeleton/pkg/sdk/apiserver/v1"
+	"github.com/spf13/cobra"
+)
+
+const (
+	createUsageStr = "create SECRET_NAME"
+)
+
+type CreateOptions struct {
+	Description string
+	Expires     time.Duration
+
+	Secret *ms.Secret
+
+	Client apiclientv1.APIV1Interface
+	clioptions.IOStreams
+}
+
+var (
+	createLong = templates.LongDesc(`Create secret resource.
+
+SecretID and SecretKey are generated by the server. A secret never expires unless --expires is specified.`)
+
+	createExample = templates.Examples(`
+		# Create secret which will never expire
+		sktctl secret create foo
+
+		# Create secret with a description and expire after 720 hours
+		sktctl secret create foo --expires=720h --description="secret for skt"`)
+
+	createUsageErrStr = fmt.Sprintf("expected '%s'.\nSECRET_NAME is required arguments for the create command", createUsageStr)
+)
+
+func NewCreateOptions(ioStreams clioptions.IOStreams) *CreateOptions {
+	return &CreateOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+func NewCmdCreate(f util.Factory, ioStreams clioptions.IOStreams) *cobra.Command {
+	o := NewCreateOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   createUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Create a secret resource",
+		TraverseChildren:      true,
+		Long:                  createLong,
+		Example:               createExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Complete(f, cmd, args))
+			util.CheckErr(o.Validate(cmd, args))
+			util.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	cmd.Flags().StringVar(&o.Description, "description", o.Description, "The description of the secret.")
+	cmd.Flags().DurationVar(&o.Expires, "expires", o.Expires, "The expire duration of the secret, 0 means never expire.")
+
+	return cmd
+}
+
+func (o *CreateOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return util.UsageErrorf(cmd, createUsageErrStr)
+	}
+
+	o.Secret = &ms.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: args[0],
+		},
+		Description: o.Description,
+	}
+	if o.Expires > 0 {
+		o.Secret.Expires = time.Now().Add(o.Expires).Unix()
+	}
+
+	clientConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.Client, err = apiclientv1.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *CreateOptions) Validate(cmd *cobra.Command, args []string) error {
+	if errs := o.Secret.Validate(); len(errs) != 0 {
+		return errs.ToAggregate()
+	}
+
+	return nil
+}
+
+func (o *CreateOptions) Run(args []string) error {
+	ret, err := o.Client.Secrets().Create(context.TODO(), o.Secret)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(o.Out, "secret/%s created\n", ret.Name)
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package secret
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/changaolee/skeleton/internal/pkg/clioptions"
+	"github.com/changaolee/skeleton/internal/sktctl/util"
+	"github.com/changaolee/skeleton/internal/sktctl/util/templates"
+	apiclientv1 "github.com/changaolee/skeleton/pkg/sdk/apiserver/v1"
+	"github.com/spf13/cobra"
+)
+
+const (
+	deleteUsageStr = "delete SECRET_NAME"
+)
+
+type DeleteOptions struct {
+	Name string
+
+	Client apiclientv1.APIV1Interface
+	clioptions.IOStreams
+}
+
+var (
+	deleteExample = templates.Examples(`
+		# Delete secret foo
+		sktctl secret delete foo`)
+
+	deleteUsageErrStr = fmt.Sprintf("expected '%s'.\nSECRET_NAME is required arguments for the delete command", deleteUsageStr)
+)
+
+func NewDeleteOptions(ioStreams clioptions.IOStreams) *DeleteOptions {
+	return &DeleteOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+func NewCmdDelete(f util.Factory, ioStreams clioptions.IOStreams) *cobra.Command {
+	o := NewDeleteOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   deleteUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Delete a secret resource",
+		TraverseChildren:      true,
+		Long:                  `Delete a secret resource.`,
+		Example:               deleteExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Complete(f, cmd, args))
+			util.CheckErr(o.Validate(cmd, args))
+			util.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	return cmd
+}
+
+func (o *DeleteOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return util.UsageErrorf(cmd, deleteUsageErrStr)
+	}
+
+	o.Name = args[0]
+
+	clientConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.Client, err = apiclientv1.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *DeleteOptions) Validate(cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+func (o *DeleteOptions) Run(args []string) error {
+	if err := o.Client.Secrets().Delete(context.TODO(), o.Name); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(o.Out, "secret/%s deleted\n", o.Name)
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package secret
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/changaolee/skeleton/internal/pkg/clioptions"
+	"github.com/changaolee/skeleton/internal/sktctl/util"
+	"github.com/changaolee/skeleton/internal/sktctl/util/templates"
+	apiclientv1 "github.com/changaolee/skeleton/pkg/sdk/apiserver/v1"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+const (
+	getUsageStr = "get SECRET_NAME"
+)
+
+type GetOptions struct {
+	Name string
+
+	Client apiclientv1.APIV1Interface
+	clioptions.IOStreams
+}
+
+var (
+	getExample = templates.Examples(`
+		# Display a secret resource
+		sktctl secret get foo`)
+
+	getUsageErrStr = fmt.Sprintf("expected '%s'.\nSECRET_NAME is required arguments for the get command", getUsageStr)
+)
+
+func NewGetOptions(ioStreams clioptions.IOStreams) *GetOptions {
+	return &GetOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+func NewCmdGet(f util.Factory, ioStreams clioptions.IOStreams) *cobra.Command {
+	o := NewGetOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   getUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Display a secret resource.",
+		TraverseChildren:      true,
+		Long:                  `Display a secret resource.`,
+		Example:               getExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Complete(f, cmd, args))
+			util.CheckErr(o.Validate(cmd, args))
+			util.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	return cmd
+}
+
+func (o *GetOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return util.UsageErrorf(cmd, getUsageErrStr)
+	}
+
+	o.Name = args[0]
+
+	clientConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.Client, err = apiclientv1.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *GetOptions) Validate(cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+func (o *GetOptions) Run(args []string) error {
+	secret, err := o.Client.Secrets().Get(context.TODO(), o.Name)
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(o.Out)
+	table = setHeader(table)
+	table = util.TableWriterDefaultConfig(table)
+	table.Append(toRow(secret))
+	table.Render()
+
+	return nil
+}
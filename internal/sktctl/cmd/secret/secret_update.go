@@ -0,0 +1,119 @@
+package secret
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/changaolee/skeleton/internal/pkg/clioptions"
+	"github.com/changaolee/skeleton/internal/sktctl/util"
+	"github.com/changaolee/skeleton/internal/sktctl/util/templates"
+	apiclientv1 "github.com/changaolee/skeleton/pkg/sdk/apiserver/v1"
+	"github.com/spf13/cobra"
+)
+
+const (
+	updateUsageStr = "update SECRET_NAME"
+)
+
+type UpdateOptions struct {
+	Name        string
+	Description string
+	Expires     time.Duration
+
+	Client apiclientv1.APIV1Interface
+	clioptions.IOStreams
+}
+
+var (
+	updateLong = templates.LongDesc(`Update a secret resource.
+
+Can only update description and expires. Use --expires=0 to make the secret never expire.`)
+
+	updateExample = templates.Examples(`
+		# Update a secret resource
+		sktctl secret update foo --expires=4h --description="new description"`)
+
+	updateUsageErrStr = fmt.Sprintf("expected '%s'.\nSECRET_NAME is required arguments for the update command", updateUsageStr)
+)
+
+func NewUpdateOptions(ioStreams clioptions.IOStreams) *UpdateOptions {
+	return &UpdateOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+func NewCmdUpdate(f util.Factory, ioStreams clioptions.IOStreams) *cobra.Command {
+	o := NewUpdateOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   updateUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Update a secret resource",
+		TraverseChildren:      true,
+		Long:                  updateLong,
+		Example:               updateExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Complete(f, cmd, args))
+			util.CheckErr(o.Validate(cmd, args))
+			util.CheckErr(o.Run(cmd, args))
+		},
+		SuggestFor: []string{},
+	}
+
+	cmd.Flags().StringVar(&o.Description, "description", o.Description, "The description of the secret.")
+	cmd.Flags().DurationVar(&o.Expires, "expires", o.Expires, "The expire duration of the secret, 0 means never expire.")
+
+	return cmd
+}
+
+func (o *UpdateOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return util.UsageErrorf(cmd, updateUsageErrStr)
+	}
+
+	o.Name = args[0]
+
+	clientConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.Client, err = apiclientv1.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *UpdateOptions) Validate(cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+func (o *UpdateOptions) Run(cmd *cobra.Command, args []string) error {
+	secret, err := o.Client.Secrets().Get(context.TODO(), o.Name)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Flags().Changed("description") {
+		secret.Description = o.Description
+	}
+	if cmd.Flags().Changed("expires") {
+		secret.Expires = 0
+		if o.Expires > 0 {
+			secret.Expires = time.Now().Add(o.Expires).Unix()
+		}
+	}
+
+	ret, err := o.Client.Secrets().Update(context.TODO(), secret)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(o.Out, "secret/%s updated\n", ret.Name)
+
+	return nil
+}
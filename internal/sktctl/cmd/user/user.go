@@ -11,7 +11,7 @@ import (
 var userLong = templates.LongDesc(`
 	User management commands.
 
-Administrator can use all subcommands, non-administrator only allow to use create/get/update. When call get/update non-administrator only allow to operate their own resources, if permission not allowed, will return an 'Permission denied' error.`)
+Administrator can use all subcommands, non-administrator only allow to use create/get/update/change-password. When call get/update/change-password non-administrator only allow to operate their own resources, if permission not allowed, will return an 'Permission denied' error.`)
 
 func NewCmdUser(f util.Factory, ioStreams clioptions.IOStreams) *cobra.Command {
 	cmd := &cobra.Command{
@@ -24,9 +24,10 @@ func NewCmdUser(f util.Factory, ioStreams clioptions.IOStreams) *cobra.Command {
 
 	cmd.AddCommand(NewCmdCreate(f, ioStreams))
 	cmd.AddCommand(NewCmdGet(f, ioStreams))
-	//cmd.AddCommand(NewCmdList(f, ioStreams))
-	//cmd.AddCommand(NewCmdDelete(f, ioStreams))
-	//cmd.AddCommand(NewCmdUpdate(f, ioStreams))
+	cmd.AddCommand(NewCmdList(f, ioStreams))
+	cmd.AddCommand(NewCmdDelete(f, ioStreams))
+	cmd.AddCommand(NewCmdUpdate(f, ioStreams))
+	cmd.AddCommand(NewCmdChangePassword(f, ioStreams))
 
 	return cmd
 }
@@ -0,0 +1,124 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/changaolee/skeleton/internal/pkg/clioptions"
+	"github.com/changaolee/skeleton/internal/pkg/model/user"
+	"github.com/changaolee/skeleton/internal/sktctl/util"
+	"github.com/changaolee/skeleton/internal/sktctl/util/templates"
+	apiclientv1 "github.com/changaolee/skeleton/pkg/sdk/apiserver/v1"
+	"github.com/spf13/cobra"
+)
+
+const (
+	changePasswordUsageStr = "change-password USERNAME"
+)
+
+type ChangePasswordOptions struct {
+	Name    string
+	Request *user.ChangePasswordRequest
+
+	Client apiclientv1.APIV1Interface
+	clioptions.IOStreams
+}
+
+var (
+	changePasswordLong = templates.LongDesc(`Change the password of a user.
+
+The old and new passwords are read interactively and are not echoed to the terminal.`)
+
+	changePasswordExample = templates.Examples(`
+		# Change the password of user foo
+		sktctl user change-password foo`)
+
+	changePasswordUsageErrStr = fmt.Sprintf(
+		"expected '%s'.\nUSERNAME is required arguments for the change-password command",
+		changePasswordUsageStr,
+	)
+)
+
+func NewChangePasswordOptions(ioStreams clioptions.IOStreams) *ChangePasswordOptions {
+	return &ChangePasswordOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+func NewCmdChangePassword(f util.Factory, ioStreams clioptions.IOStreams) *cobra.Command {
+	o := NewChangePasswordOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   changePasswordUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Change the password of a user",
+		TraverseChildren:      true,
+		Long:                  changePasswordLong,
+		Example:               changePasswordExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Complete(f, cmd, args))
+			util.CheckErr(o.Validate(cmd, args))
+			util.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	return cmd
+}
+
+func (o *ChangePasswordOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return util.UsageErrorf(cmd, changePasswordUsageErrStr)
+	}
+
+	o.Name = args[0]
+
+	oldPassword, err := util.PromptPassword(o.In, o.Out, "Old password: ")
+	if err != nil {
+		return err
+	}
+	newPassword, err := util.PromptPassword(o.In, o.Out, "New password: ")
+	if err != nil {
+		return err
+	}
+	confirmPassword, err := util.PromptPassword(o.In, o.Out, "Confirm new password: ")
+	if err != nil {
+		return err
+	}
+	if newPassword != confirmPassword {
+		return fmt.Errorf("new passwords do not match")
+	}
+
+	o.Request = &user.ChangePasswordRequest{
+		OldPassword: oldPassword,
+		NewPassword: newPassword,
+	}
+
+	clientConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.Client, err = apiclientv1.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate 不在本地校验新密码，密码策略可以在 apiserver 中配置，由 apiserver 校验.
+func (o *ChangePasswordOptions) Validate(cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+func (o *ChangePasswordOptions) Run(args []string) error {
+	if err := o.Client.Users().ChangePassword(context.TODO(), o.Name, o.Request); err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(o.Out, "user/%s password changed\n", o.Name)
+
+	return nil
+}
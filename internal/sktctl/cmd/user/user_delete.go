@@ -0,0 +1,124 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/changaolee/skeleton/internal/pkg/clioptions"
+	"github.com/changaolee/skeleton/internal/sktctl/util"
+	"github.com/changaolee/skeleton/internal/sktctl/util/templates"
+	apiclientv1 "github.com/changaolee/skeleton/pkg/sdk/apiserver/v1"
+	"github.com/spf13/cobra"
+)
+
+const (
+	deleteUsageStr = "delete USERNAME [USERNAME...]"
+)
+
+type DeleteOptions struct {
+	Names []string
+	Yes   bool
+
+	Client apiclientv1.APIV1Interface
+	clioptions.IOStreams
+}
+
+var (
+	deleteLong = templates.LongDesc(`Delete one or more user resources.
+
+The secrets and policies owned by the users are deleted as well. A confirmation is required unless --yes is specified.`)
+
+	deleteExample = templates.Examples(`
+		# Delete user foo from platform
+		sktctl user delete foo
+
+		# Delete users foo and bar without confirmation
+		sktctl user delete foo bar --yes`)
+
+	deleteUsageErrStr = fmt.Sprintf("expected '%s'.\nUSERNAME is required arguments for the delete command", deleteUsageStr)
+)
+
+func NewDeleteOptions(ioStreams clioptions.IOStreams) *DeleteOptions {
+	return &DeleteOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+func NewCmdDelete(f util.Factory, ioStreams clioptions.IOStreams) *cobra.Command {
+	o := NewDeleteOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   deleteUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Delete user resources",
+		TraverseChildren:      true,
+		Long:                  deleteLong,
+		Example:               deleteExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Complete(f, cmd, args))
+			util.CheckErr(o.Validate(cmd, args))
+			util.CheckErr(o.Run(args))
+		},
+		SuggestFor: []string{},
+	}
+
+	cmd.Flags().BoolVarP(&o.Yes, "yes", "y", o.Yes, "Skip the confirmation prompt.")
+
+	return cmd
+}
+
+func (o *DeleteOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return util.UsageErrorf(cmd, deleteUsageErrStr)
+	}
+
+	o.Names = args
+
+	clientConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.Client, err = apiclientv1.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *DeleteOptions) Validate(cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+func (o *DeleteOptions) Run(args []string) error {
+	if !o.Yes {
+		prompt := fmt.Sprintf("Are you sure to delete user(s) %s", strings.Join(o.Names, ", "))
+		confirmed, err := util.PromptConfirm(o.In, o.Out, prompt)
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			_, _ = fmt.Fprintln(o.Out, "delete canceled")
+			return nil
+		}
+	}
+
+	var err error
+	if len(o.Names) == 1 {
+		err = o.Client.Users().Delete(context.TODO(), o.Names[0])
+	} else {
+		err = o.Client.Users().DeleteCollection(context.TODO(), o.Names)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, name := range o.Names {
+		_, _ = fmt.Fprintf(o.Out, "user/%s deleted\n", name)
+	}
+
+	return nil
+}
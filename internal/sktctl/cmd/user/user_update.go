@@ -0,0 +1,120 @@
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/changaolee/skeleton/internal/pkg/clioptions"
+	"github.com/changaolee/skeleton/internal/sktctl/util"
+	"github.com/changaolee/skeleton/internal/sktctl/util/templates"
+	apiclientv1 "github.com/changaolee/skeleton/pkg/sdk/apiserver/v1"
+	"github.com/spf13/cobra"
+)
+
+const (
+	updateUsageStr = "update USERNAME"
+)
+
+type UpdateOptions struct {
+	Name     string
+	Nickname string
+	Email    string
+	Phone    string
+
+	Client apiclientv1.APIV1Interface
+	clioptions.IOStreams
+}
+
+var (
+	updateLong = templates.LongDesc(`Update a user resource.
+
+Can only update nickname, email and phone. Fields not specified by flags keep their current values.`)
+
+	updateExample = templates.Examples(`
+		# Update user foo's information
+		sktctl user update foo --nickname=foo2 --email=foo@qq.com --phone=1812883xxxx`)
+
+	updateUsageErrStr = fmt.Sprintf("expected '%s'.\nUSERNAME is required arguments for the update command", updateUsageStr)
+)
+
+func NewUpdateOptions(ioStreams clioptions.IOStreams) *UpdateOptions {
+	return &UpdateOptions{
+		IOStreams: ioStreams,
+	}
+}
+
+func NewCmdUpdate(f util.Factory, ioStreams clioptions.IOStreams) *cobra.Command {
+	o := NewUpdateOptions(ioStreams)
+
+	cmd := &cobra.Command{
+		Use:                   updateUsageStr,
+		DisableFlagsInUseLine: true,
+		Aliases:               []string{},
+		Short:                 "Update a user resource",
+		TraverseChildren:      true,
+		Long:                  updateLong,
+		Example:               updateExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			util.CheckErr(o.Complete(f, cmd, args))
+			util.CheckErr(o.Validate(cmd, args))
+			util.CheckErr(o.Run(cmd, args))
+		},
+		SuggestFor: []string{},
+	}
+
+	cmd.Flags().StringVar(&o.Nickname, "nickname", o.Nickname, "The nickname of the user.")
+	cmd.Flags().StringVar(&o.Email, "email", o.Email, "The email of the user.")
+	cmd.Flags().StringVar(&o.Phone, "phone", o.Phone, "The phone number of the user.")
+
+	return cmd
+}
+
+func (o *UpdateOptions) Complete(f util.Factory, cmd *cobra.Command, args []string) error {
+	var err error
+	if len(args) == 0 {
+		return util.UsageErrorf(cmd, updateUsageErrStr)
+	}
+
+	o.Name = args[0]
+
+	clientConfig, err := f.ToRESTConfig()
+	if err != nil {
+		return err
+	}
+	o.Client, err = apiclientv1.NewForConfig(clientConfig)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (o *UpdateOptions) Validate(cmd *cobra.Command, args []string) error {
+	return nil
+}
+
+func (o *UpdateOptions) Run(cmd *cobra.Command, args []string) error {
+	user, err := o.Client.Users().Get(context.TODO(), o.Name)
+	if err != nil {
+		return err
+	}
+
+	if cmd.Flags().Changed("nickname") {
+		user.Nickname = o.Nickname
+	}
+	if cmd.Flags().Changed("email") {
+		user.Email = o.Email
+	}
+	if cmd.Flags().Changed("phone") {
+		user.Phone = o.Phone
+	}
+
+	ret, err := o.Client.Users().Update(context.TODO(), user)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(o.Out, "user/%s updated\n", ret.Name)
+
+	return nil
+}
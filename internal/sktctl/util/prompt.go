@@ -0,0 +1,78 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package util
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// PromptConfirm 打印提示信息并等待用户确认，仅当输入 y 或 yes 时返回 true.
+func PromptConfirm(in io.Reader, out io.Writer, prompt string) (bool, error) {
+	_, _ = fmt.Fprintf(out, "%s [y/N]: ", prompt)
+
+	answer, err := readLine(in)
+	if err != nil {
+		return false, err
+	}
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// PromptPassword 打印提示信息并读取密码，输入来自终端时不回显.
+func PromptPassword(in io.Reader, out io.Writer, prompt string) (string, error) {
+	_, _ = fmt.Fprint(out, prompt)
+
+	if f, ok := in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		data, err := term.ReadPassword(int(f.Fd()))
+		_, _ = fmt.Fprintln(out)
+		if err != nil {
+			return "", err
+		}
+
+		return string(data), nil
+	}
+
+	return readLine(in)
+}
+
+// readLine 逐字节读取一行输入，避免缓冲读取吞掉后续输入.
+func readLine(in io.Reader) (string, error) {
+	var (
+		line []byte
+		buf  = make([]byte, 1)
+	)
+
+	for {
+		n, err := in.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				break
+			}
+			line = append(line, buf[0])
+		}
+		if err == io.EOF {
+			if len(line) == 0 {
+				return "", err
+			}
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return strings.TrimRight(string(line), "\r"), nil
+}
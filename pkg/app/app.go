@@ -207,13 +207,26 @@ func (a *App) runCommand(cmd *cobra.Command, args []string) error {
 }
 
 func (a *App) applyOptionRules() error {
-	if completableOptions, ok := a.options.(CompletableOptions); ok {
+	if err := completeAndValidate(a.options); err != nil {
+		return err
+	}
+
+	if printableOptions, ok := a.options.(PrintableOptions); ok && !a.silence {
+		log.Infof("%v Config: `%s`", progressMessage, printableOptions.String())
+	}
+
+	return nil
+}
+
+// completeAndValidate 补全并校验选项.
+func completeAndValidate(opts CliOptions) error {
+	if completableOptions, ok := opts.(CompletableOptions); ok {
 		if err := completableOptions.Complete(); err != nil {
 			return err
 		}
 	}
 
-	if errs := a.options.Validate(); len(errs) > 0 {
+	if errs := opts.Validate(); len(errs) > 0 {
 		var aggerr error
 		for _, err := range errs {
 			aggerr = multierr.Append(aggerr, err)
@@ -221,10 +234,6 @@ func (a *App) applyOptionRules() error {
 		return aggerr
 	}
 
-	if printableOptions, ok := a.options.(PrintableOptions); ok && !a.silence {
-		log.Infof("%v Config: `%s`", progressMessage, printableOptions.String())
-	}
-
 	return nil
 }
 
@@ -55,3 +55,16 @@ func addConfigFlag(basename string, fs *pflag.FlagSet) {
 		}
 	})
 }
+
+// ReloadOptions 重新读取配置文件并解析到 opts 中，然后补全并校验 opts，用于配置热加载.
+// opts 应该是一个新创建的选项实例，命令行参数和环境变量的优先级与启动时相同.
+func ReloadOptions(opts CliOptions) error {
+	if err := viper.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read configuration file(%s): %w", viper.ConfigFileUsed(), err)
+	}
+	if err := viper.Unmarshal(opts); err != nil {
+		return err
+	}
+
+	return completeAndValidate(opts)
+}
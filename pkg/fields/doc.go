@@ -0,0 +1,7 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+// Package fields 实现了 list 接口中 fieldSelector 查询参数的解析.
+package fields // import "github.com/changaolee/skeleton/pkg/fields"
@@ -0,0 +1,100 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package fields
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Operator 定义了字段选择器支持的比较操作.
+type Operator string
+
+const (
+	// Equals 表示字段值等于指定值.
+	Equals Operator = "="
+	// DoubleEquals 与 Equals 含义相同.
+	DoubleEquals Operator = "=="
+	// NotEquals 表示字段值不等于指定值.
+	NotEquals Operator = "!="
+)
+
+// Requirement 表示一个字段选择条件，例如 `name=foo`.
+type Requirement struct {
+	Field    string
+	Operator Operator
+	Value    string
+}
+
+// Selector 是多个字段选择条件的集合，各条件之间为“与”的关系.
+type Selector []Requirement
+
+// ParseSelector 解析形如 `name=foo,status!=0` 的字段选择器字符串.
+func ParseSelector(selector string) (Selector, error) {
+	var s Selector
+
+	for _, part := range strings.Split(selector, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		r, err := parseRequirement(part)
+		if err != nil {
+			return nil, err
+		}
+		s = append(s, r)
+	}
+
+	return s, nil
+}
+
+func parseRequirement(part string) (Requirement, error) {
+	// 先匹配较长的操作符，避免 `!=` 和 `==` 被误识别为 `=`
+	for _, op := range []Operator{NotEquals, DoubleEquals, Equals} {
+		if idx := strings.Index(part, string(op)); idx >= 0 {
+			field := strings.TrimSpace(part[:idx])
+			if field == "" {
+				return Requirement{}, fmt.Errorf("invalid field selector: '%s'", part)
+			}
+
+			value := strings.TrimSpace(part[idx+len(op):])
+			if op == DoubleEquals {
+				op = Equals
+			}
+
+			return Requirement{Field: field, Operator: op, Value: value}, nil
+		}
+	}
+
+	return Requirement{}, fmt.Errorf("invalid field selector: '%s'", part)
+}
+
+// Empty 判断选择器是否不包含任何条件.
+func (s Selector) Empty() bool {
+	return len(s) == 0
+}
+
+// RequiresExactMatch 返回选择器中要求指定字段精确匹配的值.
+func (s Selector) RequiresExactMatch(field string) (string, bool) {
+	for _, r := range s {
+		if r.Field == field && r.Operator == Equals {
+			return r.Value, true
+		}
+	}
+
+	return "", false
+}
+
+// String 返回选择器的字符串表示.
+func (s Selector) String() string {
+	parts := make([]string, 0, len(s))
+	for _, r := range s {
+		parts = append(parts, r.Field+string(r.Operator)+r.Value)
+	}
+
+	return strings.Join(parts, ",")
+}
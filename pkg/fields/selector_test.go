@@ -0,0 +1,54 @@
+package fields
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSelector(t *testing.T) {
+	testCases := []struct {
+		in      string
+		want    Selector
+		wantErr bool
+	}{
+		{in: "", want: nil},
+		{in: "name=foo", want: Selector{{Field: "name", Operator: Equals, Value: "foo"}}},
+		{in: "name==foo", want: Selector{{Field: "name", Operator: Equals, Value: "foo"}}},
+		{
+			in: "name=foo, status!=0",
+			want: Selector{
+				{Field: "name", Operator: Equals, Value: "foo"},
+				{Field: "status", Operator: NotEquals, Value: "0"},
+			},
+		},
+		{in: "name", wantErr: true},
+		{in: "=foo", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		got, err := ParseSelector(tc.in)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("ParseSelector(%q): expected error", tc.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseSelector(%q): unexpected error: %v", tc.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ParseSelector(%q) = %#v, want %#v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestRequiresExactMatch(t *testing.T) {
+	s, _ := ParseSelector("name=foo,email!=bar")
+	if v, ok := s.RequiresExactMatch("name"); !ok || v != "foo" {
+		t.Errorf("Expected name=foo, got %q, %v", v, ok)
+	}
+	if _, ok := s.RequiresExactMatch("email"); ok {
+		t.Errorf("Unexpected exact match for email")
+	}
+}
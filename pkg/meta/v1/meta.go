@@ -19,3 +19,20 @@ type ObjectMeta struct {
 	CreatedAt    time.Time `json:"createdAt,omitempty"  gorm:"column:createdAt"`
 	UpdatedAt    time.Time `json:"updatedAt,omitempty"  gorm:"column:updatedAt"`
 }
+
+// ListMeta 描述了列表类资源必须包含的元数据.
+type ListMeta struct {
+	TotalCount int64 `json:"totalCount,omitempty"`
+}
+
+// ListOptions 是调用标准 REST list 接口时的查询参数.
+type ListOptions struct {
+	// FieldSelector 通过字段过滤返回结果，例如 `name=foo,status!=0`.
+	FieldSelector string `json:"fieldSelector,omitempty" form:"fieldSelector"`
+
+	// Offset 指定返回结果的起始位置.
+	Offset *int64 `json:"offset,omitempty" form:"offset"`
+
+	// Limit 指定返回结果的最大数量，-1 表示不限制.
+	Limit *int64 `json:"limit,omitempty"  form:"limit"`
+}
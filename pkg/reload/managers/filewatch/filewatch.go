@@ -0,0 +1,99 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package filewatch
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/changaolee/skeleton/pkg/reload"
+)
+
+const Name = "FileWatchReloadManager"
+
+// defaultDebounce 为合并连续文件事件的等待时间，编辑器保存文件时通常会产生多个事件.
+const defaultDebounce = 200 * time.Millisecond
+
+type Manager struct {
+	file     string
+	debounce time.Duration
+	watcher  *fsnotify.Watcher
+}
+
+// NewFileWatchManager 初始化配置文件变更 reload 监听实例.
+func NewFileWatchManager(file string) *Manager {
+	return &Manager{
+		file:     filepath.Clean(file),
+		debounce: defaultDebounce,
+	}
+}
+
+func (m *Manager) GetName() string {
+	return Name
+}
+
+// Start 监听配置文件所在的目录而不是文件本身，这样通过重命名替换文件（如 vim 保存、Kubernetes ConfigMap
+// 更新符号链接）时也能收到事件.
+func (m *Manager) Start(rl reload.RLInterface) error {
+	if m.file == "" || m.file == "." {
+		return fmt.Errorf("no config file to watch")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	if err := watcher.Add(filepath.Dir(m.file)); err != nil {
+		_ = watcher.Close()
+		return err
+	}
+	m.watcher = watcher
+
+	realFile, _ := filepath.EvalSymlinks(m.file)
+
+	go func() {
+		var timer <-chan time.Time
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				currentFile, _ := filepath.EvalSymlinks(m.file)
+				written := filepath.Clean(event.Name) == m.file &&
+					event.Op&(fsnotify.Write|fsnotify.Create) != 0
+				relinked := currentFile != "" && currentFile != realFile
+				if !written && !relinked {
+					continue
+				}
+				realFile = currentFile
+				timer = time.After(m.debounce)
+			case <-timer:
+				timer = nil
+				rl.StartReload(m)
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				rl.ReportError(fmt.Errorf("watch config file %s: %w", m.file, err))
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop 停止监听配置文件.
+func (m *Manager) Stop() error {
+	if m.watcher == nil {
+		return nil
+	}
+	return m.watcher.Close()
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package filewatch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/changaolee/skeleton/pkg/reload"
+)
+
+func TestManager(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "skt-authz-server.yaml")
+	if err := os.WriteFile(file, []byte("log:\n  level: info\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := make(chan string, 10)
+	gr := reload.New()
+	gr.AddCallback(reload.CallbackFunc(func(manager string) error {
+		reloaded <- manager
+		return nil
+	}))
+
+	m := NewFileWatchManager(file)
+	m.debounce = 20 * time.Millisecond
+	gr.AddManager(m)
+	if err := gr.Start(); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer m.Stop()
+
+	// 其他文件的变更不触发 reload
+	if err := os.WriteFile(filepath.Join(dir, "other.yaml"), []byte("x"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// 多次写入只触发一次 reload
+	for _, level := range []string{"debug", "warn"} {
+		if err := os.WriteFile(file, []byte("log:\n  level: "+level+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	select {
+	case manager := <-reloaded:
+		if manager != Name {
+			t.Errorf("manager = %s, want %s", manager, Name)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("config file change did not trigger a reload")
+	}
+
+	select {
+	case <-reloaded:
+		t.Error("consecutive writes triggered more than one reload")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package posixsignal
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/changaolee/skeleton/pkg/reload"
+)
+
+const Name = "PosixSignalReloadManager"
+
+type Manager struct {
+	signals []os.Signal
+}
+
+// NewPosixSignalManager 初始化 POSIX 信号 reload 监听实例，默认监听 SIGHUP.
+func NewPosixSignalManager(signals ...os.Signal) *Manager {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+	return &Manager{
+		signals: signals,
+	}
+}
+
+func (m *Manager) GetName() string {
+	return Name
+}
+
+func (m *Manager) Start(rl reload.RLInterface) error {
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, m.signals...)
+
+		// 与 shutdown 不同，每次收到信号都触发一次 reload
+		for range c {
+			rl.StartReload(m)
+		}
+	}()
+
+	return nil
+}
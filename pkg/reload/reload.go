@@ -0,0 +1,105 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package reload
+
+import "sync"
+
+type Callback interface {
+	OnReload(manager string) error
+}
+
+// CallbackFunc 是一个辅助函数，用于自定义回调函数.
+type CallbackFunc func(string) error
+
+// OnReload 是在 reload 触发时执行的方法.
+func (f CallbackFunc) OnReload(manager string) error {
+	return f(manager)
+}
+
+type RLInterface interface {
+	AddManager(manager Manager)                // 添加 Reload Manager
+	AddCallback(callback Callback)             // 添加 Reload Callback
+	SetErrorHandler(errorHandler ErrorHandler) // 设置 ErrorHandler
+
+	StartReload(manager Manager) // 执行指定 Manager 触发的 reload
+	ReportError(err error)       // 向 ErrorHandler 报错
+}
+
+type Manager interface {
+	GetName() string
+	Start(rl RLInterface) error
+}
+
+type ErrorHandler interface {
+	OnError(err error)
+}
+
+// ErrorFunc 是一个辅助函数，用于自定义 ErrorHandler.
+type ErrorFunc func(err error)
+
+// OnError 在 Manager 或 Callback 失败时调用.
+func (f ErrorFunc) OnError(err error) {
+	f(err)
+}
+
+type GracefulReload struct {
+	callbacks    []Callback
+	managers     []Manager
+	errorHandler ErrorHandler
+
+	// mu 保证同一时刻只有一次 reload 在执行，信号和文件变更同时触发时依次执行
+	mu sync.Mutex
+}
+
+// New 初始化配置热加载实例.
+func New() *GracefulReload {
+	return &GracefulReload{
+		callbacks: make([]Callback, 0, 10),
+		managers:  make([]Manager, 0, 3),
+	}
+}
+
+// Start 在所有已添加的 Manager 上启动监听.
+func (gr *GracefulReload) Start() error {
+	for _, manager := range gr.managers {
+		if err := manager.Start(gr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddManager 添加 Manager 用于监听 reload 请求.
+func (gr *GracefulReload) AddManager(manager Manager) {
+	gr.managers = append(gr.managers, manager)
+}
+
+// AddCallback 添加 Callback 以便在 reload 时按添加顺序调用.
+func (gr *GracefulReload) AddCallback(callback Callback) {
+	gr.callbacks = append(gr.callbacks, callback)
+}
+
+// SetErrorHandler 设置 ErrorHandler 用于在 Manager 或 Callback 失败时调用.
+func (gr *GracefulReload) SetErrorHandler(errorHandler ErrorHandler) {
+	gr.errorHandler = errorHandler
+}
+
+// StartReload 用于执行指定 Manager 触发的 reload，回调按添加顺序依次执行，某个回调失败不影响后续回调.
+func (gr *GracefulReload) StartReload(manager Manager) {
+	gr.mu.Lock()
+	defer gr.mu.Unlock()
+
+	for _, callback := range gr.callbacks {
+		gr.ReportError(callback.OnReload(manager.GetName()))
+	}
+}
+
+// ReportError 用于向 ErrorHandler 报错.
+func (gr *GracefulReload) ReportError(err error) {
+	if err != nil && gr.errorHandler != nil {
+		gr.errorHandler.OnError(err)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package reload
+
+import (
+	"errors"
+	"testing"
+)
+
+type testManager struct{}
+
+func (testManager) GetName() string            { return "test" }
+func (testManager) Start(rl RLInterface) error { return nil }
+
+func TestStartReload(t *testing.T) {
+	var events []string
+	var errs []error
+
+	gr := New()
+	gr.SetErrorHandler(ErrorFunc(func(err error) { errs = append(errs, err) }))
+	gr.AddCallback(CallbackFunc(func(manager string) error {
+		events = append(events, "validate "+manager)
+		return errors.New("log.output-paths requires a restart")
+	}))
+	gr.AddCallback(CallbackFunc(func(manager string) error {
+		events = append(events, "apply "+manager)
+		return nil
+	}))
+
+	gr.StartReload(testManager{})
+
+	if len(events) != 2 || events[0] != "validate test" || events[1] != "apply test" {
+		t.Errorf("events = %v, want callbacks run in order", events)
+	}
+	if len(errs) != 1 {
+		t.Errorf("errors = %v, want 1", errs)
+	}
+}
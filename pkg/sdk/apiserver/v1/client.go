@@ -1,13 +1,18 @@
 package v1
 
 import (
+	"strconv"
+
 	"github.com/changaolee/skeleton/internal/pkg/rest"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
 	"github.com/changaolee/skeleton/pkg/runtime"
 )
 
 type APIV1Interface interface {
 	RESTClient() rest.Interface
 	UsersGetter
+	SecretsGetter
+	PoliciesGetter
 }
 
 type APIV1Client struct {
@@ -18,6 +23,14 @@ func (c *APIV1Client) Users() UserInterface {
 	return newUsers(c)
 }
 
+func (c *APIV1Client) Secrets() SecretInterface {
+	return newSecrets(c)
+}
+
+func (c *APIV1Client) Policies() PolicyInterface {
+	return newPolicies(c)
+}
+
 func NewForConfig(c *rest.Config) (*APIV1Client, error) {
 	config := *c
 	setConfigDefaults(&config)
@@ -61,3 +74,18 @@ func (c *APIV1Client) RESTClient() rest.Interface {
 
 	return c.restClient
 }
+
+// setListOptions 将 list 接口的查询参数设置到请求中.
+func setListOptions(req *rest.Request, opts metav1.ListOptions) *rest.Request {
+	if opts.FieldSelector != "" {
+		req = req.Param("fieldSelector", opts.FieldSelector)
+	}
+	if opts.Offset != nil {
+		req = req.Param("offset", strconv.FormatInt(*opts.Offset, 10))
+	}
+	if opts.Limit != nil {
+		req = req.Param("limit", strconv.FormatInt(*opts.Limit, 10))
+	}
+
+	return req
+}
@@ -0,0 +1,81 @@
+package v1
+
+import (
+	"context"
+
+	mp "github.com/changaolee/skeleton/internal/pkg/model/policy"
+	"github.com/changaolee/skeleton/internal/pkg/rest"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+)
+
+type PoliciesGetter interface {
+	Policies() PolicyInterface
+}
+
+type PolicyInterface interface {
+	Create(ctx context.Context, policy *mp.Policy) (*mp.Policy, error)
+	Update(ctx context.Context, policy *mp.Policy) (*mp.Policy, error)
+	Delete(ctx context.Context, name string) error
+	Get(ctx context.Context, name string) (*mp.Policy, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*mp.PolicyList, error)
+}
+
+type policies struct {
+	client rest.Interface
+}
+
+var _ PolicyInterface = (*policies)(nil)
+
+func newPolicies(c *APIV1Client) *policies {
+	return &policies{
+		client: c.RESTClient(),
+	}
+}
+
+func (p *policies) Create(ctx context.Context, policy *mp.Policy) (result *mp.Policy, err error) {
+	result = &mp.Policy{}
+	err = p.client.Post().
+		AbsPath("/v1/policies").
+		Body(policy).
+		Do(ctx).
+		Into(result)
+
+	return
+}
+
+func (p *policies) Update(ctx context.Context, policy *mp.Policy) (result *mp.Policy, err error) {
+	result = &mp.Policy{}
+	err = p.client.Put().
+		AbsPath("/v1/policies/" + policy.Name).
+		Body(policy).
+		Do(ctx).
+		Into(result)
+
+	return
+}
+
+func (p *policies) Delete(ctx context.Context, name string) error {
+	return p.client.Delete().
+		AbsPath("/v1/policies/" + name).
+		Do(ctx).
+		Error()
+}
+
+func (p *policies) Get(ctx context.Context, name string) (result *mp.Policy, err error) {
+	result = &mp.Policy{}
+	err = p.client.Get().
+		AbsPath("/v1/policies/" + name).
+		Do(ctx).
+		Into(result)
+
+	return
+}
+
+func (p *policies) List(ctx context.Context, opts metav1.ListOptions) (result *mp.PolicyList, err error) {
+	result = &mp.PolicyList{}
+	err = setListOptions(p.client.Get().AbsPath("/v1/policies"), opts).
+		Do(ctx).
+		Into(result)
+
+	return
+}
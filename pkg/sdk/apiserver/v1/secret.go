@@ -0,0 +1,81 @@
+package v1
+
+import (
+	"context"
+
+	ms "github.com/changaolee/skeleton/internal/pkg/model/secret"
+	"github.com/changaolee/skeleton/internal/pkg/rest"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
+)
+
+type SecretsGetter interface {
+	Secrets() SecretInterface
+}
+
+type SecretInterface interface {
+	Create(ctx context.Context, secret *ms.Secret) (*ms.Secret, error)
+	Update(ctx context.Context, secret *ms.Secret) (*ms.Secret, error)
+	Delete(ctx context.Context, name string) error
+	Get(ctx context.Context, name string) (*ms.Secret, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*ms.SecretList, error)
+}
+
+type secrets struct {
+	client rest.Interface
+}
+
+var _ SecretInterface = (*secrets)(nil)
+
+func newSecrets(c *APIV1Client) *secrets {
+	return &secrets{
+		client: c.RESTClient(),
+	}
+}
+
+func (s *secrets) Create(ctx context.Context, secret *ms.Secret) (result *ms.Secret, err error) {
+	result = &ms.Secret{}
+	err = s.client.Post().
+		AbsPath("/v1/secrets").
+		Body(secret).
+		Do(ctx).
+		Into(result)
+
+	return
+}
+
+func (s *secrets) Update(ctx context.Context, secret *ms.Secret) (result *ms.Secret, err error) {
+	result = &ms.Secret{}
+	err = s.client.Put().
+		AbsPath("/v1/secrets/" + secret.Name).
+		Body(secret).
+		Do(ctx).
+		Into(result)
+
+	return
+}
+
+func (s *secrets) Delete(ctx context.Context, name string) error {
+	return s.client.Delete().
+		AbsPath("/v1/secrets/" + name).
+		Do(ctx).
+		Error()
+}
+
+func (s *secrets) Get(ctx context.Context, name string) (result *ms.Secret, err error) {
+	result = &ms.Secret{}
+	err = s.client.Get().
+		AbsPath("/v1/secrets/" + name).
+		Do(ctx).
+		Into(result)
+
+	return
+}
+
+func (s *secrets) List(ctx context.Context, opts metav1.ListOptions) (result *ms.SecretList, err error) {
+	result = &ms.SecretList{}
+	err = setListOptions(s.client.Get().AbsPath("/v1/secrets"), opts).
+		Do(ctx).
+		Into(result)
+
+	return
+}
@@ -5,6 +5,7 @@ import (
 
 	mu "github.com/changaolee/skeleton/internal/pkg/model/user"
 	"github.com/changaolee/skeleton/internal/pkg/rest"
+	metav1 "github.com/changaolee/skeleton/pkg/meta/v1"
 )
 
 type UsersGetter interface {
@@ -13,7 +14,12 @@ type UsersGetter interface {
 
 type UserInterface interface {
 	Create(ctx context.Context, user *mu.User) (*mu.User, error)
+	Update(ctx context.Context, user *mu.User) (*mu.User, error)
+	Delete(ctx context.Context, name string) error
+	DeleteCollection(ctx context.Context, names []string) error
 	Get(ctx context.Context, name string) (*mu.User, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*mu.UserList, error)
+	ChangePassword(ctx context.Context, name string, req *mu.ChangePasswordRequest) error
 }
 
 type users struct {
@@ -39,6 +45,33 @@ func (u *users) Create(ctx context.Context, user *mu.User) (result *mu.User, err
 	return
 }
 
+func (u *users) Update(ctx context.Context, user *mu.User) (result *mu.User, err error) {
+	result = &mu.User{}
+	err = u.client.Put().
+		AbsPath("/v1/users/" + user.Name).
+		Body(user).
+		Do(ctx).
+		Into(result)
+
+	return
+}
+
+func (u *users) Delete(ctx context.Context, name string) error {
+	return u.client.Delete().
+		AbsPath("/v1/users/" + name).
+		Do(ctx).
+		Error()
+}
+
+func (u *users) DeleteCollection(ctx context.Context, names []string) error {
+	req := u.client.Delete().AbsPath("/v1/users")
+	for _, name := range names {
+		req = req.Param("name", name)
+	}
+
+	return req.Do(ctx).Error()
+}
+
 func (u *users) Get(ctx context.Context, name string) (result *mu.User, err error) {
 	result = &mu.User{}
 	err = u.client.Get().
@@ -48,3 +81,20 @@ func (u *users) Get(ctx context.Context, name string) (result *mu.User, err erro
 
 	return
 }
+
+func (u *users) List(ctx context.Context, opts metav1.ListOptions) (result *mu.UserList, err error) {
+	result = &mu.UserList{}
+	err = setListOptions(u.client.Get().AbsPath("/v1/users"), opts).
+		Do(ctx).
+		Into(result)
+
+	return
+}
+
+func (u *users) ChangePassword(ctx context.Context, name string, req *mu.ChangePasswordRequest) error {
+	return u.client.Put().
+		AbsPath("/v1/users/" + name + "/change-password").
+		Body(req).
+		Do(ctx).
+		Error()
+}
@@ -5,12 +5,24 @@
 
 package shutdown
 
-import "sync"
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
 
 type Callback interface {
 	OnShutdown(manager string) error
 }
 
+// ContextCallback 是可以感知关闭截止时间的 Callback，ctx 会在回调超时或整体关闭超时时被取消.
+type ContextCallback interface {
+	Callback
+	OnShutdownContext(ctx context.Context, manager string) error
+}
+
 // CallbackFunc 是一个辅助函数，用于自定义回调函数.
 type CallbackFunc func(string) error
 
@@ -19,6 +31,37 @@ func (f CallbackFunc) OnShutdown(manager string) error {
 	return f(manager)
 }
 
+// ContextCallbackFunc 是一个辅助函数，用于自定义可以感知截止时间的回调函数.
+type ContextCallbackFunc func(ctx context.Context, manager string) error
+
+// OnShutdown 使用不带截止时间的 ctx 执行回调.
+func (f ContextCallbackFunc) OnShutdown(manager string) error {
+	return f(context.Background(), manager)
+}
+
+// OnShutdownContext 是在 shutdown 触发时执行的方法.
+func (f ContextCallbackFunc) OnShutdownContext(ctx context.Context, manager string) error {
+	return f(ctx, manager)
+}
+
+// Priority 定义了回调的执行顺序，数值小的先执行，相同优先级的回调并发执行.
+type Priority int
+
+// 定义常用的关闭阶段.
+const (
+	// PriorityStopAccepting 停止接收新请求，例如让就绪检查失败.
+	PriorityStopAccepting Priority = 100
+	// PriorityDrain 等待 HTTP、gRPC 等服务中正在处理的请求完成.
+	PriorityDrain Priority = 200
+	// PriorityFlush 刷新 analytics 等缓冲中的数据.
+	PriorityFlush Priority = 300
+	// PriorityCloseStores 关闭数据库、缓存等存储连接.
+	PriorityCloseStores Priority = 400
+
+	// PriorityDefault 为 AddCallback 添加的回调使用的优先级.
+	PriorityDefault = PriorityDrain
+)
+
 type GSInterface interface {
 	AddManager(manager Manager)                // 添加 Shutdown Manager
 	AddCallback(callback Callback)             // 添加 Shutdown Callback
@@ -39,20 +82,43 @@ type ErrorHandler interface {
 	OnError(err error)
 }
 
+// ErrorFunc 是一个辅助函数，用于自定义 ErrorHandler.
+type ErrorFunc func(err error)
+
+// OnError 在 Manager 或 Callback 失败时调用.
+func (f ErrorFunc) OnError(err error) {
+	f(err)
+}
+
+type callbackEntry struct {
+	callback Callback
+	priority Priority
+	timeout  time.Duration
+}
+
 type GracefulShutdown struct {
-	callbacks    []Callback
+	callbacks    []callbackEntry
 	managers     []Manager
 	errorHandler ErrorHandler
+	timeout      time.Duration
+	done         chan struct{}
+	doneOnce     sync.Once
 }
 
 // New 初始化优雅关闭实例.
 func New() *GracefulShutdown {
 	return &GracefulShutdown{
-		callbacks: make([]Callback, 0, 10),
+		callbacks: make([]callbackEntry, 0, 10),
 		managers:  make([]Manager, 0, 3),
+		done:      make(chan struct{}),
 	}
 }
 
+// Done 返回一个在关闭流程结束后关闭的 channel，无论回调是否执行成功或因超时被跳过.
+func (gs *GracefulShutdown) Done() <-chan struct{} {
+	return gs.done
+}
+
 // Start 在所有已添加的 Manager 上启动监听.
 func (gs *GracefulShutdown) Start() error {
 	for _, manager := range gs.managers {
@@ -68,9 +134,23 @@ func (gs *GracefulShutdown) AddManager(manager Manager) {
 	gs.managers = append(gs.managers, manager)
 }
 
-// AddCallback 添加 Callback 以便在 shutdown 时调用.
+// AddCallback 添加 Callback 以便在 shutdown 时调用，使用 PriorityDefault 且没有单独的超时时间.
 func (gs *GracefulShutdown) AddCallback(callback Callback) {
-	gs.callbacks = append(gs.callbacks, callback)
+	gs.AddPriorityCallback(PriorityDefault, 0, callback)
+}
+
+// AddPriorityCallback 添加指定优先级的 Callback，timeout 大于 0 时限制该回调的最长执行时间.
+func (gs *GracefulShutdown) AddPriorityCallback(priority Priority, timeout time.Duration, callback Callback) {
+	gs.callbacks = append(gs.callbacks, callbackEntry{
+		callback: callback,
+		priority: priority,
+		timeout:  timeout,
+	})
+}
+
+// SetTimeout 设置整个关闭流程的截止时间，0 表示不限制.
+func (gs *GracefulShutdown) SetTimeout(timeout time.Duration) {
+	gs.timeout = timeout
 }
 
 // SetErrorHandler 设置 ErrorHandler 用于在 Manager 或 Callback 失败时调用.
@@ -79,21 +159,88 @@ func (gs *GracefulShutdown) SetErrorHandler(errorHandler ErrorHandler) {
 }
 
 // StartShutdown 用于执行指定 Manager 的 shutdown.
+// 回调按优先级分组依次执行，同组回调并发执行，整体超时后剩余的回调不再执行.
+// 所有回调结束后关闭 Done 返回的 channel，再调用 Manager 的 ShutdownFinish.
 func (gs *GracefulShutdown) StartShutdown(manager Manager) {
 	gs.ReportError(manager.ShutdownStart())
 
-	var wg sync.WaitGroup
-	for _, callback := range gs.callbacks {
-		wg.Add(1)
-		go func(callback Callback) {
-			defer wg.Done()
+	gs.runCallbacks(manager)
+	gs.doneOnce.Do(func() { close(gs.done) })
 
-			gs.ReportError(callback.OnShutdown(manager.GetName()))
-		}(callback)
+	gs.ReportError(manager.ShutdownFinish())
+}
+
+// runCallbacks 按优先级分组执行所有回调.
+func (gs *GracefulShutdown) runCallbacks(manager Manager) {
+	ctx := context.Background()
+	if gs.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, gs.timeout)
+		defer cancel()
 	}
-	wg.Wait()
 
-	gs.ReportError(manager.ShutdownFinish())
+	for _, group := range gs.groups() {
+		if ctx.Err() != nil {
+			gs.ReportError(fmt.Errorf("shutdown deadline exceeded, skip %d callbacks with priority %d",
+				len(group), group[0].priority))
+			continue
+		}
+
+		var wg sync.WaitGroup
+		for _, entry := range group {
+			wg.Add(1)
+			go func(entry callbackEntry) {
+				defer wg.Done()
+
+				gs.ReportError(gs.runCallback(ctx, entry, manager.GetName()))
+			}(entry)
+		}
+		wg.Wait()
+	}
+}
+
+// groups 将回调按优先级从小到大分组，同优先级保持添加顺序.
+func (gs *GracefulShutdown) groups() [][]callbackEntry {
+	entries := make([]callbackEntry, len(gs.callbacks))
+	copy(entries, gs.callbacks)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].priority < entries[j].priority
+	})
+
+	var groups [][]callbackEntry
+	for i, entry := range entries {
+		if i == 0 || entry.priority != entries[i-1].priority {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], entry)
+	}
+
+	return groups
+}
+
+// runCallback 执行单个回调，回调超时后不再等待其返回.
+func (gs *GracefulShutdown) runCallback(ctx context.Context, entry callbackEntry, manager string) error {
+	if entry.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, entry.timeout)
+		defer cancel()
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if cb, ok := entry.callback.(ContextCallback); ok {
+			errCh <- cb.OnShutdownContext(ctx, manager)
+			return
+		}
+		errCh <- entry.callback.OnShutdown(manager)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("shutdown callback with priority %d timed out: %w", entry.priority, ctx.Err())
+	}
 }
 
 // ReportError 用于向 ErrorHandler 报错.
@@ -0,0 +1,141 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testManager struct{}
+
+func (testManager) GetName() string            { return "test" }
+func (testManager) Start(gs GSInterface) error { return nil }
+func (testManager) ShutdownStart() error       { return nil }
+func (testManager) ShutdownFinish() error      { return nil }
+
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+	errs   []error
+}
+
+func (r *recorder) record(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) OnError(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, err)
+}
+
+func (r *recorder) callback(event string) Callback {
+	return CallbackFunc(func(string) error {
+		r.record(event)
+		return nil
+	})
+}
+
+func TestStartShutdown_Priority(t *testing.T) {
+	r := &recorder{}
+	gs := New()
+	gs.SetErrorHandler(r)
+
+	gs.AddPriorityCallback(PriorityCloseStores, 0, r.callback("close mysql"))
+	gs.AddPriorityCallback(PriorityFlush, 0, r.callback("flush analytics"))
+	gs.AddCallback(r.callback("drain http"))
+	gs.AddPriorityCallback(PriorityStopAccepting, 0, r.callback("not ready"))
+
+	gs.StartShutdown(testManager{})
+
+	want := []string{"not ready", "drain http", "flush analytics", "close mysql"}
+	if len(r.events) != len(want) {
+		t.Fatalf("events = %v, want %v", r.events, want)
+	}
+	for i := range want {
+		if r.events[i] != want[i] {
+			t.Fatalf("events = %v, want %v", r.events, want)
+		}
+	}
+	if len(r.errs) != 0 {
+		t.Errorf("errors = %v, want none", r.errs)
+	}
+}
+
+func TestStartShutdown_Timeout(t *testing.T) {
+	r := &recorder{}
+	gs := New()
+	gs.SetErrorHandler(r)
+	gs.SetTimeout(200 * time.Millisecond)
+
+	var deadline bool
+	gs.AddPriorityCallback(PriorityDrain, 20*time.Millisecond, ContextCallbackFunc(func(ctx context.Context, _ string) error {
+		_, deadline = ctx.Deadline()
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+	gs.AddPriorityCallback(PriorityDrain, 20*time.Millisecond, CallbackFunc(func(string) error {
+		time.Sleep(time.Second)
+		return nil
+	}))
+	gs.AddPriorityCallback(PriorityCloseStores, 0, r.callback("close mysql"))
+
+	start := time.Now()
+	gs.StartShutdown(testManager{})
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("shutdown took %s, callbacks were not bounded by their timeout", elapsed)
+	}
+
+	if !deadline {
+		t.Errorf("context callback did not receive a deadline")
+	}
+	if len(r.errs) != 2 {
+		t.Fatalf("errors = %v, want 2", r.errs)
+	}
+	for _, err := range r.errs {
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("error = %v, want deadline exceeded", err)
+		}
+	}
+	if len(r.events) != 1 || r.events[0] != "close mysql" {
+		t.Errorf("events = %v, want [close mysql]", r.events)
+	}
+}
+
+func TestStartShutdown_OverallDeadline(t *testing.T) {
+	r := &recorder{}
+	gs := New()
+	gs.SetErrorHandler(r)
+	gs.SetTimeout(20 * time.Millisecond)
+
+	gs.AddPriorityCallback(PriorityDrain, 0, CallbackFunc(func(string) error {
+		time.Sleep(100 * time.Millisecond)
+		return nil
+	}))
+	gs.AddPriorityCallback(PriorityCloseStores, 0, r.callback("close mysql"))
+
+	gs.StartShutdown(testManager{})
+
+	if len(r.events) != 0 {
+		t.Errorf("events = %v, want callbacks after the deadline skipped", r.events)
+	}
+	// 一个是 drain 回调超时，一个是跳过 close 回调
+	if len(r.errs) != 2 {
+		t.Errorf("errors = %v, want 2", r.errs)
+	}
+	// 回调被跳过时也要通知等待关闭的调用方
+	select {
+	case <-gs.Done():
+	default:
+		t.Errorf("Done() is not closed after shutdown")
+	}
+}
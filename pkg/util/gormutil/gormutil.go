@@ -0,0 +1,33 @@
+// Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file. The original repo for
+// this file is https://github.com/changaolee/skeleton.
+
+package gormutil
+
+// DefaultLimit 定义了未指定 limit 时返回记录的默认最大数量.
+const DefaultLimit = 1000
+
+// LimitAndOffset 包含了分页查询时需要的 offset 和 limit.
+type LimitAndOffset struct {
+	Offset int
+	Limit  int
+}
+
+// Unpointer 将指针形式的 offset 和 limit 转换为 LimitAndOffset，未指定时使用默认值.
+func Unpointer(offset *int64, limit *int64) *LimitAndOffset {
+	var o, l int = 0, DefaultLimit
+
+	if offset != nil {
+		o = int(*offset)
+	}
+
+	if limit != nil {
+		l = int(*limit)
+	}
+
+	return &LimitAndOffset{
+		Offset: o,
+		Limit:  l,
+	}
+}
@@ -53,8 +53,12 @@ readonly SKT_CONFIG_DIR=${SKT_CONFIG_DIR:-/etc/skt}        # skt 配置文件存
 readonly SKT_LOG_DIR=${SKT_LOG_DIR:-/var/log/skt}          # skt 日志文件存放目录
 readonly CA_FILE=${CA_FILE:-${SKT_CONFIG_DIR}/cert/ca.pem} # CA
 
+# skt-apiserver 与 skt-authz-server 之间变更通知的 HMAC 签名密钥
+readonly SKT_NOTIFICATION_SIGNING_KEY=${SKT_NOTIFICATION_SIGNING_KEY:-'kmB3MKqDa5qYc5K0Vd7Ou8bYRYbJp2Xk'}
+
 # skt-apiserver 配置
 readonly SKT_APISERVER_HOST=${SKT_APISERVER_HOST:-127.0.0.1} # skt-apiserver 部署机器 IP 地址
+readonly SKT_APISERVER_CORS_ALLOW_ORIGINS=${SKT_APISERVER_CORS_ALLOW_ORIGINS:-} # 允许跨域访问 skt-apiserver 的源，例如控制台地址
 readonly SKT_APISERVER_GRPC_BIND_ADDRESS=${SKT_APISERVER_GRPC_BIND_ADDRESS:-0.0.0.0}
 readonly SKT_APISERVER_GRPC_BIND_PORT=${SKT_APISERVER_GRPC_BIND_PORT:-8081}
 readonly SKT_APISERVER_INSECURE_BIND_ADDRESS=${SKT_APISERVER_INSECURE_BIND_ADDRESS:-127.0.0.1}
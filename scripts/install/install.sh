@@ -15,6 +15,7 @@ source "${SKT_ROOT}/scripts/install/mongodb.sh"
 source "${SKT_ROOT}/scripts/install/skt-apiserver.sh"
 source "${SKT_ROOT}/scripts/install/skt-authz-server.sh"
 source "${SKT_ROOT}/scripts/install/sktctl.sh"
+source "${SKT_ROOT}/scripts/install/skt-pump.sh"
 
 # 准备 Linux 环境
 skt::install::prepare_linux() {
@@ -304,9 +305,9 @@ function skt::install::install_skeleton() {
   # 5. 安装 sktctl 客户端工具
   skt::sktctl::install || return 1
 
-  #  # 6. 安装 skt-pump 服务
-  #  skt::pump::install || return 1
-  #
+  # 6. 安装 skt-pump 服务
+  skt::pump::install || return 1
+
   #  # 7. 安装 man page
   #  skt::man::install || return 1
 
@@ -15,6 +15,7 @@ function skt::apiserver::info() {
   cat <<EOF
 skt-apserver insecure listen on: ${SKT_APISERVER_HOST}:${SKT_APISERVER_INSECURE_BIND_PORT}
 skt-apserver secure listen on: ${SKT_APISERVER_HOST}:${SKT_APISERVER_SECURE_BIND_PORT}
+skt-apserver grpc listen on: ${SKT_APISERVER_HOST}:${SKT_APISERVER_GRPC_BIND_PORT}
 EOF
 }
 
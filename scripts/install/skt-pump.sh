@@ -0,0 +1,70 @@
+#!/usr/bin/env bash
+
+# Copyright 2023 lichangao(李长傲) <changao.li.work@outlook.com>. All rights reserved.
+# Use of this source code is governed by a MIT style
+# license that can be found in the LICENSE file. The original repo for
+# this file is https://github.com/changaolee/skeleton.
+
+
+# The root of the build/dist directory
+SKT_ROOT=$(dirname "${BASH_SOURCE[0]}")/../..
+[[ -z ${COMMON_SOURCED} ]] && source ${SKT_ROOT}/scripts/install/common.sh
+
+# 安装后打印必要的信息
+function skt::pump::info() {
+  cat <<EOF
+skt-pump audit log: ${SKT_LOG_DIR}/skt-pump-audit.log
+EOF
+}
+
+# 安装
+function skt::pump::install() {
+  pushd ${SKT_ROOT}
+
+  # 1. 构建 skt-pump
+  make build BINS=skt-pump
+  skt::common::sudo "cp ${LOCAL_OUTPUT_ROOT}/platforms/linux/amd64/skt-pump ${SKT_INSTALL_DIR}/bin"
+
+  # 2. 生成并安装 skt-pump 的配置文件（skt-pump.yaml）
+  echo ${LINUX_PASSWORD} | sudo -S bash -c \
+    "./scripts/genconfig.sh ${ENV_FILE} configs/skt-pump.yaml > ${SKT_CONFIG_DIR}/skt-pump.yaml"
+
+  # 3. 创建并安装 skt-pump systemd unit 文件
+  echo ${LINUX_PASSWORD} | sudo -S bash -c \
+    "./scripts/genconfig.sh ${ENV_FILE} init/skt-pump.service > /etc/systemd/system/skt-pump.service"
+
+  # 4. 启动 skt-pump 服务
+  skt::common::sudo "systemctl daemon-reload"
+  skt::common::sudo "systemctl restart skt-pump"
+  skt::common::sudo "systemctl enable skt-pump"
+  skt::pump::status || return 1
+  skt::pump::info
+
+  skt::log::info "install skt-pump successfully"
+  popd
+}
+
+# 卸载
+function skt::pump::uninstall() {
+  set +o errexit
+  skt::common::sudo "systemctl stop skt-pump"
+  skt::common::sudo "systemctl disable skt-pump"
+  skt::common::sudo "rm -f ${SKT_INSTALL_DIR}/bin/skt-pump"
+  skt::common::sudo "rm -f ${SKT_CONFIG_DIR}/skt-pump.yaml"
+  skt::common::sudo "rm -f /etc/systemd/system/skt-pump.service"
+  set -o errexit
+  skt::log::info "uninstall skt-pump successfully"
+}
+
+# 状态检查
+function skt::pump::status() {
+  # 查看 skt-pump 运行状态，如果输出中包含 active (running) 字样说明 skt-pump 成功启动。
+  systemctl status skt-pump | grep -q 'active' || {
+    skt::log::error "skt-pump failed to start, maybe not installed properly"
+    return 1
+  }
+}
+
+if [[ "$*" =~ skt::pump:: ]]; then
+  eval $*
+fi